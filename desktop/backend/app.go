@@ -10,12 +10,17 @@ import (
 	"log"
 	"os"
 	"sync"
+	"time"
 
 	fsConfig "github.com/rclone/rclone/fs/config"
 	"github.com/rclone/rclone/fs/config/configfile"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
+// HistoryRecorder persists the outcome of a finished sync run. statsCtx is the
+// rclone task context holding the run's accounting stats (nil if it was never created).
+type HistoryRecorder func(statsCtx context.Context, profileName, action, status string, startTime time.Time, taskErr error)
+
 // App struct - now implements Wails v3 service interface
 type App struct {
 	app            *application.App
@@ -26,6 +31,7 @@ type App struct {
 	initialized    bool
 	initMutex      sync.Mutex
 	cachedRemotes  []fsConfig.Remote
+	recordHistory  HistoryRecorder
 }
 
 // NewApp creates a new App application struct
@@ -50,6 +56,11 @@ func (a *App) SetApp(app *application.App) {
 	a.app = app
 }
 
+// SetHistoryRecorder sets the callback used to record finished tab syncs in history
+func (a *App) SetHistoryRecorder(recorder HistoryRecorder) {
+	a.recordHistory = recorder
+}
+
 //go:embed .env
 var envConfigStr string
 
@@ -28,6 +28,7 @@ func (a *App) Sync(task string, profile models.Profile) int {
 
 func (a *App) SyncWithTab(task string, profile models.Profile, tabId string) int {
 	id := time.Now().Nanosecond()
+	startTime := time.Now()
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -57,9 +58,16 @@ func (a *App) SyncWithTab(task string, profile models.Profile, tabId string) int
 		}
 		a.oc <- j
 		cancel()
+		if a.recordHistory != nil {
+			a.recordHistory(nil, profile.Name, task, "failed", startTime, err)
+		}
 		return 0
 	}
 
+	if !profile.DryRun {
+		ctx = rclone.WithTransferJournal(ctx, rclone.NewTransferJournal(rclone.JournalSync))
+	}
+
 	outStatus := make(chan *dto.SyncStatusDTO, 100)
 	var outStatusClosed bool
 	var outStatusMutex sync.Mutex
@@ -81,6 +89,15 @@ func (a *App) SyncWithTab(task string, profile models.Profile, tabId string) int
 		utils.AddTabMapping(id, tabId)
 	}
 
+	ticket := utils.SubmitTask(ctx, utils.QueueEntry{
+		Kind:    "legacy",
+		TaskId:  id,
+		TabId:   tabId,
+		Name:    profile.Name,
+		Action:  task,
+		Remotes: rclone.TaskRemotes(profile.From, profile.To),
+	}, cancel)
+
 	go func() {
 		for status := range outStatus {
 			// Enrich DTO with task identity
@@ -113,20 +130,35 @@ func (a *App) SyncWithTab(task string, profile models.Profile, tabId string) int
 	}()
 
 	go func() {
-		switch task {
-		case "pull":
-			err = rclone.Sync(ctx, config, "pull", profile, outStatus)
-		case "push":
-			err = rclone.Sync(ctx, config, "push", profile, outStatus)
-		case "bi":
-			err = rclone.BiSync(ctx, config, profile, false, outStatus)
-		case "bi-resync":
-			err = rclone.BiSync(ctx, config, profile, true, outStatus)
+		defer ticket.Release()
+
+		// Wait for the task queue to start the sync
+		if err = ticket.Wait(ctx); err == nil {
+			switch task {
+			case "pull":
+				err = rclone.Sync(ctx, config, "pull", profile, outStatus)
+			case "push":
+				err = rclone.Sync(ctx, config, "push", profile, outStatus)
+			case "bi":
+				err = rclone.BiSync(ctx, config, profile, false, outStatus)
+			case "bi-resync":
+				err = rclone.BiSync(ctx, config, profile, true, outStatus)
+			}
 		}
 
 		// Close the outStatus channel to unblock the reader goroutine
 		closeOutStatus()
 
+		if a.recordHistory != nil {
+			status := "completed"
+			if ctx.Err() != nil {
+				status = "cancelled"
+			} else if err != nil {
+				status = "failed"
+			}
+			a.recordHistory(ctx, profile.Name, task, status, startTime, err)
+		}
+
 		if err != nil {
 			var j []byte
 			if tabId != "" {
@@ -250,8 +282,8 @@ func (a *App) addOAuthRemote(ctx context.Context, remoteName string, remoteType
 	}
 
 	// For OAuth providers, we need to handle the authentication flow
-	switch remoteType {
-	case "drive", "dropbox", "onedrive", "box", "yandex", "gphotos":
+	switch provider, _ := rclone.FindProvider(remoteType); {
+	case provider.OAuth:
 		// These providers require OAuth authentication
 		// Use auto config for desktop applications
 		configParams["config_is_local"] = "true"
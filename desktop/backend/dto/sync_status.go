@@ -25,9 +25,10 @@ type SyncStatusDTO struct {
 	Renames         int64     `json:"renames"`
 	Timestamp       time.Time `json:"timestamp"`
 	ElapsedTime     string    `json:"elapsed_time"`
-	Action          string             `json:"action"`                      // "pull", "push", "bi", "bi-resync"
+	Action          string             `json:"action"`                      // "pull", "push", "bi", "bi-resync", "copy", "move", "check"
 	LogMessages     []string           `json:"log_messages,omitempty"`      // Captured rclone log messages since last emission
 	Transfers       []FileTransferInfo `json:"transfers,omitempty"`         // Per-file transfer info
+	BandwidthLimit  string             `json:"bandwidth_limit,omitempty"`   // Limit in force, shared by running tasks, e.g. "1Mi" or "2Mi:off"; empty = unlimited
 }
 
 // FileTransferInfo represents a single file's transfer status
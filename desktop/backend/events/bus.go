@@ -135,3 +135,8 @@ func (b *WailsEventBus) EmitCryptEvent(event *CryptEvent) error {
 func (b *WailsEventBus) EmitBoardEvent(event *BoardEvent) error {
 	return b.Emit(event)
 }
+
+// EmitFlowEvent is a convenience method for flow events
+func (b *WailsEventBus) EmitFlowEvent(event *FlowEvent) error {
+	return b.Emit(event)
+}
@@ -12,6 +12,9 @@ const (
 	SyncCompleted EventType = "sync:completed"
 	SyncFailed    EventType = "sync:failed"
 	SyncCancelled EventType = "sync:cancelled"
+	SyncPaused    EventType = "sync:paused"
+	SyncResumed   EventType = "sync:resumed"
+	SyncQueued    EventType = "sync:queued"
 
 	// Config Events
 	ConfigUpdated  EventType = "config:updated"
@@ -39,6 +42,9 @@ const (
 	OperationProgress  EventType = "operation:progress"
 	OperationCompleted EventType = "operation:completed"
 	OperationFailed    EventType = "operation:failed"
+	OperationPaused    EventType = "operation:paused"
+	OperationResumed   EventType = "operation:resumed"
+	OperationQueued    EventType = "operation:queued"
 
 	// File Browser Events
 	FileBrowserResult EventType = "filebrowser:result"
@@ -48,6 +54,9 @@ const (
 	ScheduleUpdated   EventType = "schedule:updated"
 	ScheduleDeleted   EventType = "schedule:deleted"
 	ScheduleTriggered EventType = "schedule:triggered"
+	ScheduleCompleted EventType = "schedule:completed"
+	ScheduleFailed    EventType = "schedule:failed"
+	ScheduleSkipped   EventType = "schedule:skipped"
 
 	// History Events
 	HistoryAdded   EventType = "history:added"
@@ -56,6 +65,7 @@ const (
 	// Crypt Events
 	CryptRemoteCreated EventType = "crypt:created"
 	CryptRemoteDeleted EventType = "crypt:deleted"
+	CryptRemoteRotated EventType = "crypt:rotated"
 
 	// Board Events
 	BoardUpdated            EventType = "board:updated"
@@ -64,6 +74,21 @@ const (
 	BoardExecutionCompleted EventType = "board:execution:completed"
 	BoardExecutionFailed    EventType = "board:execution:failed"
 	BoardExecutionCancelled EventType = "board:execution:cancelled"
+	BoardExecutionPaused    EventType = "board:execution:paused"
+	BoardExecutionResumed   EventType = "board:execution:resumed"
+
+	// Flow Events
+	FlowExecutionStarted   EventType = "flow:execution:started"
+	FlowExecutionProgress  EventType = "flow:execution:progress"
+	FlowExecutionCompleted EventType = "flow:execution:completed"
+	FlowExecutionFailed    EventType = "flow:execution:failed"
+	FlowExecutionCancelled EventType = "flow:execution:cancelled"
+
+	// Recovery Events
+	TasksInterrupted EventType = "tasks:interrupted"
+
+	// Queue Events
+	QueueUpdated EventType = "queue:updated"
 )
 
 // BaseEvent represents the base structure for all events
@@ -324,6 +349,29 @@ func NewBoardEvent(eventType EventType, boardId, edgeId, status, message string)
 	}
 }
 
+// FlowEvent represents flow execution events
+type FlowEvent struct {
+	BaseEvent
+	FlowId      string `json:"flowId"`
+	OperationId string `json:"operationId,omitempty"`
+	Status      string `json:"status"`
+	Message     string `json:"message,omitempty"`
+}
+
+// NewFlowEvent creates a new flow event
+func NewFlowEvent(eventType EventType, flowId, operationId, status, message string) *FlowEvent {
+	return &FlowEvent{
+		BaseEvent: BaseEvent{
+			Type:      eventType,
+			Timestamp: time.Now(),
+		},
+		FlowId:      flowId,
+		OperationId: operationId,
+		Status:      status,
+		Message:     message,
+	}
+}
+
 // CryptEvent represents encryption-related events
 type CryptEvent struct {
 	BaseEvent
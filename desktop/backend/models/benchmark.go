@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// BenchmarkConfig describes a remote benchmark. Empty fields use the defaults.
+type BenchmarkConfig struct {
+	Remote             string   `json:"remote"`                         // remote path; a temporary directory is created and removed inside it
+	FileSizes          []string `json:"file_sizes,omitempty"`           // rclone size suffixes, default 64K, 1M and 8M
+	FilesPerSize       int      `json:"files_per_size,omitempty"`       // files per size and parallelism level, default 8
+	Parallel           []int    `json:"parallel,omitempty"`             // concurrent transfers to compare, default 1, 4 and 8
+	MultiThreadStreams *int     `json:"multi_thread_streams,omitempty"` // streams per large file transfer, as in profiles
+	BufferSize         string   `json:"buffer_size,omitempty"`          // in-memory buffer per transfer, as in profiles
+}
+
+// BenchmarkLatency summarizes the duration of individual requests in milliseconds
+type BenchmarkLatency struct {
+	Min float64 `json:"min_ms"`
+	Avg float64 `json:"avg_ms"`
+	P50 float64 `json:"p50_ms"`
+	P95 float64 `json:"p95_ms"`
+	Max float64 `json:"max_ms"`
+}
+
+// BenchmarkPhase measures the uploads or downloads of one file size at one parallelism
+type BenchmarkPhase struct {
+	Requests   int              `json:"requests"`
+	Errors     int              `json:"errors"`
+	ErrorRate  float64          `json:"error_rate"` // errors / requests
+	Bytes      int64            `json:"bytes"`      // bytes of the successful requests
+	Throughput float64          `json:"throughput"` // bytes per second over the whole phase
+	Latency    BenchmarkLatency `json:"latency"`
+}
+
+// BenchmarkResult is the measurement of one file size at one parallelism level
+type BenchmarkResult struct {
+	FileSize int64          `json:"file_size"`
+	Parallel int            `json:"parallel"`
+	Upload   BenchmarkPhase `json:"upload"`
+	Download BenchmarkPhase `json:"download"`
+}
+
+// BenchmarkRun is a stored remote benchmark
+type BenchmarkRun struct {
+	Id        string            `json:"id"`
+	Remote    string            `json:"remote"`
+	Config    BenchmarkConfig   `json:"config"`
+	Results   []BenchmarkResult `json:"results"`
+	Status    string            `json:"status"` // "completed", "failed", "cancelled"
+	Message   string            `json:"message,omitempty"`
+	StartedAt time.Time         `json:"started_at"`
+	Duration  string            `json:"duration"`
+}
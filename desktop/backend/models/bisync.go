@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// BisyncState describes what is known about a bisync pair: the bookkeeping stored by
+// ns-drive and the listing and lock files rclone keeps in its bisync working directory.
+// Path1 of the listings is the destination (To), path2 the source (From).
+type BisyncState struct {
+	From          string     `json:"from"`
+	To            string     `json:"to"`
+	ProfileName   string     `json:"profile_name"` // profile of the most recent run
+	FilterHash    string     `json:"filter_hash"`  // hash of the include/exclude rules of the last resync
+	ResyncPending bool       `json:"resync_pending"`
+	LastRunAt     *time.Time `json:"last_run_at,omitempty"`
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+	LastResyncAt  *time.Time `json:"last_resync_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+
+	Workdir         string     `json:"workdir"`
+	ListingPath1    string     `json:"listing_path1"`
+	ListingPath2    string     `json:"listing_path2"`
+	ListingsPresent bool       `json:"listings_present"`
+	LockFile        string     `json:"lock_file"`
+	Locked          bool       `json:"locked"`
+	LockPID         string     `json:"lock_pid,omitempty"`
+	LockExpiresAt   *time.Time `json:"lock_expires_at,omitempty"`
+	LockStale       bool       `json:"lock_stale"` // expired, or left behind by another process
+	InspectError    string     `json:"inspect_error,omitempty"`
+}
+
+// ConflictVersion is one side's version of a conflicting file after bisync renamed it.
+// Both versions exist on both remotes once the run has finished.
+type ConflictVersion struct {
+	Path    string    `json:"path"` // current path relative to the pair's roots
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Hash    string    `json:"hash,omitempty"`
+}
+
+// BisyncConflict is a file that was changed on both sides of a bisync pair
+type BisyncConflict struct {
+	Id          string          `json:"id"`
+	ProfileName string          `json:"profile_name"`
+	From        string          `json:"from"`
+	To          string          `json:"to"`
+	Path        string          `json:"path"`   // original path of the file
+	Winner      string          `json:"winner"` // "source", "destination", "none", "unknown"
+	Source      ConflictVersion `json:"source"`
+	Destination ConflictVersion `json:"destination"`
+	Status      string          `json:"status"`               // "open", "resolved"
+	Resolution  string          `json:"resolution,omitempty"` // "keep_source", "keep_destination", "keep_both"
+	DetectedAt  time.Time       `json:"detected_at"`
+	ResolvedAt  *time.Time      `json:"resolved_at,omitempty"`
+}
+
+// Conflict resolutions
+const (
+	ConflictKeepSource      = "keep_source"
+	ConflictKeepDestination = "keep_destination"
+	ConflictKeepBoth        = "keep_both"
+)
@@ -17,7 +17,7 @@ type BoardEdge struct {
 	Id         string  `json:"id"`
 	SourceId   string  `json:"source_id"`
 	TargetId   string  `json:"target_id"`
-	Action     string  `json:"action"` // "pull","push","bi","bi-resync"
+	Action     string  `json:"action"` // "pull","push","bi","bi-resync","copy","move","check"
 	SyncConfig Profile `json:"sync_config"`
 }
 
@@ -42,7 +42,7 @@ type Board struct {
 // BoardExecutionStatus represents the status of a running board flow
 type BoardExecutionStatus struct {
 	BoardId      string                `json:"board_id"`
-	Status       string                `json:"status"` // "running","completed","failed","cancelled"
+	Status       string                `json:"status"` // "running","paused","completed","failed","cancelled"
 	EdgeStatuses []EdgeExecutionStatus `json:"edge_statuses"`
 	StartTime    time.Time             `json:"start_time"`
 	EndTime      *time.Time            `json:"end_time,omitempty"`
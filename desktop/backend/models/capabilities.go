@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// RemoteCapabilities describes what a remote supports, as declared by its backend
+type RemoteCapabilities struct {
+	Remote           string   `json:"remote"`
+	Type             string   `json:"type"`   // rclone backend name
+	Hashes           []string `json:"hashes"` // hash types the remote can provide, e.g. "md5"
+	ModTimeSupported bool     `json:"mod_time_supported"`
+	ModTimePrecision string   `json:"mod_time_precision,omitempty"` // e.g. "1ns", "1s"
+	ServerSideCopy   bool     `json:"server_side_copy"`
+	ServerSideMove   bool     `json:"server_side_move"`
+	DirMove          bool     `json:"dir_move"` // directories can be renamed server side
+	ListR            bool     `json:"list_r"`   // recursive listing, used by fast list
+	About            bool     `json:"about"`    // quota information
+	PublicLink       bool     `json:"public_link"`
+	CleanUp          bool     `json:"clean_up"` // trash can be emptied
+	Purge            bool     `json:"purge"`    // directories can be deleted with their contents in one call
+	CaseInsensitive  bool     `json:"case_insensitive"`
+	EmptyDirs        bool     `json:"empty_dirs"` // empty directories can exist
+}
+
+// ProbeStep is the outcome of one step of a remote probe
+type ProbeStep struct {
+	Name     string `json:"name"` // "write", "stat", "read", "hash", "delete"
+	Ok       bool   `json:"ok"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+// RemoteProbe is the behaviour of a remote measured by writing, reading, stating and
+// deleting a temporary object
+type RemoteProbe struct {
+	Remote           string             `json:"remote"`
+	Capabilities     RemoteCapabilities `json:"capabilities"`
+	Steps            []ProbeStep        `json:"steps"`
+	Writable         bool               `json:"writable"`
+	ModTimePreserved bool               `json:"mod_time_preserved"` // the written modtime was read back within the declared precision
+	ModTimeDrift     string             `json:"mod_time_drift,omitempty"`
+	VerifiedHashes   []string           `json:"verified_hashes"` // hashes the remote returned correctly
+	Ok               bool               `json:"ok"`              // every step succeeded
+	ProbedAt         time.Time          `json:"probed_at"`
+}
@@ -0,0 +1,34 @@
+package models
+
+// CheckReport is the structured result of comparing a source with a destination
+type CheckReport struct {
+	Match        []string       `json:"match"`
+	Differ       []string       `json:"differ"`
+	MissingOnSrc []string       `json:"missing_on_src"` // only on destination
+	MissingOnDst []string       `json:"missing_on_dst"` // only on source
+	Errors       []string       `json:"errors"`
+	HashType     string         `json:"hash_type"` // common hash used, "none" if only sizes were compared
+	Download     bool           `json:"download"`  // contents were compared by downloading
+	InSync       bool           `json:"in_sync"`
+	Tree         *CheckTreeNode `json:"tree"`
+}
+
+// CheckTreeNode rolls up check results per directory. The root node has an empty path.
+type CheckTreeNode struct {
+	Name         string           `json:"name"`
+	Path         string           `json:"path"`
+	IsDir        bool             `json:"is_dir"`
+	Status       string           `json:"status,omitempty"` // files only: "match","differ","missing_on_src","missing_on_dst","error"
+	Match        int              `json:"match"`
+	Differ       int              `json:"differ"`
+	MissingOnSrc int              `json:"missing_on_src"`
+	MissingOnDst int              `json:"missing_on_dst"`
+	Errors       int              `json:"errors"`
+	InSync       bool             `json:"in_sync"`
+	Children     []*CheckTreeNode `json:"children,omitempty"`
+}
+
+// Differences counts the paths that are not identical on both sides
+func (r *CheckReport) Differences() int {
+	return len(r.Differ) + len(r.MissingOnSrc) + len(r.MissingOnDst) + len(r.Errors)
+}
@@ -0,0 +1,8 @@
+package models
+
+// CryptName maps an encrypted object name of a crypt remote to its plaintext name
+type CryptName struct {
+	Encrypted string `json:"encrypted"`
+	Decrypted string `json:"decrypted,omitempty"`
+	Error     string `json:"error,omitempty"` // set when the name can't be decrypted with the remote's keys
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Dedupe modes, matching rclone's --dedupe-mode values (interactive is not supported)
+const (
+	DedupeSkip     = "skip"     // report duplicates, change nothing
+	DedupeFirst    = "first"    // keep the first listed file
+	DedupeNewest   = "newest"   // keep the most recently modified file
+	DedupeOldest   = "oldest"   // keep the least recently modified file
+	DedupeLargest  = "largest"  // keep the largest file
+	DedupeSmallest = "smallest" // keep the smallest file
+	DedupeRename   = "rename"   // give every duplicate a unique name
+	DedupeList     = "list"     // return the duplicate groups without changing anything
+)
+
+// DuplicateEntry is one of several files or directories sharing a name (or hash)
+type DuplicateEntry struct {
+	Path    string    `json:"path"`
+	Id      string    `json:"id,omitempty"` // backend object ID, tells same-name entries apart
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Hash    string    `json:"hash,omitempty"` // "type:value"
+}
+
+// DuplicateGroup is a set of entries that share a key
+type DuplicateGroup struct {
+	Key     string           `json:"key"` // path, or hash when grouping by hash
+	IsDir   bool             `json:"is_dir"`
+	Entries []DuplicateEntry `json:"entries"`
+}
+
+// DedupeReport lists the duplicate groups found on a remote
+type DedupeReport struct {
+	Remote   string           `json:"remote"`
+	ByHash   bool             `json:"by_hash"`
+	HashType string           `json:"hash_type"`
+	Groups   []DuplicateGroup `json:"groups"`
+}
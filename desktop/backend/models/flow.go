@@ -1,16 +1,19 @@
 package models
 
+import "time"
+
 // Flow represents a sync workflow containing sequential operations
 type Flow struct {
-	Id              string      `json:"id"`
-	Name            string      `json:"name"`
-	IsCollapsed     bool        `json:"is_collapsed"`
-	ScheduleEnabled bool        `json:"schedule_enabled"`
-	CronExpr        string      `json:"cron_expr,omitempty"`
-	SortOrder       int         `json:"sort_order"`
-	Operations      []Operation `json:"operations"`
-	CreatedAt       string      `json:"created_at,omitempty"`
-	UpdatedAt       string      `json:"updated_at,omitempty"`
+	Id                string      `json:"id"`
+	Name              string      `json:"name"`
+	IsCollapsed       bool        `json:"is_collapsed"`
+	ScheduleEnabled   bool        `json:"schedule_enabled"`
+	CronExpr          string      `json:"cron_expr,omitempty"`
+	ContinueOnFailure bool        `json:"continue_on_failure"` // run the remaining operations after one fails
+	SortOrder         int         `json:"sort_order"`
+	Operations        []Operation `json:"operations"`
+	CreatedAt         string      `json:"created_at,omitempty"`
+	UpdatedAt         string      `json:"updated_at,omitempty"`
 }
 
 // Operation represents a single sync operation between two remotes
@@ -22,7 +25,26 @@ type Operation struct {
 	TargetRemote string  `json:"target_remote"`
 	TargetPath   string  `json:"target_path"`
 	Action       string  `json:"action"`
-	SyncConfig   Profile `json:"sync_config"`  // JSON-serialized Profile with all rclone options
+	SyncConfig   Profile `json:"sync_config"` // JSON-serialized Profile with all rclone options
 	IsExpanded   bool    `json:"is_expanded"`
 	SortOrder    int     `json:"sort_order"`
 }
+
+// FlowExecutionStatus represents the status of a running flow
+type FlowExecutionStatus struct {
+	FlowId     string                     `json:"flow_id"`
+	Status     string                     `json:"status"` // "running","completed","failed","cancelled"
+	Operations []OperationExecutionStatus `json:"operations"`
+	StartTime  time.Time                  `json:"start_time"`
+	EndTime    *time.Time                 `json:"end_time,omitempty"`
+}
+
+// OperationExecutionStatus represents the status of one operation in a flow execution
+type OperationExecutionStatus struct {
+	OperationId string     `json:"operation_id"`
+	Status      string     `json:"status"` // "pending","running","completed","failed","skipped","cancelled"
+	Message     string     `json:"message,omitempty"`
+	TaskId      int        `json:"task_id,omitempty"`
+	StartTime   *time.Time `json:"start_time,omitempty"`
+	EndTime     *time.Time `json:"end_time,omitempty"`
+}
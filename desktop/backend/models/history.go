@@ -14,7 +14,38 @@ type HistoryEntry struct {
 	FilesTransferred int64     `json:"files_transferred"`
 	BytesTransferred int64     `json:"bytes_transferred"`
 	Errors           int       `json:"errors"`
+	Checks           int64     `json:"checks"`
+	Deletes          int64     `json:"deletes"`
+	Renames          int64     `json:"renames"`
 	ErrorMessage     string    `json:"error_message,omitempty"`
+
+	// Journal holds the per-file outcomes of the run; it is stored separately
+	// in the transfer journal table and not returned with the entry.
+	Journal []TransferRecord `json:"-"`
+}
+
+// TransferRecord is the outcome of a single file within a history run
+type TransferRecord struct {
+	Id          int64     `json:"id"`
+	RunId       string    `json:"run_id"`
+	ProfileName string    `json:"profile_name"`
+	Action      string    `json:"action"`
+	Path        string    `json:"path"`
+	Outcome     string    `json:"outcome"` // "copied", "updated", "deleted", "renamed", "moved", "failed"
+	Size        int64     `json:"size"`
+	Error       string    `json:"error,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// TransferQuery filters transfer journal records. Empty fields match everything.
+type TransferQuery struct {
+	RunId       string `json:"run_id"`
+	ProfileName string `json:"profile_name"`
+	Path        string `json:"path"` // exact path match
+	PathPrefix  string `json:"path_prefix"`
+	Outcome     string `json:"outcome"`
+	Limit       int    `json:"limit"`
+	Offset      int    `json:"offset"`
 }
 
 // AggregateStats contains summary statistics across all history entries
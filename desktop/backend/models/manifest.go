@@ -0,0 +1,15 @@
+package models
+
+// ManifestReport is the result of verifying a remote path against a checksum manifest
+type ManifestReport struct {
+	Remote   string   `json:"remote"`
+	Manifest string   `json:"manifest"`
+	HashType string   `json:"hash_type"`
+	Download bool     `json:"download"` // files were re-hashed by downloading them
+	Match    []string `json:"match"`
+	Changed  []string `json:"changed"` // hash differs from the manifest
+	Missing  []string `json:"missing"` // in the manifest, not on the remote
+	Extra    []string `json:"extra"`   // on the remote, not in the manifest
+	Errors   []string `json:"errors"`
+	Intact   bool     `json:"intact"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// PlanChangeKind identifies what a planned change does to the destination
+type PlanChangeKind string
+
+const (
+	PlanCopy   PlanChangeKind = "copy"   // file missing on destination
+	PlanUpdate PlanChangeKind = "update" // file differs on destination
+	PlanDelete PlanChangeKind = "delete" // file missing on source
+	PlanRename PlanChangeKind = "rename" // destination file moved to a new path
+)
+
+// PlanChange is a single file change in a sync plan
+type PlanChange struct {
+	Kind    PlanChangeKind `json:"kind"`
+	Path    string         `json:"path"`           // destination-relative path (new path for renames)
+	From    string         `json:"from,omitempty"` // previous destination path for renames
+	Size    int64          `json:"size"`
+	ModTime time.Time      `json:"mod_time"` // source mod time (destination mod time for deletes)
+}
+
+// PlanSummary totals the changes of a plan by kind
+type PlanSummary struct {
+	CopyCount   int   `json:"copy_count"`
+	CopyBytes   int64 `json:"copy_bytes"`
+	UpdateCount int   `json:"update_count"`
+	UpdateBytes int64 `json:"update_bytes"`
+	DeleteCount int   `json:"delete_count"`
+	DeleteBytes int64 `json:"delete_bytes"`
+	RenameCount int   `json:"rename_count"`
+	RenameBytes int64 `json:"rename_bytes"`
+}
+
+// SyncPlan is a persisted, reviewable change set for a one-way sync
+type SyncPlan struct {
+	Id          string       `json:"id"`
+	ProfileName string       `json:"profile_name"`
+	Action      string       `json:"action"` // "push", "pull", "copy"
+	Profile     Profile      `json:"profile"`
+	BoardId     string       `json:"board_id,omitempty"`
+	EdgeId      string       `json:"edge_id,omitempty"`
+	Changes     []PlanChange `json:"changes"`
+	Summary     PlanSummary  `json:"summary"`
+	Status      string       `json:"status"` // "pending", "applying", "applied", "interrupted", "stale", "failed"
+	Message     string       `json:"message,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	AppliedAt   *time.Time   `json:"applied_at,omitempty"`
+}
+
+// Summarize recomputes the plan summary from its changes
+func (p *SyncPlan) Summarize() {
+	p.Summary = PlanSummary{}
+	for _, c := range p.Changes {
+		switch c.Kind {
+		case PlanCopy:
+			p.Summary.CopyCount++
+			p.Summary.CopyBytes += c.Size
+		case PlanUpdate:
+			p.Summary.UpdateCount++
+			p.Summary.UpdateBytes += c.Size
+		case PlanDelete:
+			p.Summary.DeleteCount++
+			p.Summary.DeleteBytes += c.Size
+		case PlanRename:
+			p.Summary.RenameCount++
+			p.Summary.RenameBytes += c.Size
+		}
+	}
+}
@@ -34,6 +34,7 @@ type Profile struct {
 
 	// Performance
 	MultiThreadStreams *int     `json:"multi_thread_streams,omitempty"` // concurrent streams per file transfer
+	BandwidthSchedule  string   `json:"bandwidth_schedule,omitempty"`   // --bwlimit timetable e.g. "Mon-08:00,2M Mon-18:00,off", "10M:2M" for upload:download; overrides Bandwidth
 	BufferSize         string   `json:"buffer_size,omitempty"`          // in-memory buffer per transfer e.g. "16M","64M"
 	FastList           bool     `json:"fast_list,omitempty"`            // use recursive list (fewer API calls, more memory)
 	Retries            *int     `json:"retries,omitempty"`              // number of retries on failure
@@ -7,3 +7,52 @@ type Remote struct {
 }
 
 type Remotes []Remote
+
+// Provider describes a storage backend supported by the app
+type Provider struct {
+	Type           string   `json:"type"` // rclone backend name, e.g. "s3"
+	Name           string   `json:"name"`
+	Description    string   `json:"description"`
+	RequiredFields []string `json:"required_fields"` // config keys that must be set when adding a remote
+	OAuth          bool     `json:"oauth"`           // authorized through a browser flow
+}
+
+// ProviderOption describes a configuration option of a storage backend, taken from
+// rclone's option registry
+type ProviderOption struct {
+	Name      string          `json:"name"`
+	Help      string          `json:"help"`
+	Type      string          `json:"type"` // rclone value type, e.g. "string", "bool", "SizeSuffix"
+	Default   string          `json:"default"`
+	Provider  string          `json:"provider,omitempty"` // only applies to these providers, e.g. "AWS,Minio" or "!AWS"
+	Required  bool            `json:"required"`
+	Advanced  bool            `json:"advanced"`
+	Sensitive bool            `json:"sensitive"` // redacted when read back
+	Password  bool            `json:"password"`  // stored obscured
+	Exclusive bool            `json:"exclusive"` // the value must be one of the examples
+	Examples  []OptionExample `json:"examples,omitempty"`
+}
+
+// OptionExample is a suggested value of a provider option
+type OptionExample struct {
+	Value    string `json:"value"`
+	Help     string `json:"help"`
+	Provider string `json:"provider,omitempty"`
+}
+
+// RemoteConfig is the option schema of a configured remote with its current values.
+// Sensitive values are redacted.
+type RemoteConfig struct {
+	Name    string            `json:"name"`
+	Type    string            `json:"type"`
+	Options []ProviderOption  `json:"options"`
+	Values  map[string]string `json:"values"`
+}
+
+// RemoteConfigUpdate changes options of a configured remote. Options not in Values are
+// kept and an empty value resets an option to its default.
+type RemoteConfigUpdate struct {
+	Values   map[string]string `json:"values"`
+	Keep     []string          `json:"keep,omitempty"`     // options to leave unchanged even if in Values, e.g. redacted secrets
+	Obscured []string          `json:"obscured,omitempty"` // password options whose value is already obscured
+}
@@ -4,13 +4,17 @@ import "time"
 
 // ScheduleEntry represents a scheduled sync operation
 type ScheduleEntry struct {
-	Id          string     `json:"id"`
-	ProfileName string     `json:"profile_name"`
-	Action      string     `json:"action"`      // "pull", "push", "bi", "bi-resync", "copy", "move"
-	CronExpr    string     `json:"cron_expr"`   // cron expression e.g. "0 */6 * * *"
-	Enabled     bool       `json:"enabled"`
-	LastRun     *time.Time `json:"last_run,omitempty"`
-	NextRun     *time.Time `json:"next_run,omitempty"`
-	LastResult  string     `json:"last_result,omitempty"` // "success", "failed", "cancelled"
-	CreatedAt   time.Time  `json:"created_at"`
+	Id            string     `json:"id"`
+	ProfileName   string     `json:"profile_name"`
+	Action        string     `json:"action"`    // "pull", "push", "bi", "bi-resync", "copy", "move", "check"
+	CronExpr      string     `json:"cron_expr"` // cron expression e.g. "0 */6 * * *"
+	Enabled       bool       `json:"enabled"`
+	LastRun       *time.Time `json:"last_run,omitempty"`
+	NextRun       *time.Time `json:"next_run,omitempty"`
+	LastResult    string     `json:"last_result,omitempty"` // "running", "success", "failed", "cancelled"
+	LastError     string     `json:"last_error,omitempty"`
+	LastDuration  string     `json:"last_duration,omitempty"`
+	MisfirePolicy string     `json:"misfire_policy"` // missed runs: "skip", "once" or "all"
+	OverlapPolicy string     `json:"overlap_policy"` // trigger while running: "skip", "queue" or "restart"
+	CreatedAt     time.Time  `json:"created_at"`
 }
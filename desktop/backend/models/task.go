@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// TaskRun is a sync, operation, board or flow run persisted while it runs, so that runs cut
+// short by a crash, reboot or quit can be reported and resumed after a restart
+type TaskRun struct {
+	Id            string      `json:"id"`
+	Kind          string      `json:"kind"`             // "sync", "operation", "board" or "flow"
+	Action        string      `json:"action,omitempty"` // sync action or operation name
+	Profile       Profile     `json:"profile"`
+	TabId         string      `json:"tab_id,omitempty"`
+	BoardId       string      `json:"board_id,omitempty"` // only for "board"
+	FlowId        string      `json:"flow_id,omitempty"`  // only for "flow"
+	Options       TaskOptions `json:"options"`
+	Status        string      `json:"status"` // "running" or "interrupted"
+	Message       string      `json:"message,omitempty"`
+	StartedAt     time.Time   `json:"started_at"`
+	InterruptedAt *time.Time  `json:"interrupted_at,omitempty"`
+}
+
+// TaskOptions holds the operation specific settings needed to start a run again
+type TaskOptions struct {
+	CheckDownload bool   `json:"check_download,omitempty"`
+	DedupeMode    string `json:"dedupe_mode,omitempty"`
+	DedupeByHash  bool   `json:"dedupe_by_hash,omitempty"`
+	ManifestPath  string `json:"manifest_path,omitempty"`
+	HashType      string `json:"hash_type,omitempty"`
+}
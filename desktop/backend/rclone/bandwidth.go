@@ -0,0 +1,73 @@
+package rclone
+
+import (
+	"desktop/backend/models"
+	"desktop/backend/utils"
+	"fmt"
+
+	"github.com/rclone/rclone/fs"
+)
+
+// ParseBandwidth parses an rclone --bwlimit value: a single limit such as "10M", an
+// upload:download pair such as "10M:2M", or a timetable such as
+// "Mon-08:00,2M Mon-18:00,off" or "08:00,512k 19:00,off". An empty value means none.
+func ParseBandwidth(spec string) (fs.BwTimetable, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	var timetable fs.BwTimetable
+	if err := timetable.Set(spec); err != nil {
+		return nil, fmt.Errorf("invalid bandwidth limit %q: %w", spec, err)
+	}
+	return timetable, nil
+}
+
+// ProfileBandwidth returns the bandwidth timetable of a profile: its schedule when set,
+// otherwise its constant limit in MB/s. Empty means the default limit applies.
+func ProfileBandwidth(profile models.Profile) (fs.BwTimetable, error) {
+	if profile.BandwidthSchedule != "" {
+		return ParseBandwidth(profile.BandwidthSchedule)
+	}
+	if profile.Bandwidth > 0 {
+		return ParseBandwidth(fmt.Sprint(profile.Bandwidth) + "M")
+	}
+	return nil, nil
+}
+
+// setBandwidthLimit sets the profile's bandwidth timetable on the operation's config;
+// it is enforced while utils.RunRcloneWithRetryAndStats runs the operation
+func setBandwidthLimit(fsConfig *fs.ConfigInfo, profile models.Profile) error {
+	timetable, err := ProfileBandwidth(profile)
+	if err != nil {
+		return err
+	}
+	fsConfig.BwLimit = timetable
+	return nil
+}
+
+// SetTaskBandwidth caps the bandwidth of a running task until it finishes, e.g. "1M" or
+// "off" for no cap. An empty spec restores the task's own limit. rclone has a single
+// token bucket for the whole process, so the cap applies to every running task while
+// this one runs and raising it has no effect while another task has a lower one.
+// Returns the shared limit now in force, empty when unlimited.
+func SetTaskBandwidth(taskId int, spec string) (string, error) {
+	timetable, err := ParseBandwidth(spec)
+	if err != nil {
+		return "", err
+	}
+	if !utils.SetRunningBandwidth(taskStatsGroup(taskId), timetable) {
+		return "", fmt.Errorf("task %d is not transferring", taskId)
+	}
+	return utils.FormatBandwidth(utils.CurrentBandwidth()), nil
+}
+
+// SetRunningBandwidth changes the bandwidth limit of every running task until it
+// finishes, e.g. "1M" to throttle everything now. An empty spec restores their own limits.
+func SetRunningBandwidth(spec string) error {
+	timetable, err := ParseBandwidth(spec)
+	if err != nil {
+		return err
+	}
+	utils.SetRunningBandwidth("", timetable)
+	return nil
+}
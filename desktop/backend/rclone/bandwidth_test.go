@@ -0,0 +1,121 @@
+package rclone
+
+import (
+	"context"
+	"desktop/backend/models"
+	"desktop/backend/utils"
+	"testing"
+
+	"github.com/rclone/rclone/fs"
+)
+
+// TestProfileBandwidth tests that a profile's schedule overrides its constant limit
+func TestProfileBandwidth(t *testing.T) {
+	timetable, err := ProfileBandwidth(models.Profile{Bandwidth: 5})
+	if err != nil || len(timetable) != 1 || timetable[0].Bandwidth.Tx != 5*fs.Mebi {
+		t.Errorf("expected constant 5M limit, got %v (%v)", timetable, err)
+	}
+
+	timetable, err = ProfileBandwidth(models.Profile{Bandwidth: 5, BandwidthSchedule: "Mon-08:00,2M:1M Sat-00:00,off"})
+	if err != nil || len(timetable) != 2 {
+		t.Fatalf("expected two-slot timetable, got %v (%v)", timetable, err)
+	}
+	if timetable[0].Bandwidth.Tx != 2*fs.Mebi || timetable[0].Bandwidth.Rx != fs.Mebi {
+		t.Errorf("expected 2M upload, 1M download, got %v", timetable[0].Bandwidth)
+	}
+
+	if timetable, err := ProfileBandwidth(models.Profile{}); err != nil || timetable != nil {
+		t.Errorf("expected no limit, got %v (%v)", timetable, err)
+	}
+	if _, err := ProfileBandwidth(models.Profile{BandwidthSchedule: "fast"}); err == nil {
+		t.Error("expected error for invalid schedule")
+	}
+}
+
+// TestLimitBandwidth tests that concurrent operations share the most restrictive limit
+// and that operations without a limit follow the default
+func TestLimitBandwidth(t *testing.T) {
+	defaultLimit, _ := ParseBandwidth("8M")
+	utils.SetDefaultBandwidth(defaultLimit)
+	defer utils.SetDefaultBandwidth(nil)
+
+	if got := utils.CurrentBandwidth(); got.Tx != 8*fs.Mebi {
+		t.Errorf("expected idle bucket at the 8M default, got %v", got)
+	}
+
+	slow, _ := ParseBandwidth("2M:4M")
+	releaseSlow := utils.LimitBandwidth(context.Background(), slow)
+	releaseDefault := utils.LimitBandwidth(context.Background(), nil)
+	if got := utils.CurrentBandwidth(); got.Tx != 2*fs.Mebi || got.Rx != 4*fs.Mebi {
+		t.Errorf("expected 2M:4M while both run, got %v", got)
+	}
+
+	releaseSlow()
+	releaseSlow() // releasing twice must not drop another operation's limit
+	if got := utils.CurrentBandwidth(); got.Tx != 8*fs.Mebi || got.Rx != 8*fs.Mebi {
+		t.Errorf("expected default 8M after the slow operation ends, got %v", got)
+	}
+	releaseDefault()
+
+	utils.SetDefaultBandwidth(nil)
+	if got := utils.CurrentBandwidth(); got.Tx != 0 || got.Rx != 0 {
+		t.Errorf("expected unlimited without a default, got %v", got)
+	}
+}
+
+// TestSetTaskBandwidth tests changing the limit of running tasks while they run
+func TestSetTaskBandwidth(t *testing.T) {
+	ctx1, err := NewTaskContext(context.Background(), 9101)
+	if err != nil {
+		t.Fatalf("NewTaskContext failed: %v", err)
+	}
+	ctx2, err := NewTaskContext(context.Background(), 9102)
+	if err != nil {
+		t.Fatalf("NewTaskContext failed: %v", err)
+	}
+	own, _ := ParseBandwidth("8M")
+	release1 := utils.LimitBandwidth(ctx1, own)
+	defer release1()
+	release2 := utils.LimitBandwidth(ctx2, nil)
+	defer release2()
+
+	// Capping one task throttles both, as they share the bucket
+	current, err := SetTaskBandwidth(9102, "1M")
+	if err != nil {
+		t.Fatalf("SetTaskBandwidth failed: %v", err)
+	}
+	if current != "1Mi" {
+		t.Errorf("expected shared limit 1Mi, got %q", current)
+	}
+
+	// Raising one task's cap has no effect while the other's own limit is lower
+	if current, err = SetTaskBandwidth(9102, "16M"); err != nil || current != "8Mi" {
+		t.Errorf("expected shared limit 8Mi, got %q (%v)", current, err)
+	}
+
+	if err := SetRunningBandwidth("512k"); err != nil {
+		t.Fatalf("SetRunningBandwidth failed: %v", err)
+	}
+	if got := utils.CurrentBandwidth(); got.Tx != 512*fs.Kibi {
+		t.Errorf("expected all tasks throttled to 512Ki, got %v", got)
+	}
+
+	// Clearing the override restores each task's own limit
+	if err := SetRunningBandwidth(""); err != nil {
+		t.Fatalf("SetRunningBandwidth failed: %v", err)
+	}
+	if got := utils.CurrentBandwidth(); got.Tx != 8*fs.Mebi {
+		t.Errorf("expected own 8Mi limit back, got %v", got)
+	}
+	release1()
+	if got := utils.CurrentBandwidth(); got.IsSet() {
+		t.Errorf("expected unlimited with only the task without own limit, got %v", got)
+	}
+
+	if _, err := SetTaskBandwidth(9199, "1M"); err == nil {
+		t.Error("expected error for a task that is not running")
+	}
+	if _, err := SetTaskBandwidth(9102, "fast"); err == nil {
+		t.Error("expected error for an invalid limit")
+	}
+}
@@ -0,0 +1,270 @@
+package rclone
+
+import (
+	"context"
+	"desktop/backend/dto"
+	"desktop/backend/models"
+	"desktop/backend/utils"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/lib/random"
+)
+
+// Benchmark defaults, sized to take a minute or two on a typical connection
+var (
+	defaultBenchmarkSizes    = []string{"64K", "1M", "8M"}
+	defaultBenchmarkFiles    = 8
+	defaultBenchmarkParallel = []int{1, 4, 8}
+)
+
+// BenchmarkDefaults fills in the empty fields of a benchmark config
+func BenchmarkDefaults(cfg models.BenchmarkConfig) models.BenchmarkConfig {
+	if len(cfg.FileSizes) == 0 {
+		cfg.FileSizes = defaultBenchmarkSizes
+	}
+	if cfg.FilesPerSize <= 0 {
+		cfg.FilesPerSize = defaultBenchmarkFiles
+	}
+	if len(cfg.Parallel) == 0 {
+		cfg.Parallel = defaultBenchmarkParallel
+	}
+	return cfg
+}
+
+// Benchmark uploads generated files of each size to a temporary directory at the
+// remote and downloads them again, once per parallelism level, measuring throughput,
+// request latency and errors. Transfers go through rclone's copy like syncs do, so they
+// are counted in the context's stats and honour the multi-thread and buffer settings.
+// Failed requests are part of the results; an error is returned when the benchmark
+// can't run or every request failed.
+func Benchmark(ctx context.Context, cfg models.BenchmarkConfig, outStatus chan *dto.SyncStatusDTO) ([]models.BenchmarkResult, error) {
+	cfg = BenchmarkDefaults(cfg)
+	sizes := make([]fs.SizeSuffix, len(cfg.FileSizes))
+	for i, s := range cfg.FileSizes {
+		if err := sizes[i].Set(s); err != nil || sizes[i] <= 0 {
+			return nil, fmt.Errorf("invalid benchmark file size %q", s)
+		}
+	}
+	for _, p := range cfg.Parallel {
+		if p < 1 || p > 256 {
+			return nil, fmt.Errorf("benchmark parallelism must be between 1 and 256, got %d", p)
+		}
+	}
+
+	fsConfig := fs.GetConfig(ctx)
+	if cfg.MultiThreadStreams != nil {
+		fsConfig.MultiThreadStreams = *cfg.MultiThreadStreams
+	}
+	if cfg.BufferSize != "" {
+		if err := fsConfig.BufferSize.Set(cfg.BufferSize); err != nil {
+			return nil, fmt.Errorf("invalid buffer_size %q: %w", cfg.BufferSize, err)
+		}
+	}
+
+	remoteFs, err := fs.NewFs(ctx, cfg.Remote)
+	if utils.HandleError(err, "Failed to initialize filesystem", nil, nil) != nil {
+		return nil, err
+	}
+
+	localDir, err := os.MkdirTemp("", "ns-drive-benchmark-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create benchmark directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(localDir) }()
+
+	files, err := generateBenchmarkFiles(filepath.Join(localDir, "src"), sizes, cfg.FilesPerSize)
+	if err != nil {
+		return nil, err
+	}
+	srcFs, err := fs.NewFs(ctx, filepath.Join(localDir, "src"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize benchmark source: %w", err)
+	}
+	dlFs, err := fs.NewFs(ctx, filepath.Join(localDir, "download"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize benchmark download directory: %w", err)
+	}
+
+	benchDir := ".ns-drive-benchmark-" + random.String(8)
+	defer func() {
+		// Clean up even when the benchmark was cancelled
+		if err := operations.Purge(context.WithoutCancel(ctx), remoteFs, benchDir); err != nil && !errors.Is(err, fs.ErrorDirNotFound) {
+			fs.Errorf(remoteFs, "Failed to remove benchmark directory %s: %v", benchDir, err)
+		}
+	}()
+
+	var results []models.BenchmarkResult
+	var requests, failures int
+	var firstErr error
+	err = utils.RunRcloneWithRetryAndStats(ctx, false, false, outStatus, func() error {
+		for i, size := range sizes {
+			names := files[i]
+			for _, parallel := range cfg.Parallel {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				remoteName := func(n int) string {
+					return path.Join(benchDir, fmt.Sprintf("p%d", parallel), names[n])
+				}
+
+				result := models.BenchmarkResult{FileSize: int64(size), Parallel: parallel}
+				var upErr, downErr error
+				result.Upload, upErr = benchmarkPhase(parallel, len(names), func(n int) (int64, error) {
+					src, err := srcFs.NewObject(ctx, names[n])
+					if err != nil {
+						return 0, err
+					}
+					_, err = operations.Copy(ctx, remoteFs, nil, remoteName(n), src)
+					return src.Size(), err
+				})
+				result.Download, downErr = benchmarkPhase(parallel, len(names), func(n int) (int64, error) {
+					obj, err := remoteFs.NewObject(ctx, remoteName(n))
+					if err != nil {
+						return 0, err
+					}
+					_, err = operations.Copy(ctx, dlFs, nil, remoteName(n), obj)
+					return obj.Size(), err
+				})
+
+				for _, err := range []error{upErr, downErr} {
+					if firstErr == nil {
+						firstErr = err
+					}
+				}
+				requests += result.Upload.Requests + result.Download.Requests
+				failures += result.Upload.Errors + result.Download.Errors
+				fs.Infof(remoteFs, "Benchmark %v files x%d: upload %v/s, download %v/s", size, parallel,
+					fs.SizeSuffix(int64(result.Upload.Throughput)), fs.SizeSuffix(int64(result.Download.Throughput)))
+				results = append(results, result)
+			}
+		}
+		return nil
+	})
+	if ctx.Err() != nil {
+		return results, ctx.Err()
+	}
+	if requests > 0 && failures == requests {
+		return results, fmt.Errorf("every benchmark request failed: %w", firstErr)
+	}
+	if err != nil && len(results) == 0 {
+		return nil, err
+	}
+	// Failed requests are counted as errors but are part of the results
+	return results, nil
+}
+
+// generateBenchmarkFiles writes count files of random content for each size and
+// returns their names per size
+func generateBenchmarkFiles(dir string, sizes []fs.SizeSuffix, count int) ([][]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create benchmark directory: %w", err)
+	}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	files := make([][]string, len(sizes))
+	for i, size := range sizes {
+		for n := range count {
+			name := fmt.Sprintf("%s-%d.bin", size, n)
+			f, err := os.Create(filepath.Join(dir, name))
+			if err != nil {
+				return nil, fmt.Errorf("failed to create benchmark file: %w", err)
+			}
+			_, err = io.CopyN(f, rng, int64(size))
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to write benchmark file: %w", err)
+			}
+			files[i] = append(files[i], name)
+		}
+	}
+	return files, nil
+}
+
+// benchmarkPhase runs n requests with the given parallelism and measures them. request
+// returns the bytes it transferred. The first request error is returned alongside.
+func benchmarkPhase(parallel, n int, request func(n int) (int64, error)) (models.BenchmarkPhase, error) {
+	var (
+		mu        sync.Mutex
+		latencies []time.Duration
+		phase     = models.BenchmarkPhase{Requests: n}
+		firstErr  error
+		wg        sync.WaitGroup
+	)
+	indexes := make(chan int, n)
+	for i := range n {
+		indexes <- i
+	}
+	close(indexes)
+
+	start := time.Now()
+	for range min(parallel, n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				reqStart := time.Now()
+				size, err := request(i)
+				elapsed := time.Since(reqStart)
+
+				mu.Lock()
+				if err != nil {
+					phase.Errors++
+					if firstErr == nil {
+						firstErr = err
+					}
+				} else {
+					phase.Bytes += size
+					latencies = append(latencies, elapsed)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	wall := time.Since(start)
+
+	if n > 0 {
+		phase.ErrorRate = float64(phase.Errors) / float64(n)
+	}
+	if wall > 0 {
+		phase.Throughput = float64(phase.Bytes) / wall.Seconds()
+	}
+	phase.Latency = latencySummary(latencies)
+	return phase, firstErr
+}
+
+// latencySummary computes the latency distribution of successful requests
+func latencySummary(latencies []time.Duration) models.BenchmarkLatency {
+	if len(latencies) == 0 {
+		return models.BenchmarkLatency{}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	ms := func(d time.Duration) float64 { return float64(d.Microseconds()) / 1000 }
+	percentile := func(p float64) float64 {
+		return ms(latencies[int(p*float64(len(latencies)-1)+0.5)])
+	}
+
+	var total time.Duration
+	for _, l := range latencies {
+		total += l
+	}
+	return models.BenchmarkLatency{
+		Min: ms(latencies[0]),
+		Avg: ms(total / time.Duration(len(latencies))),
+		P50: percentile(0.5),
+		P95: percentile(0.95),
+		Max: ms(latencies[len(latencies)-1]),
+	}
+}
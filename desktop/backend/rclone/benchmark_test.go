@@ -0,0 +1,52 @@
+package rclone
+
+import (
+	"context"
+	"desktop/backend/models"
+	"os"
+	"testing"
+)
+
+// TestBenchmark tests that a benchmark of a local directory measures every size and
+// parallelism level and cleans up after itself
+func TestBenchmark(t *testing.T) {
+	ctx, err := NewTaskContext(context.Background(), 9011)
+	if err != nil {
+		t.Fatalf("NewTaskContext failed: %v", err)
+	}
+	dir := t.TempDir()
+
+	results, err := Benchmark(ctx, models.BenchmarkConfig{
+		Remote:       dir,
+		FileSizes:    []string{"4K", "64K"},
+		FilesPerSize: 3,
+		Parallel:     []int{1, 2},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Benchmark failed: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+	for _, r := range results {
+		for _, phase := range []models.BenchmarkPhase{r.Upload, r.Download} {
+			if phase.Requests != 3 || phase.Errors != 0 || phase.Bytes != 3*r.FileSize || phase.Throughput <= 0 {
+				t.Errorf("unexpected phase for %d bytes x%d: %+v", r.FileSize, r.Parallel, phase)
+			}
+			if phase.Latency.Max < phase.Latency.Min || phase.Latency.P95 > phase.Latency.Max {
+				t.Errorf("inconsistent latency %+v", phase.Latency)
+			}
+		}
+	}
+	if results[0].FileSize != 4096 || results[1].Parallel != 2 {
+		t.Errorf("unexpected result order: %+v", results)
+	}
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("expected benchmark directory to be removed, found %d entries", len(entries))
+	}
+
+	if _, err := Benchmark(ctx, models.BenchmarkConfig{Remote: dir, FileSizes: []string{"0"}}, nil); err == nil {
+		t.Error("expected error for zero file size")
+	}
+}
@@ -2,15 +2,11 @@ package rclone
 
 import (
 	"context"
-	"crypto/sha256"
 	"desktop/backend/config"
 	"desktop/backend/dto"
 	"desktop/backend/models"
 	"desktop/backend/utils"
 	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/rclone/rclone/cmd/bisync"
 	"github.com/rclone/rclone/fs"
@@ -73,83 +69,46 @@ func BiSync(ctx context.Context, config config.Config, profile models.Profile, r
 		opt.DryRun = true
 	}
 
-	// Handle resync
-	if resync {
-		opt.Resync = true
-	} else {
-		dir, err := os.Getwd()
-		if utils.HandleError(err, "Failed to get current working directory", nil, nil) != nil {
-			return err
-		}
-		path := filepath.Join(dir, config.ResyncFilePath)
-		if _, err := os.Stat(path); os.IsNotExist(err) {
-			_, err = os.Create(path)
-			if utils.HandleError(err, "Failed to create .resync file", nil, nil) != nil {
-				return err
-			}
-		}
-		filterFileChecksum := "0"
-		if len(profile.IncludedPaths)+len(profile.ExcludedPaths) > 0 {
-			filterFileChecksum, err = utils.CalculateContentHash(utils.MergeBytes([]byte(strings.Join(profile.IncludedPaths, "")), []byte(strings.Join(profile.ExcludedPaths, ""))), sha256.New)
-			if utils.HandleError(err, "Failed to calculate hash of filter file", nil, nil) != nil {
-				return err
-			}
-		}
-		filterFileChecksumLinePrefix := profile.From + "|" + profile.To + "|"
-		filterFileChecksumLine := filterFileChecksumLinePrefix + filterFileChecksum
-		filterFileChecksumLineContained := false
-		resyncContent, err := os.ReadFile(path)
-		if utils.HandleError(err, "Failed to read .resync file", nil, nil) != nil {
-			return err
-		}
-		resyncContentStrArr := strings.Split(string(resyncContent), "\n")
-		for _, line := range resyncContentStrArr {
-			if line == filterFileChecksumLine {
-				filterFileChecksumLineContained = true
-				break
-			}
-		}
-		if !filterFileChecksumLineContained {
-			// Update .resync file content
-			newResyncContentStrArr := []string{}
-			isAdded := false
-			for _, line := range resyncContentStrArr {
-				if line == "" {
-					continue
-				}
-				if strings.HasPrefix(line, filterFileChecksumLinePrefix) {
-					newResyncContentStrArr = append(newResyncContentStrArr, filterFileChecksumLine)
-					isAdded = true
-				} else {
-					newResyncContentStrArr = append(newResyncContentStrArr, line)
-				}
-			}
-			if !isAdded {
-				newResyncContentStrArr = append(newResyncContentStrArr, filterFileChecksumLine)
-			}
-			// Write to .resync file
-			err = os.WriteFile(path, []byte(strings.Join(newResyncContentStrArr, "\n")), 0644)
-			if utils.HandleError(err, "Failed to write to .resync file", nil, nil) != nil {
-				return err
-			}
-			// Set resync flag
-			opt.Resync = true
-			// if err = opt.ResyncMode.Set(bisync.PreferNewer.String()); err != nil {
-			// 	return err
-			// }
-		}
-	}
-
-	srcFs, err := fs.NewFs(ctx, profile.From)
+	srcFs, err := newTaskFs(ctx, profile.From)
 	if utils.HandleError(err, "Failed to initialize source filesystem", nil, nil) != nil {
 		return err
 	}
 
-	dstFs, err := fs.NewFs(ctx, profile.To)
+	dstFs, err := newTaskFs(ctx, profile.To)
 	if utils.HandleError(err, "Failed to initialize destination filesystem", nil, nil) != nil {
 		return err
 	}
 
+	// Resync when requested, on the first run of the pair, or when the state store says so
+	// (e.g. the filter rules changed since the last resync)
+	filterHash, err := BisyncFilterHash(profile)
+	if utils.HandleError(err, "Failed to calculate hash of filter rules", nil, nil) != nil {
+		return err
+	}
+	store := getBisyncStateStore()
+	session, err := newBisyncSession(dstFs, srcFs)
+	if err != nil {
+		return fmt.Errorf("failed to resolve bisync session: %w", err)
+	}
+	opt.Resync = resync
+	if !opt.Resync {
+		if !session.HasListings() {
+			if err := checkMissingListings(session, store, profile); err != nil {
+				return err
+			}
+			opt.Resync = true
+		} else if store != nil {
+			reason, err := store.ResyncReason(profile, filterHash)
+			if err != nil {
+				return fmt.Errorf("failed to read bisync state: %w", err)
+			}
+			if reason != "" {
+				fs.Infof(nil, "Bisync %s <-> %s needs a resync: %s", profile.From, profile.To, reason)
+				opt.Resync = true
+			}
+		}
+	}
+
 	// Set up filter rules (prefix with {{regexp:}} if UseRegex is enabled)
 	filterOpt := CopyFilterOpt(ctx)
 	for _, p := range profile.IncludedPaths {
@@ -174,10 +133,8 @@ func BiSync(ctx context.Context, config config.Config, profile models.Profile, r
 	}
 
 	// Set bandwidth limit
-	if profile.Bandwidth > 0 {
-		if err := utils.HandleError(fsConfig.BwLimit.Set(fmt.Sprint(profile.Bandwidth)+"M"), "Failed to set bandwidth limit", nil, nil); err != nil {
-			return err
-		}
+	if err := utils.HandleError(setBandwidthLimit(fsConfig, profile), "Failed to set bandwidth limit", nil, nil); err != nil {
+		return err
 	}
 
 	// Set parallel transfers
@@ -195,7 +152,63 @@ func BiSync(ctx context.Context, config config.Config, profile models.Profile, r
 		return err
 	}
 
-	return utils.RunRcloneWithRetryAndStats(ctx, true, false, outStatus, func() error {
+	// Conflicts only arise in normal runs; a resync never renames. Copies listed before
+	// the run were there already.
+	var collector *conflictCollector
+	var namer *conflictNamer
+	var existing map[string]struct{}
+	if store != nil && !opt.Resync && !opt.DryRun {
+		collector = newConflictCollector(dstFs, srcFs)
+		namer = newConflictNamer(profile)
+		existing = listedConflictCopies(ctx, namer, session.Listing1, session.Listing2)
+	}
+
+	err = utils.RunRcloneWithRetryAndStats(ctx, true, false, outStatus, func() error {
 		return utils.HandleError(bisync.Bisync(ctx, dstFs, srcFs, opt), "Sync failed", nil, nil)
 	})
+
+	if store != nil && !opt.DryRun {
+		if recordErr := store.RecordRun(profile, filterHash, opt.Resync, err); recordErr != nil {
+			fs.Errorf(nil, "Failed to record bisync state for %s <-> %s: %v", profile.From, profile.To, recordErr)
+		}
+	}
+	if collector != nil {
+		collector.stop()
+		conflicts, findErr := collector.findConflicts(ctx, profile, srcFs, dstFs, namer, existing)
+		if findErr != nil {
+			fs.Errorf(nil, "Failed to find bisync conflicts of %s <-> %s: %v", profile.From, profile.To, findErr)
+		}
+		if len(conflicts) > 0 {
+			if recordErr := store.RecordConflicts(profile, conflicts); recordErr != nil {
+				fs.Errorf(nil, "Failed to record bisync conflicts for %s <-> %s: %v", profile.From, profile.To, recordErr)
+			}
+		}
+	}
+	return err
+}
+
+// checkMissingListings decides whether a pair without listings may be resynced. Only a
+// first run (no recorded state) or a requested resync may: listings that went missing
+// later, in particular those rclone set aside after a critical error, need the user to
+// check both sides first, as a resync could bring back deleted files.
+func checkMissingListings(session *BisyncSession, store BisyncStateStore, profile models.Profile) error {
+	known := false
+	if store != nil {
+		var resyncPending bool
+		var err error
+		known, resyncPending, err = store.PairState(profile)
+		if err != nil {
+			return fmt.Errorf("failed to read bisync state: %w", err)
+		}
+		if resyncPending {
+			return nil
+		}
+	}
+	switch {
+	case session.HasFailedListings():
+		return fmt.Errorf("bisync of '%s' and '%s' stopped after a critical error; check both sides, then force a resync of the pair", profile.From, profile.To)
+	case known:
+		return fmt.Errorf("bisync listings of '%s' and '%s' are missing; check both sides, then force a resync of the pair", profile.From, profile.To)
+	}
+	return nil
 }
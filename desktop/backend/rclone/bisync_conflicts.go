@@ -0,0 +1,560 @@
+package rclone
+
+import (
+	"context"
+	"desktop/backend/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/cmd/bisync"
+	"github.com/rclone/rclone/cmd/bisync/bilib"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	fslog "github.com/rclone/rclone/fs/log"
+	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/fs/walk"
+)
+
+// bisync announces a conflict with this warning, then logs what it does with each side
+const conflictWarning = "New or changed in both paths"
+
+var (
+	conflictActionRegex = regexp.MustCompile(`(Not renaming|Renaming|Deleting) Path([12]) copy`)
+	ansiColorRegex      = regexp.MustCompile("\x1b\\[[0-9;]*m")
+)
+
+// conflictCollector follows what bisync logs about the conflicts of a run: which side won
+// and which copy it renamed. The conflicts themselves are found on the remotes after the
+// run (see findConflicts), so lines lost to the log level or a reworded message only cost
+// that detail. Path1 is the destination, path2 the source.
+type conflictCollector struct {
+	mu      sync.Mutex
+	roots   [3]string
+	pending map[string]*pendingConflict
+	found   []pendingConflict
+}
+
+type pendingConflict struct {
+	path    string
+	names   [3]string // renamed path per side
+	seen    [3]bool
+	winner  int
+	deleted bool
+}
+
+// rclone's log outputs can't be removed, so a single output is added once and hands each
+// line to the collectors of the bisync runs in progress
+var (
+	conflictOutputOnce sync.Once
+	conflictCollectors = struct {
+		sync.Mutex
+		active map[*conflictCollector]struct{}
+	}{active: make(map[*conflictCollector]struct{})}
+)
+
+// newConflictCollector starts collecting conflicts of a bisync between fs1 and fs2
+func newConflictCollector(fs1, fs2 fs.Fs) *conflictCollector {
+	c := &conflictCollector{pending: make(map[string]*pendingConflict)}
+	c.roots[1] = bilib.FsPath(fs1)
+	c.roots[2] = bilib.FsPath(fs2)
+
+	conflictOutputOnce.Do(func() {
+		fslog.Handler.AddOutput(false, dispatchConflictOutput)
+	})
+	conflictCollectors.Lock()
+	conflictCollectors.active[c] = struct{}{}
+	conflictCollectors.Unlock()
+	return c
+}
+
+// stop ends collection
+func (c *conflictCollector) stop() {
+	conflictCollectors.Lock()
+	delete(conflictCollectors.active, c)
+	conflictCollectors.Unlock()
+}
+
+func dispatchConflictOutput(level slog.Level, text string) {
+	if !strings.Contains(text, conflictWarning) && !strings.Contains(text, " copy") {
+		return
+	}
+	conflictCollectors.Lock()
+	collectors := make([]*conflictCollector, 0, len(conflictCollectors.active))
+	for c := range conflictCollectors.active {
+		collectors = append(collectors, c)
+	}
+	conflictCollectors.Unlock()
+
+	for _, c := range collectors {
+		c.output(level, text)
+	}
+}
+
+// output records a bisync log line. Concurrent runs share the log, so a conflict is only
+// attributed to this run when bisync acts on a file under one of this run's roots.
+func (c *conflictCollector) output(level slog.Level, text string) {
+	// bisync colors its output when terminal colors are enabled
+	text = strings.TrimSpace(ansiColorRegex.ReplaceAllString(text, ""))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if idx := strings.Index(text, conflictWarning); idx >= 0 {
+		// Another run may have warned about the same file while this one is resolving it
+		file := conflictLogFile(text[idx+len(conflictWarning):])
+		if _, ok := c.pending[file]; !ok {
+			c.pending[file] = &pendingConflict{path: file}
+		}
+		return
+	}
+
+	m := conflictActionRegex.FindStringSubmatchIndex(text)
+	if m == nil {
+		return
+	}
+	action := text[m[2]:m[3]]
+	side, _ := strconv.Atoi(text[m[4]:m[5]])
+
+	file := conflictLogFile(text[m[1]:])
+	if !strings.HasPrefix(file, c.roots[side]) {
+		return
+	}
+	file = strings.TrimPrefix(file, c.roots[side])
+	p := c.pendingFor(file)
+	if p == nil {
+		return
+	}
+
+	p.seen[side] = true
+	switch action {
+	case "Not renaming":
+		p.winner = side
+	case "Renaming":
+		p.names[side] = file
+	case "Deleting":
+		p.deleted = true
+	}
+
+	if p.seen[1] && p.seen[2] || p.deleted {
+		// A deleted loser leaves nothing to resolve
+		if !p.deleted {
+			c.found = append(c.found, *p)
+		}
+		delete(c.pending, p.path)
+	}
+}
+
+// pendingFor finds the conflict a file logged by bisync belongs to. The file is either the
+// original path or the path with the conflict suffix added, before the extension when
+// --suffix-keep-extension is set.
+func (c *conflictCollector) pendingFor(file string) *pendingConflict {
+	if p, ok := c.pending[file]; ok {
+		return p
+	}
+	var match *pendingConflict
+	for name, p := range c.pending {
+		ext := path.Ext(name)
+		suffixed := strings.HasPrefix(file, name+".") ||
+			ext != "" && strings.HasPrefix(file, strings.TrimSuffix(name, ext)+".") && strings.HasSuffix(file, ext)
+		if suffixed && (match == nil || len(name) > len(match.path)) {
+			match = p
+		}
+	}
+	return match
+}
+
+// conflictLogFile extracts the file from the " - file" tail of a bisync log line
+func conflictLogFile(tail string) string {
+	tail = strings.TrimLeft(tail, " ,")
+	if i := strings.Index(tail, " - "); i >= 0 {
+		tail = tail[i+3:]
+	} else {
+		tail = strings.TrimPrefix(tail, "- ")
+	}
+	if unquoted, err := strconv.Unquote(tail); err == nil {
+		return unquoted
+	}
+	return tail
+}
+
+// timeGlobRegex matches the time globs a conflict suffix may contain, like {DateOnly}
+var timeGlobRegex = regexp.MustCompile(`\{[^{}]*\}`)
+
+// conflictNamer recognizes the copies bisync renames conflicting files to, from the
+// profile's conflict suffixes. Side 1 is the destination, side 2 the source.
+type conflictNamer struct {
+	patterns   [3]*regexp.Regexp
+	sameSuffix bool
+	pathname   bool // --conflict-loser pathname, which numbers a shared suffix by side
+}
+
+func newConflictNamer(profile models.Profile) *conflictNamer {
+	flag := profile.ConflictSuffix
+	if flag == "" {
+		flag = "conflict"
+	}
+	suffixes := strings.Split(flag, ",")
+	if len(suffixes) == 1 {
+		suffixes = append(suffixes, suffixes[0])
+	}
+	n := &conflictNamer{
+		sameSuffix: suffixes[0] == suffixes[1],
+		pathname:   profile.ConflictLoser == bisync.ConflictLoserPathname.String(),
+	}
+	// Copies are numbered, except under pathname with two suffixes
+	digits := `(\d+)`
+	if n.pathname && !n.sameSuffix {
+		digits = `(\d*)`
+	}
+	for side := 1; side <= 2; side++ {
+		// bisync expands time globs when the run starts, so they match anything
+		parts := timeGlobRegex.Split(suffixes[side-1], -1)
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+		n.patterns[side] = regexp.MustCompile(`^(.*)(\.` + strings.Join(parts, ".+?") + `)` + digits + `(.*)$`)
+	}
+	return n
+}
+
+// conflictCopy is a file bisync renamed a conflicting version to
+type conflictCopy struct {
+	name string
+	side int // 0 when the name doesn't tell
+	num  int
+}
+
+// parse returns the original path of a conflict copy. The copy is named like bisync's
+// SuffixName does, with the suffix before the extension when --suffix-keep-extension is set.
+func (n *conflictNamer) parse(ctx context.Context, name string) (string, conflictCopy, bool) {
+	for side := 1; side <= 2; side++ {
+		m := n.patterns[side].FindStringSubmatch(name)
+		if m == nil {
+			continue
+		}
+		original := m[1] + m[4]
+		if original == "" || bisync.SuffixName(ctx, original, m[2]+m[3]) != name {
+			continue
+		}
+		c := conflictCopy{name: name, side: side}
+		c.num, _ = strconv.Atoi(m[3])
+		if n.sameSuffix {
+			c.side = 0
+			if n.pathname && (c.num == 1 || c.num == 2) {
+				c.side = c.num
+			}
+		}
+		return original, c, true
+	}
+	return "", conflictCopy{}, false
+}
+
+// listedConflictCopies returns the conflict copies in bisync listing files, i.e. the
+// copies that existed when the pair last ran
+func listedConflictCopies(ctx context.Context, namer *conflictNamer, listings ...string) map[string]struct{} {
+	copies := make(map[string]struct{})
+	for _, listing := range listings {
+		data, err := os.ReadFile(listing)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			// The path is the last field, quoted, after a timestamp that holds no quotes
+			i := strings.Index(line, ` "`)
+			if i < 0 {
+				continue
+			}
+			name, err := strconv.Unquote(line[i+1:])
+			if err != nil {
+				continue
+			}
+			if _, _, ok := namer.parse(ctx, name); ok {
+				copies[name] = struct{}{}
+			}
+		}
+	}
+	return copies
+}
+
+// findConflicts lists the conflict copies on both remotes and describes those the run
+// created, i.e. that are not in existing. What is on the remotes decides which conflicts
+// there are; the collected log lines only tell which side each copy came from when the
+// names don't.
+func (c *conflictCollector) findConflicts(ctx context.Context, profile models.Profile, srcFs, dstFs fs.Fs, namer *conflictNamer, existing map[string]struct{}) ([]models.BisyncConflict, error) {
+	groups := make(map[string]map[string]conflictCopy)
+	var order []string
+	for _, f := range []fs.Fs{dstFs, srcFs} {
+		err := walk.ListR(ctx, f, "", false, -1, walk.ListObjects, func(entries fs.DirEntries) error {
+			for _, entry := range entries {
+				name := entry.Remote()
+				if _, ok := existing[name]; ok {
+					continue
+				}
+				original, cp, ok := namer.parse(ctx, name)
+				if !ok {
+					continue
+				}
+				if groups[original] == nil {
+					groups[original] = make(map[string]conflictCopy)
+					order = append(order, original)
+				}
+				groups[original][name] = cp
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list conflicts on %s: %w", f.String(), err)
+		}
+	}
+
+	c.mu.Lock()
+	logged := make(map[string]pendingConflict, len(c.found))
+	for _, p := range c.found {
+		logged[p.path] = p
+	}
+	c.mu.Unlock()
+
+	hashType := srcFs.Hashes().Overlap(dstFs.Hashes()).GetOne()
+	now := time.Now()
+	var conflicts []models.BisyncConflict
+	for _, original := range order {
+		names, winner, ok := conflictSides(groups[original], logged[original], profile)
+		if !ok {
+			fs.Logf(original, "Found %d conflict copies, expected one or two; not recording", len(groups[original]))
+			continue
+		}
+		// The winner, if any, keeps the original name
+		for side := 1; side <= 2; side++ {
+			if names[side] == "" {
+				names[side] = original
+			}
+		}
+		conflicts = append(conflicts, models.BisyncConflict{
+			ProfileName: profile.Name,
+			From:        profile.From,
+			To:          profile.To,
+			Path:        original,
+			Winner:      winner,
+			Source:      conflictVersion(ctx, hashType, names[2], dstFs, srcFs),
+			Destination: conflictVersion(ctx, hashType, names[1], dstFs, srcFs),
+			Status:      "open",
+			DetectedAt:  now,
+		})
+	}
+	return conflicts, nil
+}
+
+// conflictSides works out which side each copy of a conflict came from and the winner.
+// The log lines of the run say so directly. Otherwise distinct suffixes and pathname
+// numbering name the side; two copies under a shared suffix got increasing numbers,
+// destination first; and a path1 or path2 preference names the winner. A single copy
+// that none of these places is recorded as the source version with an "unknown" winner.
+func conflictSides(copies map[string]conflictCopy, logged pendingConflict, profile models.Profile) ([3]string, string, bool) {
+	var names [3]string
+	winners := [3]string{"none", "destination", "source"}
+
+	if logged.path != "" {
+		matches := true
+		for side := 1; side <= 2; side++ {
+			if _, ok := copies[logged.names[side]]; logged.names[side] != "" && !ok {
+				matches = false
+			}
+		}
+		if matches {
+			return logged.names, winners[logged.winner], true
+		}
+	}
+
+	list := make([]conflictCopy, 0, len(copies))
+	for _, cp := range copies {
+		list = append(list, cp)
+	}
+	switch len(list) {
+	case 2:
+		// No winner, so both copies were renamed
+		a, b := list[0], list[1]
+		if a.side == 0 || b.side == 0 || a.side == b.side {
+			if a.num > b.num {
+				a, b = b, a
+			}
+			a.side, b.side = 1, 2
+		}
+		names[a.side], names[b.side] = a.name, b.name
+		return names, winners[0], true
+	case 1:
+		// The loser was renamed and the winner kept the original name
+		loser := list[0].side
+		if loser == 0 {
+			switch profile.ConflictResolution {
+			case bisync.PreferPath1.String():
+				loser = 2
+			case bisync.PreferPath2.String():
+				loser = 1
+			}
+		}
+		if loser == 0 {
+			names[2] = list[0].name
+			return names, "unknown", true
+		}
+		names[loser] = list[0].name
+		return names, winners[3-loser], true
+	}
+	return names, "", false
+}
+
+// conflictVersion reads the size, modtime and hash of a version from the first remote
+// that has it. Both have it once the run has copied the renamed files across.
+func conflictVersion(ctx context.Context, hashType hash.Type, remote string, fses ...fs.Fs) models.ConflictVersion {
+	v := models.ConflictVersion{Path: remote}
+	var obj fs.Object
+	for _, f := range fses {
+		var err error
+		if obj, err = f.NewObject(ctx, remote); err == nil {
+			break
+		}
+	}
+	if obj == nil {
+		return v
+	}
+	v.Size = obj.Size()
+	v.ModTime = obj.ModTime(ctx)
+	if hashType != hash.None {
+		if h, err := obj.Hash(ctx, hashType); err == nil && h != "" {
+			v.Hash = hashType.String() + ":" + h
+		}
+	}
+	return v
+}
+
+// versionChanged reports whether an object no longer matches the version recorded for a
+// conflict. The hash is only compared when the object's remote supports its type.
+func versionChanged(ctx context.Context, obj fs.Object, v models.ConflictVersion, window time.Duration) bool {
+	if obj.Size() != v.Size {
+		return true
+	}
+	if window != fs.ModTimeNotSupported {
+		dt := obj.ModTime(ctx).Sub(v.ModTime)
+		if dt > window || dt < -window {
+			return true
+		}
+	}
+	if name, value, ok := strings.Cut(v.Hash, ":"); ok {
+		var hashType hash.Type
+		if err := hashType.Set(name); err == nil && obj.Fs().Hashes().Contains(hashType) {
+			h, err := obj.Hash(ctx, hashType)
+			if err != nil || h != "" && h != value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ResolveBisyncConflict applies a resolution to both remotes of the pair. Keeping one side
+// moves that version to the original path and deletes the other version; keeping both
+// leaves the renamed versions in place.
+func ResolveBisyncConflict(ctx context.Context, conflict models.BisyncConflict, resolution string) error {
+	var keep, drop models.ConflictVersion
+	switch resolution {
+	case models.ConflictKeepBoth:
+		return nil
+	case models.ConflictKeepSource:
+		keep, drop = conflict.Source, conflict.Destination
+	case models.ConflictKeepDestination:
+		keep, drop = conflict.Destination, conflict.Source
+	default:
+		return fmt.Errorf("unknown conflict resolution %q", resolution)
+	}
+
+	srcFs, err := fs.NewFs(ctx, conflict.From)
+	if err != nil {
+		return fmt.Errorf("failed to initialize source filesystem: %w", err)
+	}
+	dstFs, err := fs.NewFs(ctx, conflict.To)
+	if err != nil {
+		return fmt.Errorf("failed to initialize destination filesystem: %w", err)
+	}
+
+	// Refuse to touch anything if either remote changed since the conflict was detected
+	window := fs.GetModifyWindow(ctx, srcFs, dstFs)
+	for _, f := range []fs.Fs{srcFs, dstFs} {
+		for _, v := range []models.ConflictVersion{keep, drop} {
+			obj, err := f.NewObject(ctx, v.Path)
+			if err != nil {
+				return fmt.Errorf("%s on %s: %w", v.Path, f.String(), err)
+			}
+			if versionChanged(ctx, obj, v, window) {
+				return fmt.Errorf("%s on %s changed since the conflict was detected", v.Path, f.String())
+			}
+		}
+	}
+
+	for _, f := range []fs.Fs{srcFs, dstFs} {
+		if keep.Path != conflict.Path {
+			if err := operations.MoveFile(ctx, f, f, conflict.Path, keep.Path); err != nil {
+				return fmt.Errorf("failed to move %s to %s on %s: %w", keep.Path, conflict.Path, f.String(), err)
+			}
+		}
+		if drop.Path != conflict.Path {
+			obj, err := f.NewObject(ctx, drop.Path)
+			if errors.Is(err, fs.ErrorObjectNotFound) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to find %s on %s: %w", drop.Path, f.String(), err)
+			}
+			if err := operations.DeleteFile(ctx, obj); err != nil {
+				return fmt.Errorf("failed to delete %s on %s: %w", drop.Path, f.String(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// DownloadConflictVersions copies both versions of a conflict into a local directory so
+// they can be opened side by side, returning the local source and destination paths
+func DownloadConflictVersions(ctx context.Context, conflict models.BisyncConflict, dir string) (string, string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	dstFs, err := fs.NewFs(ctx, conflict.To)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to initialize destination filesystem: %w", err)
+	}
+	localFs, err := fs.NewFs(ctx, dir)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to initialize local filesystem: %w", err)
+	}
+
+	download := func(prefix string, v models.ConflictVersion) (string, error) {
+		obj, err := dstFs.NewObject(ctx, v.Path)
+		if err != nil {
+			return "", fmt.Errorf("failed to find %s: %w", v.Path, err)
+		}
+		name := prefix + "-" + path.Base(v.Path)
+		if _, err := operations.Copy(ctx, localFs, nil, name, obj); err != nil {
+			return "", fmt.Errorf("failed to download %s: %w", v.Path, err)
+		}
+		return filepath.Join(dir, name), nil
+	}
+
+	srcLocal, err := download("source", conflict.Source)
+	if err != nil {
+		return "", "", err
+	}
+	dstLocal, err := download("destination", conflict.Destination)
+	if err != nil {
+		return "", "", err
+	}
+	return srcLocal, dstLocal, nil
+}
@@ -0,0 +1,286 @@
+package rclone
+
+import (
+	"context"
+	beConfig "desktop/backend/config"
+	"desktop/backend/dto"
+	"desktop/backend/models"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/cmd/bisync"
+	"github.com/rclone/rclone/cmd/bisync/bilib"
+	"github.com/rclone/rclone/fs"
+)
+
+// memoryBisyncStore keeps bisync state in memory for tests
+type memoryBisyncStore struct {
+	known         bool
+	resyncPending bool
+	filterHash    string
+	conflicts     []models.BisyncConflict
+}
+
+func (m *memoryBisyncStore) PairState(profile models.Profile) (bool, bool, error) {
+	return m.known, m.resyncPending, nil
+}
+
+func (m *memoryBisyncStore) ResyncReason(profile models.Profile, filterHash string) (string, error) {
+	if m.filterHash != filterHash {
+		return "filter rules changed", nil
+	}
+	return "", nil
+}
+
+func (m *memoryBisyncStore) RecordRun(profile models.Profile, filterHash string, resync bool, runErr error) error {
+	m.known = true
+	if resync {
+		m.resyncPending = runErr != nil
+		if runErr == nil {
+			m.filterHash = filterHash
+		}
+	}
+	return nil
+}
+
+func (m *memoryBisyncStore) RecordConflicts(profile models.Profile, conflicts []models.BisyncConflict) error {
+	m.conflicts = append(m.conflicts, conflicts...)
+	return nil
+}
+
+func runTestBiSync(t *testing.T, profile models.Profile, id int) {
+	t.Helper()
+	if err := tryTestBiSync(t, profile, id); err != nil {
+		t.Fatalf("BiSync failed: %v", err)
+	}
+}
+
+// tryTestBiSync runs a bisync of the profile and returns its error
+func tryTestBiSync(t *testing.T, profile models.Profile, id int) error {
+	t.Helper()
+	ctx, err := NewTaskContext(context.Background(), id)
+	if err != nil {
+		t.Fatalf("NewTaskContext failed: %v", err)
+	}
+	outStatus := make(chan *dto.SyncStatusDTO, 100)
+	go func() {
+		for range outStatus {
+		}
+	}()
+	err = BiSync(ctx, beConfig.Config{}, profile, false, outStatus)
+	close(outStatus)
+	return err
+}
+
+// TestBisyncConflictInbox tests that conflicts renamed by bisync are recorded and can be resolved
+func TestBisyncConflictInbox(t *testing.T) {
+	oldWorkdir := bisync.DefaultWorkdir
+	bisync.DefaultWorkdir = t.TempDir()
+	defer func() { bisync.DefaultWorkdir = oldWorkdir }()
+
+	store := &memoryBisyncStore{}
+	SetBisyncStateStore(store)
+	defer SetBisyncStateStore(nil)
+
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+	writeTestFile(t, srcDir, "shared.txt", "base")
+	writeTestFile(t, dstDir, "shared.txt", "base")
+
+	profile := models.Profile{
+		Name:               "conflict-test",
+		From:               srcDir,
+		To:                 dstDir,
+		Parallel:           2,
+		ConflictResolution: "newer",
+		ConflictLoser:      "num",
+	}
+
+	// First run has no listings and resyncs
+	runTestBiSync(t, profile, 9101)
+
+	// Edit both sides, the destination more recently
+	writeTestFile(t, srcDir, "shared.txt", "source edit")
+	writeTestFile(t, dstDir, "shared.txt", "destination edit!")
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(srcDir, "shared.txt"), past, past); err != nil {
+		t.Fatal(err)
+	}
+	runTestBiSync(t, profile, 9102)
+
+	if len(store.conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %+v", store.conflicts)
+	}
+	c := store.conflicts[0]
+	if c.Path != "shared.txt" || c.Winner != "destination" {
+		t.Errorf("unexpected conflict: %+v", c)
+	}
+	if c.Destination.Path != "shared.txt" || c.Destination.Size != int64(len("destination edit!")) {
+		t.Errorf("unexpected destination version: %+v", c.Destination)
+	}
+	if c.Source.Path == "shared.txt" || c.Source.Size != int64(len("source edit")) {
+		t.Errorf("unexpected source version: %+v", c.Source)
+	}
+
+	// An edit that keeps the size and modtime is still caught by the hash
+	sharedDst := filepath.Join(dstDir, "shared.txt")
+	writeTestFile(t, dstDir, "shared.txt", "destination edit?")
+	if err := os.Chtimes(sharedDst, c.Destination.ModTime, c.Destination.ModTime); err != nil {
+		t.Fatal(err)
+	}
+	if err := ResolveBisyncConflict(context.Background(), c, models.ConflictKeepSource); err == nil {
+		t.Fatal("expected resolving a changed conflict to fail")
+	}
+	writeTestFile(t, dstDir, "shared.txt", "destination edit!")
+	if err := os.Chtimes(sharedDst, c.Destination.ModTime, c.Destination.ModTime); err != nil {
+		t.Fatal(err)
+	}
+
+	// Keeping the source restores its content under the original name on both sides
+	if err := ResolveBisyncConflict(context.Background(), c, models.ConflictKeepSource); err != nil {
+		t.Fatalf("ResolveBisyncConflict failed: %v", err)
+	}
+	for _, dir := range []string{srcDir, dstDir} {
+		data, err := os.ReadFile(filepath.Join(dir, "shared.txt"))
+		if err != nil || string(data) != "source edit" {
+			t.Errorf("%s: expected source content, got %q (%v)", dir, data, err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, c.Source.Path)); !os.IsNotExist(err) {
+			t.Errorf("%s: expected renamed copy to be gone", dir)
+		}
+	}
+}
+
+// TestConflictCollectorScopedToRun tests that concurrent runs only collect their own conflicts
+func TestConflictCollectorScopedToRun(t *testing.T) {
+	ctx := context.Background()
+	newFs := func(dir string) fs.Fs {
+		f, err := fs.NewFs(ctx, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+	a1, a2 := newFs(t.TempDir()), newFs(t.TempDir())
+	b1, b2 := newFs(t.TempDir()), newFs(t.TempDir())
+	a := newConflictCollector(a1, a2)
+	b := newConflictCollector(b1, b2)
+	defer b.stop()
+
+	// Both runs hit a conflict on the same file, with their log lines interleaved
+	dispatchConflictOutput(slog.LevelWarn, "- WARNING  New or changed in both paths - doc.txt")
+	dispatchConflictOutput(slog.LevelInfo, "- Path1  Not renaming Path1 copy, as it was determined the winner - "+bilib.FsPath(a1)+"doc.txt")
+	dispatchConflictOutput(slog.LevelWarn, "- WARNING  New or changed in both paths - doc.txt")
+	dispatchConflictOutput(slog.LevelInfo, "- Path1  Renaming Path1 copy - "+bilib.FsPath(b1)+"doc.conflict1.txt")
+	dispatchConflictOutput(slog.LevelInfo, "- Path2  Renaming Path2 copy - "+bilib.FsPath(a2)+"doc.txt.conflict1")
+	dispatchConflictOutput(slog.LevelInfo, "- Path2  Not renaming Path2 copy, as it was determined the winner - "+bilib.FsPath(b2)+"doc.txt")
+
+	a.stop()
+	// Output after stopping is ignored
+	dispatchConflictOutput(slog.LevelWarn, "- WARNING  New or changed in both paths - late.txt")
+	if len(a.pending) != 0 {
+		t.Errorf("stopped collector still receives output: %+v", a.pending)
+	}
+
+	if len(a.found) != 1 || a.found[0].winner != 1 || a.found[0].names[2] != "doc.txt.conflict1" {
+		t.Errorf("unexpected conflicts for run a: %+v", a.found)
+	}
+	if len(b.found) != 1 || b.found[0].winner != 2 || b.found[0].names[1] != "doc.conflict1.txt" {
+		t.Errorf("unexpected conflicts for run b: %+v", b.found)
+	}
+}
+
+// TestBisyncConflictsFromRemotes tests that conflicts are found on the remotes when bisync's
+// log lines are not seen, and that copies left by earlier runs are not recorded again
+func TestBisyncConflictsFromRemotes(t *testing.T) {
+	oldWorkdir := bisync.DefaultWorkdir
+	bisync.DefaultWorkdir = t.TempDir()
+	defer func() { bisync.DefaultWorkdir = oldWorkdir }()
+
+	store := &memoryBisyncStore{}
+	SetBisyncStateStore(store)
+	defer SetBisyncStateStore(nil)
+
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+	writeTestFile(t, srcDir, "shared.txt", "base")
+	writeTestFile(t, dstDir, "shared.txt", "base")
+	profile := models.Profile{
+		Name:               "conflict-disk-test",
+		From:               srcDir,
+		To:                 dstDir,
+		Parallel:           2,
+		ConflictResolution: "none",
+		ConflictLoser:      "num",
+	}
+	runTestBiSync(t, profile, 9201)
+
+	// Raise the log level above the lines bisync reports conflicts with
+	ci := fs.GetConfig(context.Background())
+	oldLevel := ci.LogLevel
+	ci.LogLevel = fs.LogLevelError
+	defer func() { ci.LogLevel = oldLevel }()
+
+	writeTestFile(t, srcDir, "shared.txt", "source edit")
+	writeTestFile(t, dstDir, "shared.txt", "destination edit!")
+	runTestBiSync(t, profile, 9202)
+
+	if len(store.conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %+v", store.conflicts)
+	}
+	c := store.conflicts[0]
+	if c.Path != "shared.txt" || c.Winner != "none" {
+		t.Errorf("unexpected conflict: %+v", c)
+	}
+	// Without a winner the destination copy gets the lower number
+	if c.Destination.Path != "shared.txt.conflict1" || c.Destination.Size != int64(len("destination edit!")) {
+		t.Errorf("unexpected destination version: %+v", c.Destination)
+	}
+	if c.Source.Path != "shared.txt.conflict2" || c.Source.Size != int64(len("source edit")) {
+		t.Errorf("unexpected source version: %+v", c.Source)
+	}
+
+	// The copies are still there on the next run, which found no new conflict
+	writeTestFile(t, srcDir, "other.txt", "other")
+	runTestBiSync(t, profile, 9203)
+	if len(store.conflicts) != 1 {
+		t.Errorf("expected existing copies not to be recorded again, got %+v", store.conflicts)
+	}
+}
+
+// TestConflictNamer tests that conflict copies are traced back to their original path and side
+func TestConflictNamer(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		profile  models.Profile
+		name     string
+		original string
+		side     int
+	}{
+		{models.Profile{}, "a.txt.conflict3", "a.txt", 0},
+		{models.Profile{}, "dir/a.txt.conflict12", "dir/a.txt", 0},
+		{models.Profile{ConflictLoser: "pathname"}, "a.txt.conflict2", "a.txt", 2},
+		{models.Profile{ConflictSuffix: "dst,src"}, "a.txt.src1", "a.txt", 2},
+		{models.Profile{ConflictSuffix: "dst,src", ConflictLoser: "pathname"}, "a.txt.dst", "a.txt", 1},
+		{models.Profile{ConflictSuffix: "{DateOnly}-conflict"}, "a.txt.2026-01-02-conflict1", "a.txt", 0},
+		{models.Profile{}, "a.txt.conflict", "", 0},
+		{models.Profile{}, "a.txt", "", 0},
+	}
+	for _, tt := range tests {
+		original, cp, ok := newConflictNamer(tt.profile).parse(ctx, tt.name)
+		if ok != (tt.original != "") || original != tt.original || ok && cp.side != tt.side {
+			t.Errorf("%+v %s: got %q side %d (%v), want %q side %d", tt.profile, tt.name, original, cp.side, ok, tt.original, tt.side)
+		}
+	}
+
+	// With --suffix-keep-extension the suffix goes before the extension
+	ctx, ci := fs.AddConfig(ctx)
+	ci.SuffixKeepExtension = true
+	original, _, ok := newConflictNamer(models.Profile{}).parse(ctx, "a.conflict1.txt")
+	if !ok || original != "a.txt" {
+		t.Errorf("expected a.conflict1.txt to be a copy of a.txt, got %q (%v)", original, ok)
+	}
+}
@@ -0,0 +1,176 @@
+package rclone
+
+import (
+	"context"
+	"crypto/sha256"
+	"desktop/backend/models"
+	"desktop/backend/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/cmd/bisync"
+	"github.com/rclone/rclone/cmd/bisync/bilib"
+	"github.com/rclone/rclone/fs"
+)
+
+// BisyncStateStore persists the per-pair bookkeeping bisync needs between runs
+type BisyncStateStore interface {
+	// PairState reports whether state was recorded for the pair and whether a resync was
+	// requested for it. It is only called for pairs whose listings are missing.
+	PairState(profile models.Profile) (known, resyncPending bool, err error)
+	// ResyncReason returns a non-empty reason when the pair must be resynced before its next
+	// run. It is only called for pairs whose listings exist.
+	ResyncReason(profile models.Profile, filterHash string) (string, error)
+	// RecordRun stores the outcome of a bisync run of the pair
+	RecordRun(profile models.Profile, filterHash string, resync bool, runErr error) error
+	// RecordConflicts stores the conflicts bisync renamed during a run of the pair
+	RecordConflicts(profile models.Profile, conflicts []models.BisyncConflict) error
+}
+
+var (
+	bisyncStateStore   BisyncStateStore
+	bisyncStateStoreMu sync.RWMutex
+)
+
+// SetBisyncStateStore sets the store BiSync uses to decide when a resync is needed (called from main.go)
+func SetBisyncStateStore(store BisyncStateStore) {
+	bisyncStateStoreMu.Lock()
+	defer bisyncStateStoreMu.Unlock()
+	bisyncStateStore = store
+}
+
+func getBisyncStateStore() BisyncStateStore {
+	bisyncStateStoreMu.RLock()
+	defer bisyncStateStoreMu.RUnlock()
+	return bisyncStateStore
+}
+
+// BisyncSession locates the files rclone keeps for a bisync pair
+type BisyncSession struct {
+	Workdir  string
+	BasePath string
+	Listing1 string // destination listing
+	Listing2 string // source listing
+	LockFile string
+}
+
+// BisyncLock is the content of a bisync lock file
+type BisyncLock struct {
+	PID       string
+	ExpiresAt time.Time
+}
+
+// Stale reports whether the lock can no longer belong to a running bisync of this process
+func (l *BisyncLock) Stale() bool {
+	if !l.ExpiresAt.IsZero() && l.ExpiresAt.Before(time.Now()) {
+		return true
+	}
+	return l.PID != strconv.Itoa(os.Getpid())
+}
+
+// BisyncFilterHash hashes the include/exclude rules of a profile, "0" when it has none
+func BisyncFilterHash(profile models.Profile) (string, error) {
+	if len(profile.IncludedPaths)+len(profile.ExcludedPaths) == 0 {
+		return "0", nil
+	}
+	return utils.CalculateContentHash(utils.MergeBytes([]byte(strings.Join(profile.IncludedPaths, "")), []byte(strings.Join(profile.ExcludedPaths, ""))), sha256.New)
+}
+
+// GetBisyncSession resolves the listing and lock file paths of the pair, using the same
+// session naming as rclone. BiSync runs with the destination as path1.
+func GetBisyncSession(ctx context.Context, from, to string) (*BisyncSession, error) {
+	srcFs, err := fs.NewFs(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize source filesystem: %w", err)
+	}
+	dstFs, err := fs.NewFs(ctx, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize destination filesystem: %w", err)
+	}
+	return newBisyncSession(dstFs, srcFs)
+}
+
+func newBisyncSession(fs1, fs2 fs.Fs) (*BisyncSession, error) {
+	workdir, err := filepath.Abs(bisync.DefaultWorkdir)
+	if err != nil {
+		return nil, err
+	}
+	basePath := filepath.Join(workdir, bilib.SessionName(fs1, fs2))
+	return &BisyncSession{
+		Workdir:  workdir,
+		BasePath: basePath,
+		Listing1: basePath + ".path1.lst",
+		Listing2: basePath + ".path2.lst",
+		LockFile: basePath + ".lck",
+	}, nil
+}
+
+// HasListings reports whether both prior listings exist, i.e. the pair can run without a resync
+func (s *BisyncSession) HasListings() bool {
+	return bilib.FileExists(s.Listing1) && bilib.FileExists(s.Listing2)
+}
+
+// HasFailedListings reports whether rclone set the listings aside after a critical error.
+// It renames them to .lst-err and refuses to run the pair until it is resynced on purpose.
+func (s *BisyncSession) HasFailedListings() bool {
+	return bilib.FileExists(s.Listing1+"-err") || bilib.FileExists(s.Listing2+"-err")
+}
+
+// ReadLock returns the lock held on the pair, or nil when it is not locked
+func (s *BisyncSession) ReadLock() (*BisyncLock, error) {
+	data, err := os.ReadFile(s.LockFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lock file: %w", err)
+	}
+
+	// A fresh lock file holds only the PID until bisync renews it as JSON
+	var content struct {
+		PID         string
+		TimeExpires time.Time
+	}
+	if err := json.Unmarshal(data, &content); err != nil {
+		return &BisyncLock{PID: strings.TrimSpace(string(data))}, nil
+	}
+	return &BisyncLock{PID: content.PID, ExpiresAt: content.TimeExpires}, nil
+}
+
+// RemoveLock deletes the lock file of the pair
+func (s *BisyncSession) RemoveLock() error {
+	if err := os.Remove(s.LockFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove lock file: %w", err)
+	}
+	return nil
+}
+
+// RemoveListings deletes the listings of the pair, including backup and failed copies
+func (s *BisyncSession) RemoveListings() error {
+	entries, err := os.ReadDir(s.Workdir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read bisync workdir: %w", err)
+	}
+
+	prefix1, prefix2 := filepath.Base(s.Listing1), filepath.Base(s.Listing2)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !(strings.HasPrefix(name, prefix1) || strings.HasPrefix(name, prefix2)) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.Workdir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package rclone
+
+import (
+	"context"
+	"desktop/backend/models"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rclone/rclone/cmd/bisync"
+)
+
+// TestBisyncMissingListings tests that only a first run or a requested resync resyncs a
+// pair without listings, and that listings set aside after a critical error are not
+// papered over by an automatic resync
+func TestBisyncMissingListings(t *testing.T) {
+	oldWorkdir := bisync.DefaultWorkdir
+	bisync.DefaultWorkdir = t.TempDir()
+	defer func() { bisync.DefaultWorkdir = oldWorkdir }()
+
+	store := &memoryBisyncStore{}
+	SetBisyncStateStore(store)
+	defer SetBisyncStateStore(nil)
+
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+	writeTestFile(t, srcDir, "a.txt", "a")
+	profile := models.Profile{
+		Name:     "listings-test",
+		From:     srcDir,
+		To:       dstDir,
+		Parallel: 2,
+	}
+
+	// The first run has no state and resyncs
+	runTestBiSync(t, profile, 9301)
+	if _, err := os.Stat(filepath.Join(dstDir, "a.txt")); err != nil {
+		t.Fatalf("expected the first run to copy a.txt: %v", err)
+	}
+
+	session, err := GetBisyncSession(context.Background(), srcDir, dstDir)
+	if err != nil {
+		t.Fatalf("GetBisyncSession failed: %v", err)
+	}
+
+	// rclone sets the listings aside as .lst-err after a critical error
+	for _, listing := range []string{session.Listing1, session.Listing2} {
+		if err := os.Rename(listing, listing+"-err"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestFile(t, srcDir, "b.txt", "b")
+	err = tryTestBiSync(t, profile, 9302)
+	if err == nil || !strings.Contains(err.Error(), "critical error") {
+		t.Fatalf("expected the run to refuse failed listings, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dstDir, "b.txt")); !os.IsNotExist(err) {
+		t.Error("expected nothing to be synced while the listings are set aside")
+	}
+
+	// Listings of a known pair that are simply gone are refused too
+	if err := session.RemoveListings(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tryTestBiSync(t, profile, 9303); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Fatalf("expected the run to refuse missing listings, got %v", err)
+	}
+
+	// A requested resync goes ahead
+	store.resyncPending = true
+	runTestBiSync(t, profile, 9304)
+	if _, err := os.Stat(filepath.Join(dstDir, "b.txt")); err != nil {
+		t.Errorf("expected the resync to copy b.txt: %v", err)
+	}
+	if store.resyncPending {
+		t.Error("expected the resync to clear the pending request")
+	}
+}
@@ -0,0 +1,280 @@
+package rclone
+
+import (
+	"bytes"
+	"context"
+	"desktop/backend/models"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/lib/random"
+)
+
+// probeModTime is the modtime written by the probe; the sub-second part shows whether
+// the remote keeps it
+var probeModTime = time.Date(2020, time.January, 2, 3, 4, 5, 678901234, time.UTC)
+
+// probes holds the latest probe of each remote, used to refine profile warnings
+var probes = struct {
+	sync.Mutex
+	byRemote map[string]*models.RemoteProbe
+}{byRemote: map[string]*models.RemoteProbe{}}
+
+// GetCapabilities reports what the remote at remotePath supports according to its backend
+func GetCapabilities(ctx context.Context, remotePath string) (*models.RemoteCapabilities, error) {
+	remoteFs, err := fs.NewFs(ctx, remotePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize filesystem %q: %w", remotePath, err)
+	}
+	return capabilities(remotePath, remoteFs), nil
+}
+
+// capabilities describes the features of an initialized filesystem
+func capabilities(remotePath string, f fs.Fs) *models.RemoteCapabilities {
+	features := f.Features()
+	caps := &models.RemoteCapabilities{
+		Remote:          remotePath,
+		Type:            backendName(remotePath),
+		Hashes:          []string{},
+		ServerSideCopy:  features.Copy != nil,
+		ServerSideMove:  features.Move != nil,
+		DirMove:         features.DirMove != nil,
+		ListR:           features.ListR != nil,
+		About:           features.About != nil,
+		PublicLink:      features.PublicLink != nil,
+		CleanUp:         features.CleanUp != nil,
+		Purge:           features.Purge != nil,
+		CaseInsensitive: features.CaseInsensitive,
+		EmptyDirs:       features.CanHaveEmptyDirectories,
+	}
+	for _, ht := range f.Hashes().Array() {
+		caps.Hashes = append(caps.Hashes, ht.String())
+	}
+	if precision := f.Precision(); precision != fs.ModTimeNotSupported {
+		caps.ModTimeSupported = true
+		caps.ModTimePrecision = precision.String()
+	}
+	return caps
+}
+
+// backendName returns the name of the backend serving remotePath, e.g. "s3"
+func backendName(remotePath string) string {
+	info, _, _, _, err := fs.ParseRemote(remotePath)
+	if err != nil {
+		return ""
+	}
+	return info.Name
+}
+
+// ProbeRemote measures how the remote at remotePath really behaves by writing a small
+// temporary object, stating, reading and hashing it, then deleting it. Failed steps are
+// part of the report; an error is only returned when the remote can't be opened. The
+// result is kept to refine ProfileWarnings.
+func ProbeRemote(ctx context.Context, remotePath string) (*models.RemoteProbe, error) {
+	remoteFs, err := fs.NewFs(ctx, remotePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize filesystem %q: %w", remotePath, err)
+	}
+
+	probe := &models.RemoteProbe{
+		Remote:         remotePath,
+		Capabilities:   *capabilities(remotePath, remoteFs),
+		Steps:          []models.ProbeStep{},
+		VerifiedHashes: []string{},
+		ProbedAt:       time.Now(),
+	}
+	step := func(name string, fn func() error) bool {
+		start := time.Now()
+		err := fn()
+		s := models.ProbeStep{Name: name, Ok: err == nil, Duration: time.Since(start).Round(time.Millisecond).String()}
+		if err != nil {
+			s.Error = err.Error()
+		}
+		probe.Steps = append(probe.Steps, s)
+		return err == nil
+	}
+
+	data := []byte(random.String(4096))
+	leaf := ".ns-drive-probe-" + random.String(8)
+	var obj fs.Object
+
+	probe.Writable = step("write", func() error {
+		obj, err = operations.Rcat(ctx, remoteFs, leaf, io.NopCloser(bytes.NewReader(data)), probeModTime, nil)
+		return err
+	})
+	if probe.Writable {
+		defer func() {
+			// Don't leave the probe object behind when a step failed before deleting it
+			if obj != nil {
+				_ = obj.Remove(ctx)
+			}
+		}()
+
+		step("stat", func() error {
+			o, err := remoteFs.NewObject(ctx, leaf)
+			if err != nil {
+				return err
+			}
+			if o.Size() != int64(len(data)) {
+				return fmt.Errorf("size is %d, expected %d", o.Size(), len(data))
+			}
+			drift := o.ModTime(ctx).Sub(probeModTime).Abs()
+			probe.ModTimeDrift = drift.String()
+			precision := remoteFs.Precision()
+			probe.ModTimePreserved = precision != fs.ModTimeNotSupported && drift <= precision
+			return nil
+		})
+
+		step("read", func() error {
+			rc, err := obj.Open(ctx)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = rc.Close() }()
+			got, err := io.ReadAll(rc)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(got, data) {
+				return errors.New("content read back differs from content written")
+			}
+			return nil
+		})
+
+		if hashes := remoteFs.Hashes(); hashes.Count() > 0 {
+			step("hash", func() error {
+				sums, err := hash.StreamTypes(bytes.NewReader(data), hashes)
+				if err != nil {
+					return err
+				}
+				var failed []string
+				for _, ht := range hashes.Array() {
+					got, err := obj.Hash(ctx, ht)
+					if err == nil && got == sums[ht] {
+						probe.VerifiedHashes = append(probe.VerifiedHashes, ht.String())
+					} else if err == nil && got == "" {
+						continue // the backend may not know the hash of every object
+					} else {
+						failed = append(failed, ht.String())
+					}
+				}
+				if len(failed) > 0 {
+					return fmt.Errorf("%s hash differs from the content", strings.Join(failed, ", "))
+				}
+				return nil
+			})
+		}
+
+		step("delete", func() error {
+			if err := obj.Remove(ctx); err != nil {
+				return err
+			}
+			obj = nil
+			if _, err := remoteFs.NewObject(ctx, leaf); !errors.Is(err, fs.ErrorObjectNotFound) {
+				return fmt.Errorf("object still listed after delete: %v", err)
+			}
+			return nil
+		})
+	}
+
+	probe.Ok = true
+	for _, s := range probe.Steps {
+		probe.Ok = probe.Ok && s.Ok
+	}
+
+	probes.Lock()
+	probes.byRemote[remoteKey(remoteFs)] = probe
+	probes.Unlock()
+	return probe, nil
+}
+
+// remoteKey identifies the remote a filesystem belongs to, regardless of the path
+func remoteKey(f fs.Fs) string {
+	return f.Name()
+}
+
+// lastProbe returns the latest probe of the remote a filesystem belongs to
+func lastProbe(f fs.Fs) *models.RemoteProbe {
+	probes.Lock()
+	defer probes.Unlock()
+	return probes.byRemote[remoteKey(f)]
+}
+
+// ProfileWarnings reports settings of a profile that won't work as expected between its
+// two remotes, e.g. checksum comparison without a hash type in common. Results of
+// ProbeRemote refine the declared capabilities.
+func ProfileWarnings(ctx context.Context, profile models.Profile) ([]string, error) {
+	srcFs, err := fs.NewFs(ctx, profile.From)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize filesystem %q: %w", profile.From, err)
+	}
+	dstFs, err := fs.NewFs(ctx, profile.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize filesystem %q: %w", profile.To, err)
+	}
+
+	warnings := []string{}
+	srcHashes, dstHashes := verifiedHashes(srcFs), verifiedHashes(dstFs)
+	if !profile.SizeOnly && srcHashes.Overlap(dstHashes).Count() == 0 {
+		warnings = append(warnings, fmt.Sprintf("%s and %s have no hash type in common: files are compared by size only, renames can't be tracked and check needs to download both sides", profile.From, profile.To))
+	}
+
+	if profile.UpdateMode {
+		if !modTimeReliable(srcFs) {
+			warnings = append(warnings, fmt.Sprintf("%s doesn't keep modification times: update mode can't tell which side is newer", profile.From))
+		}
+		if !modTimeReliable(dstFs) {
+			warnings = append(warnings, fmt.Sprintf("%s doesn't keep modification times: update mode can't tell which side is newer", profile.To))
+		}
+	}
+
+	if srcFs.Features().CaseInsensitive != dstFs.Features().CaseInsensitive {
+		insensitive := profile.From
+		if dstFs.Features().CaseInsensitive {
+			insensitive = profile.To
+		}
+		warnings = append(warnings, fmt.Sprintf("%s is case-insensitive: files whose names differ only in case will overwrite each other", insensitive))
+	}
+
+	if probe := lastProbe(dstFs); probe != nil && !probe.Writable {
+		warnings = append(warnings, fmt.Sprintf("the last probe of %s couldn't write to it", probe.Remote))
+	}
+	return warnings, nil
+}
+
+// verifiedHashes returns the hashes of a filesystem, leaving out those its last probe
+// found wrong
+func verifiedHashes(f fs.Fs) hash.Set {
+	hashes := f.Hashes()
+	probe := lastProbe(f)
+	if probe == nil || !probe.Writable {
+		return hashes
+	}
+	verified := hash.NewHashSet()
+	for _, name := range probe.VerifiedHashes {
+		var ht hash.Type
+		if err := ht.Set(name); err == nil && hashes.Contains(ht) {
+			verified.Add(ht)
+		}
+	}
+	return verified
+}
+
+// modTimeReliable tells whether a filesystem keeps modification times, trusting its last
+// probe over the declared precision
+func modTimeReliable(f fs.Fs) bool {
+	if f.Precision() == fs.ModTimeNotSupported {
+		return false
+	}
+	if probe := lastProbe(f); probe != nil && probe.Writable {
+		return probe.ModTimePreserved
+	}
+	return true
+}
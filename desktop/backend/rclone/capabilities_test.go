@@ -0,0 +1,73 @@
+package rclone
+
+import (
+	"context"
+	"desktop/backend/models"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestProbeRemote tests the capability report and probe of a local directory and a
+// read-only HTTP remote
+func TestProbeRemote(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	caps, err := GetCapabilities(ctx, dir)
+	if err != nil {
+		t.Fatalf("GetCapabilities failed: %v", err)
+	}
+	if caps.Type != "local" || !caps.ModTimeSupported || !slices.Contains(caps.Hashes, "md5") || !caps.ServerSideMove {
+		t.Errorf("unexpected local capabilities %+v", caps)
+	}
+
+	probe, err := ProbeRemote(ctx, dir)
+	if err != nil {
+		t.Fatalf("ProbeRemote failed: %v", err)
+	}
+	if !probe.Ok || !probe.Writable || !probe.ModTimePreserved {
+		t.Errorf("expected local probe to pass, got %+v", probe)
+	}
+	if !slices.Contains(probe.VerifiedHashes, "md5") {
+		t.Errorf("expected md5 to be verified, got %v", probe.VerifiedHashes)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("expected probe object to be deleted, found %d entries", len(entries))
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "site"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	addr := startTestServer(t, "http", filepath.Join(dir, "site"), nil)
+	httpRemote := ":http,url='http://" + addr + "/':"
+	probe, err = ProbeRemote(ctx, httpRemote)
+	if err != nil {
+		t.Fatalf("ProbeRemote failed: %v", err)
+	}
+	if probe.Ok || probe.Writable || len(probe.Steps) != 1 || probe.Steps[0].Error == "" {
+		t.Errorf("expected http probe to fail writing, got %+v", probe)
+	}
+	if probe.Capabilities.Type != "http" || probe.Capabilities.ServerSideCopy {
+		t.Errorf("unexpected http capabilities %+v", probe.Capabilities)
+	}
+
+	warnings, err := ProfileWarnings(ctx, models.Profile{From: dir, To: httpRemote, UpdateMode: true})
+	if err != nil {
+		t.Fatalf("ProfileWarnings failed: %v", err)
+	}
+	joined := strings.Join(warnings, "\n")
+	if !strings.Contains(joined, "no hash type in common") || !strings.Contains(joined, "couldn't write") {
+		t.Errorf("expected hash and write warnings, got %v", warnings)
+	}
+
+	warnings, err = ProfileWarnings(ctx, models.Profile{From: dir, To: t.TempDir(), UpdateMode: true})
+	if err != nil {
+		t.Fatalf("ProfileWarnings failed: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("expected no warnings between local directories, got %v", warnings)
+	}
+}
@@ -0,0 +1,210 @@
+package rclone
+
+import (
+	"bytes"
+	"context"
+	beConfig "desktop/backend/config"
+	"desktop/backend/dto"
+	"desktop/backend/models"
+	"desktop/backend/utils"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/operations"
+)
+
+// Check compares source and destination and returns a structured report of matching,
+// differing, missing and errored paths with a per-directory rollup. With download set,
+// file contents are compared byte by byte, for remotes without a common hash.
+//
+// Differences are part of the report, not an error; an error is returned only when the
+// check itself could not complete.
+func Check(ctx context.Context, config beConfig.Config, profile models.Profile, download bool, outStatus chan *dto.SyncStatusDTO) (*models.CheckReport, error) {
+	fsConfig := fs.GetConfig(ctx)
+	fsConfig.Checkers = profile.Parallel
+
+	srcFs, err := newTaskFs(ctx, profile.From)
+	if utils.HandleError(err, "Failed to initialize source filesystem", nil, nil) != nil {
+		return nil, err
+	}
+
+	dstFs, err := newTaskFs(ctx, profile.To)
+	if utils.HandleError(err, "Failed to initialize destination filesystem", nil, nil) != nil {
+		return nil, err
+	}
+
+	ctx = applyFiltersAndBandwidth(ctx, fsConfig, profile)
+
+	ctx, err = ApplyProfileOptions(ctx, profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply profile options: %w", err)
+	}
+
+	if err := fsConfig.Reload(ctx); err != nil {
+		return nil, err
+	}
+
+	collector := &checkCollector{}
+	opt := &operations.CheckOpt{
+		Fsrc:     srcFs,
+		Fdst:     dstFs,
+		Combined: collector,
+	}
+
+	err = utils.RunRcloneWithRetryAndStats(ctx, false, false, outStatus, func() error {
+		if download {
+			return utils.HandleError(operations.CheckDownload(ctx, opt), "Check failed", nil, nil)
+		}
+		return utils.HandleError(operations.Check(ctx, opt), "Check failed", nil, nil)
+	})
+
+	report := collector.report()
+	report.Download = download
+	report.HashType = srcFs.Hashes().Overlap(dstFs.Hashes()).GetOne().String()
+	if download {
+		report.HashType = hash.None.String()
+	}
+
+	// "N differences found" only restates the report
+	if err != nil && ctx.Err() == nil && len(report.Errors) == 0 && strings.HasSuffix(err.Error(), "differences found") {
+		err = nil
+	}
+	return report, err
+}
+
+// checkCollector is an io.Writer receiving rclone's combined check output,
+// one "<sigil> <path>" line per file
+type checkCollector struct {
+	mu      sync.Mutex
+	partial []byte
+	lines   []string
+}
+
+func (c *checkCollector) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := append(c.partial, p...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		c.lines = append(c.lines, string(data[:i]))
+		data = data[i+1:]
+	}
+	c.partial = append([]byte(nil), data...)
+	return len(p), nil
+}
+
+// report sorts the collected lines into a CheckReport and builds its tree
+func (c *checkCollector) report() *models.CheckReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report := &models.CheckReport{
+		Match:        []string{},
+		Differ:       []string{},
+		MissingOnSrc: []string{},
+		MissingOnDst: []string{},
+		Errors:       []string{},
+	}
+	for _, line := range c.lines {
+		if len(line) < 3 {
+			continue
+		}
+		p := line[2:]
+		switch line[0] {
+		case '=':
+			report.Match = append(report.Match, p)
+		case '*':
+			report.Differ = append(report.Differ, p)
+		case '-':
+			report.MissingOnSrc = append(report.MissingOnSrc, p)
+		case '+':
+			report.MissingOnDst = append(report.MissingOnDst, p)
+		case '!':
+			report.Errors = append(report.Errors, p)
+		}
+	}
+	for _, list := range [][]string{report.Match, report.Differ, report.MissingOnSrc, report.MissingOnDst, report.Errors} {
+		sort.Strings(list)
+	}
+
+	report.InSync = len(report.Differ)+len(report.MissingOnSrc)+len(report.MissingOnDst)+len(report.Errors) == 0
+	report.Tree = BuildCheckTree(report)
+	return report
+}
+
+// BuildCheckTree rolls the paths of a check report up into a directory tree whose
+// nodes count the results below them, so out-of-sync folders can be spotted quickly
+func BuildCheckTree(report *models.CheckReport) *models.CheckTreeNode {
+	root := &models.CheckTreeNode{IsDir: true}
+	dirs := map[string]*models.CheckTreeNode{"": root}
+
+	var dirNode func(dir string) *models.CheckTreeNode
+	dirNode = func(dir string) *models.CheckTreeNode {
+		if node, ok := dirs[dir]; ok {
+			return node
+		}
+		parent := dirNode(parentDir(dir))
+		node := &models.CheckTreeNode{Name: path.Base(dir), Path: dir, IsDir: true}
+		parent.Children = append(parent.Children, node)
+		dirs[dir] = node
+		return node
+	}
+
+	add := func(paths []string, status string, count func(n *models.CheckTreeNode)) {
+		for _, p := range paths {
+			dir := parentDir(p)
+			parent := dirNode(dir)
+			file := &models.CheckTreeNode{Name: path.Base(p), Path: p, Status: status}
+			count(file)
+			parent.Children = append(parent.Children, file)
+			for d := dir; ; d = parentDir(d) {
+				count(dirs[d])
+				if d == "" {
+					break
+				}
+			}
+		}
+	}
+
+	add(report.Match, "match", func(n *models.CheckTreeNode) { n.Match++ })
+	add(report.Differ, "differ", func(n *models.CheckTreeNode) { n.Differ++ })
+	add(report.MissingOnSrc, "missing_on_src", func(n *models.CheckTreeNode) { n.MissingOnSrc++ })
+	add(report.MissingOnDst, "missing_on_dst", func(n *models.CheckTreeNode) { n.MissingOnDst++ })
+	add(report.Errors, "error", func(n *models.CheckTreeNode) { n.Errors++ })
+
+	finishCheckTree(root)
+	return root
+}
+
+// finishCheckTree sets InSync and sorts children, directories first
+func finishCheckTree(node *models.CheckTreeNode) {
+	node.InSync = node.Differ+node.MissingOnSrc+node.MissingOnDst+node.Errors == 0
+	sort.Slice(node.Children, func(i, j int) bool {
+		a, b := node.Children[i], node.Children[j]
+		if a.IsDir != b.IsDir {
+			return a.IsDir
+		}
+		return a.Name < b.Name
+	})
+	for _, child := range node.Children {
+		finishCheckTree(child)
+	}
+}
+
+// parentDir returns the parent directory of a slash separated path, "" for the root
+func parentDir(p string) string {
+	dir := path.Dir(p)
+	if dir == "." || dir == "/" {
+		return ""
+	}
+	return dir
+}
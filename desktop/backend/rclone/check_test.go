@@ -0,0 +1,84 @@
+package rclone
+
+import (
+	"context"
+	beConfig "desktop/backend/config"
+	"desktop/backend/dto"
+	"desktop/backend/models"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestCheckReport tests that Check sorts paths into the report and rolls them up per directory
+func TestCheckReport(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+	for _, dir := range []string{srcDir, dstDir} {
+		if err := os.MkdirAll(filepath.Join(dir, "docs", "deep"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(dir, "photos"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeTestFile(t, srcDir, "photos/same.jpg", "same")
+	writeTestFile(t, dstDir, "photos/same.jpg", "same")
+	writeTestFile(t, srcDir, "docs/deep/diff.txt", "source")
+	writeTestFile(t, dstDir, "docs/deep/diff.txt", "destin")
+	writeTestFile(t, srcDir, "docs/only-src.txt", "src")
+	writeTestFile(t, dstDir, "only-dst.txt", "dst")
+
+	ctx, err := NewTaskContext(context.Background(), 9002)
+	if err != nil {
+		t.Fatalf("NewTaskContext failed: %v", err)
+	}
+	outStatus := make(chan *dto.SyncStatusDTO, 100)
+	go func() {
+		for range outStatus {
+		}
+	}()
+	profile := models.Profile{From: srcDir, To: dstDir, Parallel: 2}
+	report, err := Check(ctx, beConfig.Config{}, profile, false, outStatus)
+	close(outStatus)
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(report.Match, []string{"photos/same.jpg"}) {
+		t.Errorf("unexpected match: %v", report.Match)
+	}
+	if !reflect.DeepEqual(report.Differ, []string{"docs/deep/diff.txt"}) {
+		t.Errorf("unexpected differ: %v", report.Differ)
+	}
+	if !reflect.DeepEqual(report.MissingOnDst, []string{"docs/only-src.txt"}) {
+		t.Errorf("unexpected missing on dst: %v", report.MissingOnDst)
+	}
+	if !reflect.DeepEqual(report.MissingOnSrc, []string{"only-dst.txt"}) {
+		t.Errorf("unexpected missing on src: %v", report.MissingOnSrc)
+	}
+	if report.InSync {
+		t.Error("expected report to be out of sync")
+	}
+
+	// Tree rollup: root -> docs (out of sync), photos (in sync), only-dst.txt
+	root := report.Tree
+	if root.Differ != 1 || root.MissingOnDst != 1 || root.MissingOnSrc != 1 || root.Match != 1 {
+		t.Errorf("unexpected root counts: %+v", root)
+	}
+	if len(root.Children) != 3 {
+		t.Fatalf("expected 3 root children, got %d", len(root.Children))
+	}
+	docs, photos := root.Children[0], root.Children[1]
+	if docs.Path != "docs" || docs.InSync || docs.Differ != 1 || docs.MissingOnDst != 1 {
+		t.Errorf("unexpected docs node: %+v", docs)
+	}
+	if photos.Path != "photos" || !photos.InSync {
+		t.Errorf("unexpected photos node: %+v", photos)
+	}
+	if root.Children[2].Path != "only-dst.txt" || root.Children[2].Status != "missing_on_src" {
+		t.Errorf("unexpected file node: %+v", root.Children[2])
+	}
+}
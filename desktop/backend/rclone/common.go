@@ -7,12 +7,14 @@ import (
 	"fmt"
 	"os"
 	"runtime/pprof"
+	"slices"
 	"sync"
 
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/accounting"
 	"github.com/rclone/rclone/fs/config/configfile"
 	"github.com/rclone/rclone/fs/filter"
+	"github.com/rclone/rclone/fs/fspath"
 	fslog "github.com/rclone/rclone/fs/log"
 	"github.com/rclone/rclone/fs/rc"
 	"github.com/rclone/rclone/fs/rc/rcserver"
@@ -167,7 +169,7 @@ func NewTaskContext(parentCtx context.Context, taskId int) (context.Context, err
 	ctx, _ := fs.AddConfig(parentCtx)
 
 	// 2. Isolated stats group
-	ctx = accounting.WithStatsGroup(ctx, fmt.Sprintf("task-%d", taskId))
+	ctx = accounting.WithStatsGroup(ctx, taskStatsGroup(taskId))
 	stats := accounting.Stats(ctx)
 	stats.ResetCounters()
 	stats.ResetErrors()
@@ -183,6 +185,25 @@ func NewTaskContext(parentCtx context.Context, taskId int) (context.Context, err
 	return ctx, nil
 }
 
+// taskStatsGroup names the stats group of a task context
+func taskStatsGroup(taskId int) string {
+	return fmt.Sprintf("task-%d", taskId)
+}
+
+// TaskRemotes returns the names of the remotes in the given paths, without duplicates.
+// Local paths have no remote.
+func TaskRemotes(paths ...string) []string {
+	var remotes []string
+	for _, path := range paths {
+		parsed, err := fspath.Parse(path)
+		if err != nil || parsed.Name == "" || slices.Contains(remotes, parsed.Name) {
+			continue
+		}
+		remotes = append(remotes, parsed.Name)
+	}
+	return remotes
+}
+
 // SimpleContext creates an isolated rclone context for lightweight operations
 // (ListFiles, Mkdir, etc.) that don't need stats isolation.
 func SimpleContext(parentCtx context.Context) (context.Context, error) {
@@ -0,0 +1,133 @@
+package rclone
+
+import (
+	"context"
+	beConfig "desktop/backend/config"
+	"desktop/backend/dto"
+	"desktop/backend/models"
+	"desktop/backend/utils"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rclone/rclone/backend/crypt"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/operations"
+)
+
+// ErrCryptNoHashes is returned by CryptCheck when the remote under the crypt remote
+// stores no hashes, so contents can only be compared by downloading them
+var ErrCryptNoHashes = errors.New("the remote under the crypt remote does not support any hashes")
+
+// CryptCheck verifies an encrypted destination against its plaintext source without
+// downloading anything: each source file is encrypted with the destination's nonce and
+// its hash compared with the hash the underlying remote stores for the encrypted file.
+// profile.To must be a crypt remote whose underlying remote supports hashes.
+//
+// The result has the same shape as Check; differences are part of the report, not an error.
+func CryptCheck(ctx context.Context, config beConfig.Config, profile models.Profile, outStatus chan *dto.SyncStatusDTO) (*models.CheckReport, error) {
+	fsConfig := fs.GetConfig(ctx)
+	fsConfig.Checkers = profile.Parallel
+
+	srcFs, err := newTaskFs(ctx, profile.From)
+	if utils.HandleError(err, "Failed to initialize source filesystem", nil, nil) != nil {
+		return nil, err
+	}
+
+	dstFs, err := fs.NewFs(ctx, profile.To)
+	if utils.HandleError(err, "Failed to initialize destination filesystem", nil, nil) != nil {
+		return nil, err
+	}
+
+	fcrypt, ok := dstFs.(*crypt.Fs)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a crypt remote", profile.To)
+	}
+	hashType := fcrypt.UnWrap().Hashes().GetOne()
+	if hashType == hash.None {
+		return nil, fmt.Errorf("%s: %w", profile.To, ErrCryptNoHashes)
+	}
+
+	ctx = applyFiltersAndBandwidth(ctx, fsConfig, profile)
+
+	ctx, err = ApplyProfileOptions(ctx, profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply profile options: %w", err)
+	}
+
+	if err := fsConfig.Reload(ctx); err != nil {
+		return nil, err
+	}
+
+	collector := &checkCollector{}
+	opt := &operations.CheckOpt{
+		Fsrc:     srcFs,
+		Fdst:     fcrypt,
+		Combined: collector,
+		Check: func(ctx context.Context, dst, src fs.Object) (differ bool, noHash bool, err error) {
+			cryptDst, ok := dst.(*crypt.Object)
+			if !ok {
+				return true, false, fmt.Errorf("%v is not a crypt object", dst)
+			}
+			underlyingHash, err := cryptDst.UnWrap().Hash(ctx, hashType)
+			if err != nil {
+				return true, false, fmt.Errorf("error reading hash from underlying %v: %w", cryptDst.UnWrap(), err)
+			}
+			if underlyingHash == "" {
+				return false, true, nil
+			}
+			cryptHash, err := fcrypt.ComputeHash(ctx, cryptDst, src, hashType)
+			if err != nil {
+				return true, false, fmt.Errorf("error computing hash: %w", err)
+			}
+			if cryptHash == "" {
+				return false, true, nil
+			}
+			return cryptHash != underlyingHash, false, nil
+		},
+	}
+
+	err = utils.RunRcloneWithRetryAndStats(ctx, false, false, outStatus, func() error {
+		return utils.HandleError(operations.CheckFn(ctx, opt), "Cryptcheck failed", nil, nil)
+	})
+
+	report := collector.report()
+	report.HashType = hashType.String()
+
+	// "N differences found" only restates the report
+	if err != nil && ctx.Err() == nil && len(report.Errors) == 0 && strings.HasSuffix(err.Error(), "differences found") {
+		err = nil
+	}
+	return report, err
+}
+
+// DecodeCryptNames decrypts object names (or slash separated paths) as stored on the
+// remote underlying the crypt remote cryptRemote, using its keys. Names that can't be
+// decrypted are returned with an error instead of failing the whole call.
+func DecodeCryptNames(ctx context.Context, cryptRemote string, names []string) ([]models.CryptName, error) {
+	fsInfo, _, _, config, err := fs.ConfigFs(cryptRemote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load remote %q: %w", cryptRemote, err)
+	}
+	if fsInfo.Name != "crypt" {
+		return nil, fmt.Errorf("%s is not a crypt remote", cryptRemote)
+	}
+	cipher, err := crypt.NewCipher(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize cipher: %w", err)
+	}
+
+	decoded := make([]models.CryptName, 0, len(names))
+	for _, name := range names {
+		entry := models.CryptName{Encrypted: name}
+		plain, err := cipher.DecryptFileName(name)
+		if err != nil {
+			entry.Error = err.Error()
+		} else {
+			entry.Decrypted = plain
+		}
+		decoded = append(decoded, entry)
+	}
+	return decoded, nil
+}
@@ -0,0 +1,94 @@
+package rclone
+
+import (
+	"context"
+	beConfig "desktop/backend/config"
+	"desktop/backend/dto"
+	"desktop/backend/models"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/obscure"
+	fssync "github.com/rclone/rclone/fs/sync"
+)
+
+// TestCryptCheckAndDecode tests that an encrypted copy verifies against its plaintext
+// source and that its object names decode back to the plaintext names
+func TestCryptCheckAndDecode(t *testing.T) {
+	srcDir := t.TempDir()
+	encDir := t.TempDir()
+	writeTestFile(t, srcDir, "report.txt", "quarterly numbers")
+	writeTestFile(t, srcDir, "notes.txt", "meeting notes")
+	cryptRemote := ":crypt,remote='" + encDir + "',password='" + obscure.MustObscure("secret") + "':"
+
+	ctx, err := NewTaskContext(context.Background(), 9006)
+	if err != nil {
+		t.Fatalf("NewTaskContext failed: %v", err)
+	}
+	srcFs, err := fs.NewFs(ctx, srcDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cryptFs, err := fs.NewFs(ctx, cryptRemote)
+	if err != nil {
+		t.Fatalf("crypt backend not available: %v", err)
+	}
+	if err := fssync.CopyDir(ctx, cryptFs, srcFs, false); err != nil {
+		t.Fatalf("CopyDir failed: %v", err)
+	}
+
+	outStatus := make(chan *dto.SyncStatusDTO, 100)
+	go func() {
+		for range outStatus {
+		}
+	}()
+	defer close(outStatus)
+
+	profile := models.Profile{From: srcDir, To: cryptRemote, Parallel: 2}
+	report, err := CryptCheck(ctx, beConfig.Config{}, profile, outStatus)
+	if err != nil {
+		t.Fatalf("CryptCheck failed: %v", err)
+	}
+	if !report.InSync || len(report.Match) != 2 {
+		t.Errorf("expected encrypted copy in sync, got %+v", report)
+	}
+
+	writeTestFile(t, srcDir, "report.txt", "restated numbers")
+	report, err = CryptCheck(ctx, beConfig.Config{}, profile, outStatus)
+	if err != nil {
+		t.Fatalf("CryptCheck failed: %v", err)
+	}
+	if !reflect.DeepEqual(report.Differ, []string{"report.txt"}) {
+		t.Errorf("unexpected differ: %v", report.Differ)
+	}
+
+	entries, err := os.ReadDir(encDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	names = append(names, "not-encrypted")
+
+	decoded, err := DecodeCryptNames(ctx, cryptRemote, names)
+	if err != nil {
+		t.Fatalf("DecodeCryptNames failed: %v", err)
+	}
+	plain := map[string]bool{}
+	for _, d := range decoded[:len(decoded)-1] {
+		if d.Error != "" {
+			t.Errorf("failed to decode %s: %s", d.Encrypted, d.Error)
+		}
+		plain[d.Decrypted] = true
+	}
+	if !plain["report.txt"] || !plain["notes.txt"] {
+		t.Errorf("unexpected decoded names: %+v", decoded)
+	}
+	if last := decoded[len(decoded)-1]; last.Error == "" {
+		t.Errorf("expected an error for a plaintext name, got %+v", last)
+	}
+}
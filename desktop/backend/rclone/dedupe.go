@@ -0,0 +1,157 @@
+package rclone
+
+import (
+	"context"
+	"desktop/backend/dto"
+	"desktop/backend/models"
+	"desktop/backend/utils"
+	"fmt"
+	"sort"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/fs/walk"
+)
+
+// ParseDedupeMode converts a dedupe mode name into rclone's mode. Interactive mode
+// needs a terminal and is rejected.
+func ParseDedupeMode(mode string) (operations.DeduplicateMode, error) {
+	var m operations.DeduplicateMode
+	if err := m.Set(mode); err != nil {
+		return m, fmt.Errorf("unknown dedupe mode %q", mode)
+	}
+	if m == operations.DeduplicateInteractive {
+		return m, fmt.Errorf("interactive dedupe mode is not supported")
+	}
+	return m, nil
+}
+
+// Dedupe resolves files with duplicate names (or duplicate hashes with byHash) at the
+// given remote path according to mode. Duplicate directories are merged first, and
+// identical copies are removed before the mode is applied, as rclone dedupe does.
+// Use ListDuplicates for the "list" mode.
+func Dedupe(ctx context.Context, remotePath, mode string, byHash bool, outStatus chan *dto.SyncStatusDTO) error {
+	m, err := ParseDedupeMode(mode)
+	if err != nil {
+		return err
+	}
+
+	remoteFs, err := newTaskFs(ctx, remotePath)
+	if utils.HandleError(err, "Failed to initialize filesystem", nil, nil) != nil {
+		return err
+	}
+
+	return utils.RunRcloneWithRetryAndStats(ctx, true, false, outStatus, func() error {
+		return utils.HandleError(operations.Deduplicate(ctx, remoteFs, m, byHash), "Dedupe failed", nil, nil)
+	})
+}
+
+// ListDuplicates finds the files sharing a name (or a hash with byHash) at the given
+// remote path without changing anything. Unless grouping by hash, directories sharing
+// a name are reported too.
+func ListDuplicates(ctx context.Context, remotePath string, byHash bool, outStatus chan *dto.SyncStatusDTO) (*models.DedupeReport, error) {
+	remoteFs, err := newTaskFs(ctx, remotePath)
+	if utils.HandleError(err, "Failed to initialize filesystem", nil, nil) != nil {
+		return nil, err
+	}
+
+	ht := remoteFs.Hashes().GetOne()
+	if byHash && ht == hash.None {
+		return nil, fmt.Errorf("%v has no hashes", remoteFs)
+	}
+
+	report := &models.DedupeReport{
+		Remote:   remotePath,
+		ByHash:   byHash,
+		HashType: ht.String(),
+		Groups:   []models.DuplicateGroup{},
+	}
+
+	listType := walk.ListAll
+	if byHash {
+		listType = walk.ListObjects
+	}
+
+	err = utils.RunRcloneWithRetryAndStats(ctx, false, false, outStatus, func() error {
+		files := map[string][]fs.Object{}
+		dirs := map[string][]models.DuplicateEntry{}
+		err := walk.ListR(ctx, remoteFs, "", false, fs.GetConfig(ctx).MaxDepth, listType, func(entries fs.DirEntries) error {
+			for _, entry := range entries {
+				switch e := entry.(type) {
+				case fs.Directory:
+					dirs[e.Remote()] = append(dirs[e.Remote()], models.DuplicateEntry{
+						Path:    e.Remote(),
+						Id:      e.ID(),
+						Size:    e.Size(),
+						ModTime: e.ModTime(ctx),
+					})
+				case fs.Object:
+					key := e.Remote()
+					if byHash {
+						h, err := e.Hash(ctx, ht)
+						if err != nil || h == "" {
+							fs.Errorf(e, "Failed to hash: %v", err)
+							continue
+						}
+						key = h
+					}
+					files[key] = append(files[key], e)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return utils.HandleError(err, "Listing duplicates failed", nil, nil)
+		}
+
+		// Only duplicates are hashed when grouping by name
+		fileEntries := map[string][]models.DuplicateEntry{}
+		for key, objs := range files {
+			if len(objs) < 2 {
+				continue
+			}
+			for _, o := range objs {
+				fileEntries[key] = append(fileEntries[key], duplicateEntry(ctx, o, ht))
+			}
+		}
+
+		report.Groups = append(duplicateGroups(dirs, true), duplicateGroups(fileEntries, false)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// duplicateEntry describes a duplicate file
+func duplicateEntry(ctx context.Context, o fs.Object, ht hash.Type) models.DuplicateEntry {
+	dup := models.DuplicateEntry{
+		Path:    o.Remote(),
+		Size:    o.Size(),
+		ModTime: o.ModTime(ctx),
+	}
+	if ider, ok := o.(fs.IDer); ok {
+		dup.Id = ider.ID()
+	}
+	if ht != hash.None {
+		if h, err := o.Hash(ctx, ht); err == nil && h != "" {
+			dup.Hash = ht.String() + ":" + h
+		}
+	}
+	return dup
+}
+
+// duplicateGroups keeps the keys with more than one entry, sorted by key
+func duplicateGroups(entries map[string][]models.DuplicateEntry, isDir bool) []models.DuplicateGroup {
+	groups := []models.DuplicateGroup{}
+	for key, dups := range entries {
+		if len(dups) > 1 {
+			sort.Slice(dups, func(i, j int) bool { return dups[i].Path < dups[j].Path })
+			groups = append(groups, models.DuplicateGroup{Key: key, IsDir: isDir, Entries: dups})
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Key < groups[j].Key })
+	return groups
+}
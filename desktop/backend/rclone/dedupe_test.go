@@ -0,0 +1,71 @@
+package rclone
+
+import (
+	"context"
+	"desktop/backend/dto"
+	"desktop/backend/models"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestParseDedupeMode tests that every non-interactive rclone mode is accepted
+func TestParseDedupeMode(t *testing.T) {
+	for _, mode := range []string{models.DedupeSkip, models.DedupeFirst, models.DedupeNewest, models.DedupeOldest,
+		models.DedupeLargest, models.DedupeSmallest, models.DedupeRename, models.DedupeList} {
+		if _, err := ParseDedupeMode(mode); err != nil {
+			t.Errorf("mode %q rejected: %v", mode, err)
+		}
+	}
+	for _, mode := range []string{"interactive", "bogus"} {
+		if _, err := ParseDedupeMode(mode); err == nil {
+			t.Errorf("mode %q accepted", mode)
+		}
+	}
+}
+
+// TestListDuplicatesByHash tests that files with identical content are grouped and nothing is changed
+func TestListDuplicatesByHash(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, dir, "a.txt", "same content")
+	writeTestFile(t, dir, "sub/b.txt", "same content")
+	writeTestFile(t, dir, "c.txt", "unique")
+
+	ctx, err := NewTaskContext(context.Background(), 9004)
+	if err != nil {
+		t.Fatalf("NewTaskContext failed: %v", err)
+	}
+	outStatus := make(chan *dto.SyncStatusDTO, 100)
+	go func() {
+		for range outStatus {
+		}
+	}()
+	report, err := ListDuplicates(ctx, dir, true, outStatus)
+	close(outStatus)
+	if err != nil {
+		t.Fatalf("ListDuplicates failed: %v", err)
+	}
+
+	if len(report.Groups) != 1 {
+		t.Fatalf("expected 1 duplicate group, got %+v", report.Groups)
+	}
+	group := report.Groups[0]
+	if group.IsDir || len(group.Entries) != 2 {
+		t.Fatalf("unexpected group: %+v", group)
+	}
+	if group.Entries[0].Path != "a.txt" || group.Entries[1].Path != "sub/b.txt" {
+		t.Errorf("unexpected entries: %+v", group.Entries)
+	}
+	if group.Entries[0].Hash == "" || group.Entries[0].Hash != group.Entries[1].Hash {
+		t.Errorf("expected matching hashes, got %q and %q", group.Entries[0].Hash, group.Entries[1].Hash)
+	}
+
+	for _, name := range []string{"a.txt", "sub/b.txt", "c.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s was touched: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package rclone
+
+import (
+	"context"
+	"desktop/backend/models"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/operations"
+)
+
+// Journal outcomes recorded per file
+const (
+	JournalCopied  = "copied"
+	JournalUpdated = "updated"
+	JournalDeleted = "deleted"
+	JournalRenamed = "renamed"
+	JournalMoved   = "moved"
+	JournalFailed  = "failed"
+)
+
+// JournalRun is the kind of run a transfer journal records
+type JournalRun int
+
+// Kinds of journaled runs
+const (
+	// JournalSync runs delete destination files that are missing on the source
+	JournalSync JournalRun = iota
+	// JournalCopy runs copy files and never delete
+	JournalCopy
+	// JournalMove runs move files, so transfers are recorded as moves
+	JournalMove
+)
+
+type transferJournalKey struct{}
+
+// TransferJournal collects the per-file outcome of a single run. It is fed by
+// rclone's sync logger while the run is in progress and completed from the
+// task's accounting stats when the run finishes.
+type TransferJournal struct {
+	mu      sync.Mutex
+	run     JournalRun
+	records map[string]*models.TransferRecord
+	order   []string
+}
+
+// NewTransferJournal creates an empty transfer journal for the given kind of run
+func NewTransferJournal(run JournalRun) *TransferJournal {
+	return &TransferJournal{
+		run:     run,
+		records: make(map[string]*models.TransferRecord),
+	}
+}
+
+// WithTransferJournal attaches the journal to ctx. Sync runs get an rclone logger that
+// records every file decision, deletions included. Copy and move runs are recorded from
+// the accounting stats alone: with a logger installed rclone lists every directory that
+// only exists on the destination just to report it, which they have no use for. Runs
+// that replace the logger (bisync) are recorded from the stats too, so the task's stats
+// group keeps all its completed transfers until the journal is read.
+func WithTransferJournal(ctx context.Context, journal *TransferJournal) context.Context {
+	if _, ok := accounting.StatsGroupFromContext(ctx); ok {
+		accounting.Stats(ctx).SetMaxCompletedTransfers(-1)
+	}
+	ctx = context.WithValue(ctx, transferJournalKey{}, journal)
+	if journal.run != JournalSync {
+		return ctx
+	}
+	return operations.WithLogger(ctx, journal.log)
+}
+
+// TransferJournalFromContext returns the journal attached to ctx, or nil
+func TransferJournalFromContext(ctx context.Context) *TransferJournal {
+	if ctx == nil {
+		return nil
+	}
+	journal, _ := ctx.Value(transferJournalKey{}).(*TransferJournal)
+	return journal
+}
+
+// log is the rclone LoggerFn; it maps sync sigils to journal outcomes
+func (j *TransferJournal) log(ctx context.Context, sigil operations.Sigil, src, dst fs.DirEntry, err error) {
+	if err == fs.ErrorIsDir {
+		return
+	}
+
+	var outcome string
+	var entry fs.DirEntry
+	switch sigil {
+	case operations.MissingOnDst:
+		outcome, entry = j.transferOutcome(JournalCopied), src
+	case operations.Differ:
+		outcome, entry = j.transferOutcome(JournalUpdated), src
+	case operations.MissingOnSrc:
+		outcome, entry = JournalDeleted, dst
+	case operations.TransferError:
+		outcome, entry = JournalFailed, src
+		if entry == nil {
+			entry = dst
+		}
+	default:
+		return
+	}
+
+	obj, ok := entry.(fs.Object)
+	if !ok {
+		return
+	}
+
+	record := models.TransferRecord{
+		Path:      obj.Remote(),
+		Outcome:   outcome,
+		Size:      obj.Size(),
+		Timestamp: time.Now(),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	j.put(record, false)
+}
+
+// transferOutcome returns the outcome of a file transfer, which is a move in a move run
+func (j *TransferJournal) transferOutcome(outcome string) string {
+	if j.run == JournalMove {
+		return JournalMoved
+	}
+	return outcome
+}
+
+// put stores a record, keeping the first outcome unless overwrite is set.
+// A failure always replaces an earlier planned outcome for the same path.
+func (j *TransferJournal) put(record models.TransferRecord, overwrite bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	existing, ok := j.records[record.Path]
+	if !ok {
+		j.records[record.Path] = &record
+		j.order = append(j.order, record.Path)
+		return
+	}
+	if overwrite || record.Outcome == JournalFailed {
+		*existing = record
+	}
+}
+
+// Records returns the journal entries in the order they were first seen. Completed
+// transfers held by the task's accounting stats are merged in, which covers failures
+// reported after the logger ran and runs that replace the logger (bisync). The stats
+// group then goes back to pruning its completed transfers.
+func (j *TransferJournal) Records(statsCtx context.Context) []models.TransferRecord {
+	if statsCtx != nil {
+		stats := accounting.Stats(statsCtx)
+		for _, tr := range stats.Transferred() {
+			j.mergeSnapshot(tr)
+		}
+		if _, ok := accounting.StatsGroupFromContext(statsCtx); ok {
+			stats.SetMaxCompletedTransfers(accounting.MaxCompletedTransfers)
+			stats.RemoveDoneTransfers()
+		}
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	records := make([]models.TransferRecord, 0, len(j.order))
+	for _, path := range j.order {
+		records = append(records, *j.records[path])
+	}
+	return records
+}
+
+// mergeSnapshot folds a completed accounting transfer into the journal
+func (j *TransferJournal) mergeSnapshot(tr accounting.TransferSnapshot) {
+	record := models.TransferRecord{
+		Path:      tr.Name,
+		Size:      tr.Size,
+		Timestamp: tr.CompletedAt,
+	}
+
+	switch {
+	case tr.Error != nil:
+		record.Outcome = JournalFailed
+		record.Error = tr.Error.Error()
+		j.put(record, true)
+		return
+	case !tr.Checked:
+		record.Outcome = j.transferOutcome(JournalCopied)
+	case tr.What == "deleting":
+		record.Outcome = JournalDeleted
+	case tr.What == "moving":
+		// Server-side moves; in other runs these are renames of tracked files
+		record.Outcome = j.transferOutcome(JournalRenamed)
+		j.put(record, true)
+		return
+	default:
+		return
+	}
+	j.put(record, false)
+}
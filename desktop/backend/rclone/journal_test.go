@@ -0,0 +1,98 @@
+package rclone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/fstest/mockobject"
+)
+
+// TestTransferJournalOutcomes tests that sync logger sigils map to journal outcomes
+func TestTransferJournalOutcomes(t *testing.T) {
+	journal := NewTransferJournal(JournalSync)
+	ctx := WithTransferJournal(context.Background(), journal)
+
+	if TransferJournalFromContext(ctx) != journal {
+		t.Fatal("journal not attached to context")
+	}
+
+	logger, ok := operations.GetLogger(ctx)
+	if !ok {
+		t.Fatal("expected logger to be installed")
+	}
+
+	logger(ctx, operations.MissingOnDst, mockobject.New("new.txt"), nil, nil)
+	logger(ctx, operations.Differ, mockobject.New("changed.txt"), mockobject.New("changed.txt"), nil)
+	logger(ctx, operations.Match, mockobject.New("same.txt"), mockobject.New("same.txt"), nil)
+	logger(ctx, operations.MissingOnSrc, nil, mockobject.New("gone.txt"), nil)
+	logger(ctx, operations.MissingOnDst, mockobject.New("dir"), nil, fs.ErrorIsDir)
+	// A later failure replaces the planned outcome
+	logger(ctx, operations.TransferError, mockobject.New("new.txt"), nil, errors.New("boom"))
+
+	records := journal.Records(nil)
+	want := map[string]string{
+		"new.txt":     JournalFailed,
+		"changed.txt": JournalUpdated,
+		"gone.txt":    JournalDeleted,
+	}
+	if len(records) != len(want) {
+		t.Fatalf("expected %d records, got %d: %+v", len(want), len(records), records)
+	}
+	for _, rec := range records {
+		if want[rec.Path] != rec.Outcome {
+			t.Errorf("path %s: expected outcome %q, got %q", rec.Path, want[rec.Path], rec.Outcome)
+		}
+	}
+	if records[0].Path != "new.txt" || records[0].Error != "boom" {
+		t.Errorf("expected first record to be failed new.txt, got %+v", records[0])
+	}
+}
+
+// TestTransferJournalStats tests that runs without the logger, like bisync, keep every
+// completed transfer and that server-side moves of a move run are recorded as moves
+func TestTransferJournalStats(t *testing.T) {
+	ctx := accounting.WithStatsGroup(context.Background(), "journal-test")
+	journal := NewTransferJournal(JournalMove)
+	ctx = WithTransferJournal(ctx, journal)
+	stats := accounting.Stats(ctx)
+
+	total := accounting.MaxCompletedTransfers * 2
+	for i := 0; i < total; i++ {
+		tr := stats.NewTransferRemoteSize(fmt.Sprintf("file-%d.txt", i), 1, nil, nil)
+		tr.Done(ctx, nil)
+	}
+	tr := stats.NewCheckingTransfer(mockobject.New("moved.txt"), "moving")
+	tr.Done(ctx, nil)
+
+	records := journal.Records(ctx)
+	if len(records) != total+1 {
+		t.Fatalf("expected %d records, got %d", total+1, len(records))
+	}
+	for _, rec := range records {
+		if rec.Outcome != JournalMoved {
+			t.Errorf("path %s: expected outcome %q, got %q", rec.Path, JournalMoved, rec.Outcome)
+		}
+	}
+	if n := len(stats.Transferred()); n > accounting.MaxCompletedTransfers+fs.GetConfig(ctx).Transfers {
+		t.Errorf("expected the stats to be pruned after reading the journal, got %d transfers", n)
+	}
+}
+
+// TestTransferJournalCopyWithoutLogger tests that copy and move runs are not given a
+// logger, which would make rclone list destination-only directories
+func TestTransferJournalCopyWithoutLogger(t *testing.T) {
+	for _, run := range []JournalRun{JournalCopy, JournalMove} {
+		ctx := WithTransferJournal(context.Background(), NewTransferJournal(run))
+		if TransferJournalFromContext(ctx) == nil {
+			t.Fatalf("run %d: journal not attached to context", run)
+		}
+		if _, ok := operations.GetLogger(ctx); ok {
+			t.Errorf("run %d: expected no logger to be installed", run)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package rclone
+
+import (
+	"bytes"
+	"context"
+	"desktop/backend/dto"
+	"desktop/backend/models"
+	"desktop/backend/utils"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/filter"
+	"github.com/rclone/rclone/fs/fspath"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/operations"
+)
+
+// GenerateManifest hashes every file at remotePath and writes the sums to manifestPath
+// in the format of rclone hashsum ("<hash>  <path>" per line, sorted by path). With
+// download set, or when the remote can't provide the hash itself, files are downloaded
+// and hashed locally instead of trusting the provider's checksums. Returns the number
+// of files in the manifest.
+func GenerateManifest(ctx context.Context, remotePath, manifestPath, hashType string, download bool, outStatus chan *dto.SyncStatusDTO) (int, error) {
+	ht, err := parseManifestHash(hashType)
+	if err != nil {
+		return 0, err
+	}
+
+	remoteFs, err := newTaskFs(ctx, remotePath)
+	if utils.HandleError(err, "Failed to initialize filesystem", nil, nil) != nil {
+		return 0, err
+	}
+	manifestFs, manifestLeaf, err := newManifestFs(ctx, manifestPath)
+	if err != nil {
+		return 0, err
+	}
+	if !remoteFs.Hashes().Contains(ht) {
+		download = true
+	}
+	ctx = excludeManifest(ctx, remotePath, manifestPath)
+
+	var lines []string
+	err = utils.RunRcloneWithRetryAndStats(ctx, false, false, outStatus, func() error {
+		collector := &checkCollector{}
+		if err := operations.HashLister(ctx, ht, false, download, remoteFs, collector); err != nil {
+			return utils.HandleError(err, "Hashing failed", nil, nil)
+		}
+		lines = collector.lines
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Lines arrive in completion order; sort them so manifests of the same tree diff cleanly
+	sort.Slice(lines, func(i, j int) bool {
+		_, a, _ := strings.Cut(lines[i], "  ")
+		_, b, _ := strings.Cut(lines[j], "  ")
+		return a < b
+	})
+
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	if _, err := operations.Rcat(ctx, manifestFs, manifestLeaf, io.NopCloser(&buf), time.Now(), nil); err != nil {
+		return 0, fmt.Errorf("failed to write manifest %s: %w", manifestPath, err)
+	}
+	return len(lines), nil
+}
+
+// VerifyManifest re-hashes the files at remotePath and compares them with the manifest
+// at manifestPath, reporting changed, missing and extra files. Like Check, differences
+// are part of the report, not an error.
+func VerifyManifest(ctx context.Context, remotePath, manifestPath, hashType string, download bool, outStatus chan *dto.SyncStatusDTO) (*models.ManifestReport, error) {
+	ht, err := parseManifestHash(hashType)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteFs, err := newTaskFs(ctx, remotePath)
+	if utils.HandleError(err, "Failed to initialize filesystem", nil, nil) != nil {
+		return nil, err
+	}
+	manifestFs, manifestLeaf, err := newManifestFs(ctx, manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	if !remoteFs.Hashes().Contains(ht) {
+		download = true
+	}
+	ctx = excludeManifest(ctx, remotePath, manifestPath)
+
+	collector := &checkCollector{}
+	err = utils.RunRcloneWithRetryAndStats(ctx, false, false, outStatus, func() error {
+		opt := &operations.CheckOpt{Combined: collector}
+		return utils.HandleError(operations.CheckSum(ctx, remoteFs, manifestFs, manifestLeaf, ht, opt, download), "Verify failed", nil, nil)
+	})
+
+	// The sum list is the source and the remote the destination of the check
+	check := collector.report()
+	report := &models.ManifestReport{
+		Remote:   remotePath,
+		Manifest: manifestPath,
+		HashType: ht.String(),
+		Download: download,
+		Match:    check.Match,
+		Changed:  check.Differ,
+		Missing:  check.MissingOnDst,
+		Extra:    check.MissingOnSrc,
+		Errors:   check.Errors,
+	}
+	report.Intact = len(report.Changed)+len(report.Missing)+len(report.Extra)+len(report.Errors) == 0
+
+	if err != nil && len(collector.lines) == 0 {
+		return nil, err
+	}
+	// Changed, missing and extra files are counted as errors but only restate the report
+	if err != nil && ctx.Err() == nil && len(report.Errors) == 0 {
+		err = nil
+	}
+	return report, err
+}
+
+// parseManifestHash resolves a hash name such as "sha256" or "md5"
+func parseManifestHash(hashType string) (hash.Type, error) {
+	var ht hash.Type
+	if err := ht.Set(hashType); err != nil || ht == hash.None {
+		return hash.None, fmt.Errorf("unsupported manifest hash type %q", hashType)
+	}
+	return ht, nil
+}
+
+// newManifestFs opens the directory holding the manifest and returns it with the file name
+func newManifestFs(ctx context.Context, manifestPath string) (fs.Fs, string, error) {
+	parent, leaf, err := fspath.Split(manifestPath)
+	if err != nil || leaf == "" {
+		return nil, "", fmt.Errorf("invalid manifest path %q", manifestPath)
+	}
+	f, err := fs.NewFs(ctx, parent)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to initialize manifest filesystem: %w", err)
+	}
+	return f, leaf, nil
+}
+
+// excludeManifest filters the manifest out of the listing when it is stored inside the
+// tree it describes
+func excludeManifest(ctx context.Context, remotePath, manifestPath string) context.Context {
+	prefix := strings.TrimSuffix(remotePath, "/") + "/"
+	if strings.HasSuffix(remotePath, ":") {
+		prefix = remotePath
+	}
+	rel, ok := strings.CutPrefix(manifestPath, prefix)
+	if !ok {
+		return ctx
+	}
+
+	filterOpt := CopyFilterOpt(ctx)
+	filterOpt.ExcludeRule = append(filterOpt.ExcludeRule, "/{{"+regexp.QuoteMeta(rel)+"}}")
+	newFilter, err := filter.NewFilter(&filterOpt)
+	if err != nil {
+		fs.Errorf(nil, "Failed to exclude manifest %s: %v", manifestPath, err)
+		return ctx
+	}
+	return filter.ReplaceConfig(ctx, newFilter)
+}
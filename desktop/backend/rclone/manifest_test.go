@@ -0,0 +1,82 @@
+package rclone
+
+import (
+	"context"
+	"desktop/backend/dto"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestManifestGenerateAndVerify tests that a manifest round-trips and that verification
+// reports changed, missing and extra files
+func TestManifestGenerateAndVerify(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, dir, "a.txt", "alpha")
+	writeTestFile(t, dir, "docs/b.txt", "bravo")
+	writeTestFile(t, dir, "docs/c.txt", "charlie")
+	manifest := filepath.Join(dir, "archive.sha256")
+
+	ctx, err := NewTaskContext(context.Background(), 9005)
+	if err != nil {
+		t.Fatalf("NewTaskContext failed: %v", err)
+	}
+	outStatus := make(chan *dto.SyncStatusDTO, 100)
+	go func() {
+		for range outStatus {
+		}
+	}()
+	defer close(outStatus)
+
+	files, err := GenerateManifest(ctx, dir, manifest, "sha256", false, outStatus)
+	if err != nil {
+		t.Fatalf("GenerateManifest failed: %v", err)
+	}
+	if files != 3 {
+		t.Errorf("expected 3 files in manifest, got %d", files)
+	}
+	data, err := os.ReadFile(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], "  a.txt") || !strings.HasSuffix(lines[2], "  docs/c.txt") {
+		t.Fatalf("unexpected manifest:\n%s", data)
+	}
+
+	report, err := VerifyManifest(ctx, dir, manifest, "sha256", false, outStatus)
+	if err != nil {
+		t.Fatalf("VerifyManifest failed: %v", err)
+	}
+	if !report.Intact || len(report.Match) != 3 {
+		t.Errorf("expected intact tree, got %+v", report)
+	}
+
+	writeTestFile(t, dir, "a.txt", "tampered")
+	if err := os.Remove(filepath.Join(dir, "docs/b.txt")); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, dir, "new.txt", "delta")
+
+	report, err = VerifyManifest(ctx, dir, manifest, "sha256", true, outStatus)
+	if err != nil {
+		t.Fatalf("VerifyManifest failed: %v", err)
+	}
+	if report.Intact {
+		t.Error("expected tree not to be intact")
+	}
+	if !reflect.DeepEqual(report.Changed, []string{"a.txt"}) {
+		t.Errorf("unexpected changed: %v", report.Changed)
+	}
+	if !reflect.DeepEqual(report.Missing, []string{"docs/b.txt"}) {
+		t.Errorf("unexpected missing: %v", report.Missing)
+	}
+	if !reflect.DeepEqual(report.Extra, []string{"new.txt"}) {
+		t.Errorf("unexpected extra: %v", report.Extra)
+	}
+}
@@ -21,12 +21,12 @@ func Copy(ctx context.Context, config beConfig.Config, profile models.Profile, o
 	fsConfig.Transfers = profile.Parallel
 	fsConfig.Checkers = profile.Parallel
 
-	srcFs, err := fs.NewFs(ctx, profile.From)
+	srcFs, err := newTaskFs(ctx, profile.From)
 	if utils.HandleError(err, "Failed to initialize source filesystem", nil, nil) != nil {
 		return err
 	}
 
-	dstFs, err := fs.NewFs(ctx, profile.To)
+	dstFs, err := newTaskFs(ctx, profile.To)
 	if utils.HandleError(err, "Failed to initialize destination filesystem", nil, nil) != nil {
 		return err
 	}
@@ -53,12 +53,12 @@ func Move(ctx context.Context, config beConfig.Config, profile models.Profile, o
 	fsConfig.Transfers = profile.Parallel
 	fsConfig.Checkers = profile.Parallel
 
-	srcFs, err := fs.NewFs(ctx, profile.From)
+	srcFs, err := newTaskFs(ctx, profile.From)
 	if utils.HandleError(err, "Failed to initialize source filesystem", nil, nil) != nil {
 		return err
 	}
 
-	dstFs, err := fs.NewFs(ctx, profile.To)
+	dstFs, err := newTaskFs(ctx, profile.To)
 	if utils.HandleError(err, "Failed to initialize destination filesystem", nil, nil) != nil {
 		return err
 	}
@@ -79,40 +79,6 @@ func Move(ctx context.Context, config beConfig.Config, profile models.Profile, o
 	})
 }
 
-// Check compares source and destination and reports differences.
-func Check(ctx context.Context, config beConfig.Config, profile models.Profile, outStatus chan *dto.SyncStatusDTO) error {
-	fsConfig := fs.GetConfig(ctx)
-	fsConfig.Checkers = profile.Parallel
-
-	srcFs, err := fs.NewFs(ctx, profile.From)
-	if utils.HandleError(err, "Failed to initialize source filesystem", nil, nil) != nil {
-		return err
-	}
-
-	dstFs, err := fs.NewFs(ctx, profile.To)
-	if utils.HandleError(err, "Failed to initialize destination filesystem", nil, nil) != nil {
-		return err
-	}
-
-	ctx = applyFiltersAndBandwidth(ctx, fsConfig, profile)
-
-	ctx, err = ApplyProfileOptions(ctx, profile)
-	if err != nil {
-		return fmt.Errorf("failed to apply profile options: %w", err)
-	}
-
-	if err := fsConfig.Reload(ctx); err != nil {
-		return err
-	}
-
-	return utils.RunRcloneWithRetryAndStats(ctx, false, false, outStatus, func() error {
-		return utils.HandleError(operations.Check(ctx, &operations.CheckOpt{
-			Fsrc: srcFs,
-			Fdst: dstFs,
-		}), "Check failed", nil, nil)
-	})
-}
-
 // ListFiles lists files at the given remote path and returns FileEntry items.
 // Returns an empty slice (not an error) when the path is invalid or listing fails.
 func ListFiles(ctx context.Context, remotePath string, recursive bool) ([]models.FileEntry, error) {
@@ -246,8 +212,8 @@ func applyFiltersAndBandwidth(ctx context.Context, fsConfig *fs.ConfigInfo, prof
 	}
 
 	// Set bandwidth limit
-	if profile.Bandwidth > 0 {
-		_ = fsConfig.BwLimit.Set(fmt.Sprint(profile.Bandwidth) + "M")
+	if err := setBandwidthLimit(fsConfig, profile); err != nil {
+		fs.Errorf(nil, "Ignoring bandwidth limit: %v", err)
 	}
 
 	return ctx
@@ -0,0 +1,193 @@
+package rclone
+
+import (
+	"desktop/backend/models"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/cache"
+	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/fs/config/obscure"
+)
+
+// RedactedValue replaces sensitive option values read back from the config
+const RedactedValue = "XXX"
+
+// ProviderOptions returns the configuration options of a backend as registered with
+// rclone, leaving out the ones hidden from the configurator
+func ProviderOptions(remoteType string) ([]models.ProviderOption, error) {
+	info, err := fs.Find(remoteType)
+	if err != nil {
+		return nil, fmt.Errorf("unknown remote type %q: %w", remoteType, err)
+	}
+	options := make([]models.ProviderOption, 0, len(info.Options))
+	for i := range info.Options {
+		opt := &info.Options[i]
+		if opt.Hide&fs.OptionHideConfigurator != 0 {
+			continue
+		}
+		options = append(options, providerOption(opt))
+	}
+	return options, nil
+}
+
+// providerOption converts an rclone option into its schema description
+func providerOption(opt *fs.Option) models.ProviderOption {
+	def := opt.Copy()
+	def.Value = nil
+	option := models.ProviderOption{
+		Name:      opt.Name,
+		Help:      opt.Help,
+		Type:      def.Type(),
+		Default:   def.String(),
+		Provider:  opt.Provider,
+		Required:  opt.Required,
+		Advanced:  opt.Advanced,
+		Sensitive: opt.Sensitive || opt.IsPassword,
+		Password:  opt.IsPassword,
+		Exclusive: opt.Exclusive,
+	}
+	for _, example := range opt.Examples {
+		option.Examples = append(option.Examples, models.OptionExample{
+			Value:    example.Value,
+			Help:     example.Help,
+			Provider: example.Provider,
+		})
+	}
+	return option
+}
+
+// GetRemoteConfig returns the option schema of a configured remote with its current
+// values, redacting passwords and other sensitive values
+func GetRemoteConfig(name string) (*models.RemoteConfig, error) {
+	remoteType, info, err := findRemoteBackend(name)
+	if err != nil {
+		return nil, err
+	}
+	options, err := ProviderOptions(remoteType)
+	if err != nil {
+		return nil, err
+	}
+	return &models.RemoteConfig{
+		Name:    name,
+		Type:    remoteType,
+		Options: options,
+		Values:  redactedValues(name, info),
+	}, nil
+}
+
+// RemoteConfigValues returns the values set for a configured remote, with passwords and
+// other sensitive values redacted
+func RemoteConfigValues(name string) (map[string]string, error) {
+	_, info, err := findRemoteBackend(name)
+	if err != nil {
+		return nil, err
+	}
+	return redactedValues(name, info), nil
+}
+
+// UpdateRemoteConfig sets options of an existing remote in place, keeping every option
+// not mentioned or listed in update.Keep. Redacted options sent back as RedactedValue are
+// kept too, so values read with GetRemoteConfig can be saved unchanged. An empty value
+// resets the option to its default. Values are checked against the option types and passwords are obscured before
+// being stored, unless listed in update.Obscured. Unlike rclone's config update, the
+// backend's config flow is not re-run, so OAuth remotes keep their token.
+func UpdateRemoteConfig(name string, update models.RemoteConfigUpdate) error {
+	_, info, err := findRemoteBackend(name)
+	if err != nil {
+		return err
+	}
+
+	updates := map[string]string{}
+	for key, value := range update.Values {
+		if slices.Contains(update.Keep, key) || value == RedactedValue && isRedacted(info, key) {
+			continue
+		}
+		opt := info.Options.Get(key)
+		if opt == nil {
+			return fmt.Errorf("unknown option %q for %s remote", key, info.Name)
+		}
+		if value != "" {
+			if err := validateOptionValue(opt, value); err != nil {
+				return err
+			}
+			if opt.IsPassword {
+				if slices.Contains(update.Obscured, key) {
+					if _, err := obscure.Reveal(value); err != nil {
+						return fmt.Errorf("%s is not an obscured password: %w", key, err)
+					}
+				} else if value, err = obscure.Obscure(value); err != nil {
+					return fmt.Errorf("failed to obscure %s: %w", key, err)
+				}
+			}
+		}
+		updates[key] = value
+	}
+
+	for key, value := range updates {
+		if value == "" {
+			config.FileDeleteKey(name, key)
+		} else {
+			config.FileSetValue(name, key, value)
+		}
+	}
+	config.SaveConfig()
+	cache.ClearConfig(name)
+	return nil
+}
+
+// findRemoteBackend returns the type and backend of a configured remote
+func findRemoteBackend(name string) (string, *fs.RegInfo, error) {
+	if !config.LoadedData().HasSection(name) {
+		return "", nil, fmt.Errorf("remote '%s' not found", name)
+	}
+	remoteType, _ := config.FileGetValue(name, "type")
+	info, err := fs.Find(remoteType)
+	if err != nil {
+		return "", nil, fmt.Errorf("unknown type %q of remote '%s': %w", remoteType, name, err)
+	}
+	return remoteType, info, nil
+}
+
+// redactedValues reads the options set for a remote, replacing sensitive values
+func redactedValues(name string, info *fs.RegInfo) map[string]string {
+	values := map[string]string{}
+	for _, key := range config.LoadedData().GetKeyList(name) {
+		if key == "type" {
+			continue
+		}
+		value, _ := config.FileGetValue(name, key)
+		if value != "" && isRedacted(info, key) {
+			value = RedactedValue
+		}
+		values[key] = value
+	}
+	return values
+}
+
+// isRedacted reports whether the value of key is hidden when read back. Keys the backend
+// doesn't declare, like tokens some backends store, are hidden as they may be secret.
+func isRedacted(info *fs.RegInfo, key string) bool {
+	opt := info.Options.Get(key)
+	return opt == nil || opt.Sensitive || opt.IsPassword
+}
+
+// validateOptionValue checks that a value parses as the option's type and, for
+// exclusive options, is one of the examples
+func validateOptionValue(opt *fs.Option, value string) error {
+	if err := opt.Copy().Set(value); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", opt.Name, err)
+	}
+	if opt.Exclusive && len(opt.Examples) > 0 {
+		allowed := make([]string, 0, len(opt.Examples))
+		for _, example := range opt.Examples {
+			allowed = append(allowed, example.Value)
+		}
+		if !slices.Contains(allowed, value) {
+			return fmt.Errorf("invalid value for %s: must be one of %s", opt.Name, strings.Join(allowed, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package rclone
+
+import (
+	"desktop/backend/models"
+	"strings"
+	"testing"
+
+	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/fs/config/obscure"
+)
+
+// TestProviderOptions tests that backend options are described from rclone's registry
+func TestProviderOptions(t *testing.T) {
+	options, err := ProviderOptions("sftp")
+	if err != nil {
+		t.Fatalf("ProviderOptions failed: %v", err)
+	}
+	byName := map[string]int{}
+	for i, opt := range options {
+		byName[opt.Name] = i
+	}
+
+	host, ok := byName["host"]
+	if !ok || !options[host].Required || options[host].Advanced {
+		t.Errorf("expected required basic host option, got %+v", options[host])
+	}
+	pass, ok := byName["pass"]
+	if !ok || !options[pass].Password || !options[pass].Sensitive {
+		t.Errorf("expected password pass option, got %+v", options[pass])
+	}
+	if port := options[byName["port"]]; port.Type != "int" || port.Default != "22" {
+		t.Errorf("expected int port defaulting to 22, got %+v", port)
+	}
+
+	s3Options, err := ProviderOptions("s3")
+	if err != nil {
+		t.Fatalf("ProviderOptions failed: %v", err)
+	}
+	for _, opt := range s3Options {
+		if opt.Name == "provider" && len(opt.Examples) == 0 {
+			t.Error("expected s3 provider option to list examples")
+		}
+	}
+
+	if _, err := ProviderOptions("invalid"); err == nil {
+		t.Error("expected error for unknown type")
+	}
+}
+
+// TestUpdateRemoteConfig tests reading back and editing a remote's options in place
+func TestUpdateRemoteConfig(t *testing.T) {
+	const name = "options-test"
+	data := config.LoadedData()
+	data.SetValue(name, "type", "sftp")
+	data.SetValue(name, "host", "nas.local")
+	data.SetValue(name, "pass", obscure.MustObscure("secret"))
+	data.SetValue(name, "shell_type", "unix")
+	data.SetValue(name, "undeclared_key", "hidden")
+	t.Cleanup(func() { config.DeleteRemote(name) })
+
+	remote, err := GetRemoteConfig(name)
+	if err != nil {
+		t.Fatalf("GetRemoteConfig failed: %v", err)
+	}
+	if remote.Type != "sftp" || len(remote.Options) == 0 {
+		t.Errorf("unexpected remote config %+v", remote)
+	}
+	// rclone marks the sftp host as sensitive as well as the password; keys the backend
+	// doesn't declare are redacted too
+	if remote.Values["shell_type"] != "unix" || remote.Values["pass"] != RedactedValue ||
+		remote.Values["host"] != RedactedValue || remote.Values["undeclared_key"] != RedactedValue {
+		t.Errorf("expected shell_type shown and host, pass and undeclared_key redacted, got %v", remote.Values)
+	}
+
+	// Saving the values read back unchanged keeps the redacted ones
+	if err := UpdateRemoteConfig(name, models.RemoteConfigUpdate{Values: remote.Values}); err != nil {
+		t.Fatalf("UpdateRemoteConfig of unchanged values failed: %v", err)
+	}
+	for key, want := range map[string]string{"host": "nas.local", "shell_type": "unix", "undeclared_key": "hidden"} {
+		if got, _ := config.FileGetValue(name, key); got != want {
+			t.Errorf("expected %s to stay %q, got %q", key, want, got)
+		}
+	}
+	if pass, _ := config.FileGetValue(name, "pass"); obscure.MustReveal(pass) != "secret" {
+		t.Errorf("expected password to stay secret, got %q", obscure.MustReveal(pass))
+	}
+
+	// Kept secrets are left alone, empty values reset to the default
+	err = UpdateRemoteConfig(name, models.RemoteConfigUpdate{
+		Values: map[string]string{
+			"pass":       RedactedValue,
+			"port":       "2222",
+			"shell_type": "",
+		},
+		Keep: []string{"pass"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateRemoteConfig failed: %v", err)
+	}
+	if pass, _ := config.FileGetValue(name, "pass"); obscure.MustReveal(pass) != "secret" {
+		t.Errorf("expected password to be kept, got %q", pass)
+	}
+	if port, _ := config.FileGetValue(name, "port"); port != "2222" {
+		t.Errorf("expected port 2222, got %q", port)
+	}
+	if _, ok := config.FileGetValue(name, "shell_type"); ok {
+		t.Error("expected shell_type to be reset")
+	}
+	if host, _ := config.FileGetValue(name, "host"); host != "nas.local" {
+		t.Errorf("expected host to be kept, got %q", host)
+	}
+
+	// New passwords are stored obscured, even ones that happen to look obscured
+	for _, password := range []string{"rotated", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA", obscure.MustObscure("secret")} {
+		if err := UpdateRemoteConfig(name, models.RemoteConfigUpdate{Values: map[string]string{"pass": password}}); err != nil {
+			t.Fatalf("UpdateRemoteConfig failed: %v", err)
+		}
+		if pass, _ := config.FileGetValue(name, "pass"); pass == password || obscure.MustReveal(pass) != password {
+			t.Errorf("expected obscured password %q, got %q", password, pass)
+		}
+	}
+
+	// Values flagged as obscured are stored as they are
+	obscured := obscure.MustObscure("copied")
+	err = UpdateRemoteConfig(name, models.RemoteConfigUpdate{
+		Values:   map[string]string{"pass": obscured},
+		Obscured: []string{"pass"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateRemoteConfig failed: %v", err)
+	}
+	if pass, _ := config.FileGetValue(name, "pass"); pass != obscured {
+		t.Errorf("expected obscured password to be stored as is, got %q", pass)
+	}
+	err = UpdateRemoteConfig(name, models.RemoteConfigUpdate{
+		Values:   map[string]string{"pass": "plain"},
+		Obscured: []string{"pass"},
+	})
+	if err == nil {
+		t.Error("expected error for a plaintext password flagged as obscured")
+	}
+
+	if err := UpdateRemoteConfig(name, models.RemoteConfigUpdate{Values: map[string]string{"port": "not-a-number"}}); err == nil {
+		t.Error("expected error for invalid port")
+	}
+	if err := UpdateRemoteConfig(name, models.RemoteConfigUpdate{Values: map[string]string{"no_such_option": "x"}}); err == nil || !strings.Contains(err.Error(), "unknown option") {
+		t.Errorf("expected unknown option error, got %v", err)
+	}
+	if err := UpdateRemoteConfig("missing-remote", models.RemoteConfigUpdate{Values: map[string]string{"host": "x"}}); err == nil {
+		t.Error("expected error for missing remote")
+	}
+}
@@ -0,0 +1,240 @@
+package rclone
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/rclone/rclone/fs"
+)
+
+// PauseGate pauses and resumes a running task. rclone can't suspend a stream, so a
+// paused task is held at the remotes it opened: before starting any further upload,
+// server-side copy or move, and listing (which is how checks and deletes are found).
+// Transfers already in flight finish their current file, then the task uses no
+// bandwidth until resumed, keeping its context, accounting and listings.
+type PauseGate struct {
+	mu     sync.Mutex
+	resume chan struct{} // closed on resume; nil while running
+}
+
+// NewPauseGate returns a gate in the running state
+func NewPauseGate() *PauseGate {
+	return &PauseGate{}
+}
+
+// Pause holds the task before it starts further work. Returns false if already paused.
+func (g *PauseGate) Pause() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.resume != nil {
+		return false
+	}
+	g.resume = make(chan struct{})
+	return true
+}
+
+// Resume releases a paused task. Returns false if it was not paused.
+func (g *PauseGate) Resume() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.resume == nil {
+		return false
+	}
+	close(g.resume)
+	g.resume = nil
+	return true
+}
+
+// Paused reports whether the task is paused
+func (g *PauseGate) Paused() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.resume != nil
+}
+
+// Wait blocks while the gate is paused, returning the context's error if it ends first
+func (g *PauseGate) Wait(ctx context.Context) error {
+	for {
+		g.mu.Lock()
+		resume := g.resume
+		g.mu.Unlock()
+		if resume == nil {
+			return nil
+		}
+		select {
+		case <-resume:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+type pauseGateKey struct{}
+
+// WithPause returns a context carrying the gate. Remotes a task opens under it are
+// held while the gate is paused.
+func WithPause(ctx context.Context, gate *PauseGate) context.Context {
+	return context.WithValue(ctx, pauseGateKey{}, gate)
+}
+
+// newTaskFs opens a remote for a task, holding it while the task is paused
+func newTaskFs(ctx context.Context, remote string) (fs.Fs, error) {
+	f, err := fs.NewFs(ctx, remote)
+	if err != nil {
+		return f, err
+	}
+	if gate, ok := ctx.Value(pauseGateKey{}).(*PauseGate); ok {
+		f = newPausableFs(f, gate)
+	}
+	return f, nil
+}
+
+// pausableFs wraps a remote so that operations starting new work wait for the gate.
+// It keeps the name and root of the wrapped remote, so bisync listings and rclone's
+// same-remote checks are unchanged.
+type pausableFs struct {
+	fs.Fs
+	gate     *PauseGate
+	features *fs.Features
+}
+
+func newPausableFs(f fs.Fs, gate *PauseGate) *pausableFs {
+	p := &pausableFs{Fs: f, gate: gate}
+	hold := gate.Wait
+
+	// Optional features are taken over from the wrapped remote, held where they start work
+	ft := *f.Features()
+	if purge := ft.Purge; purge != nil {
+		ft.Purge = func(ctx context.Context, dir string) error {
+			if err := hold(ctx); err != nil {
+				return err
+			}
+			return purge(ctx, dir)
+		}
+	}
+	if copyFn := ft.Copy; copyFn != nil {
+		ft.Copy = func(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+			if err := hold(ctx); err != nil {
+				return nil, err
+			}
+			return copyFn(ctx, src, remote)
+		}
+	}
+	if move := ft.Move; move != nil {
+		ft.Move = func(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+			if err := hold(ctx); err != nil {
+				return nil, err
+			}
+			return move(ctx, src, remote)
+		}
+	}
+	if dirMove := ft.DirMove; dirMove != nil {
+		ft.DirMove = func(ctx context.Context, src fs.Fs, srcRemote, dstRemote string) error {
+			if err := hold(ctx); err != nil {
+				return err
+			}
+			// Backends check the type of the source remote
+			if ps, ok := src.(*pausableFs); ok {
+				src = ps.Fs
+			}
+			return dirMove(ctx, src, srcRemote, dstRemote)
+		}
+	}
+	if putUnchecked := ft.PutUnchecked; putUnchecked != nil {
+		ft.PutUnchecked = func(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+			if err := hold(ctx); err != nil {
+				return nil, err
+			}
+			return putUnchecked(ctx, in, src, options...)
+		}
+	}
+	if putStream := ft.PutStream; putStream != nil {
+		ft.PutStream = func(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+			if err := hold(ctx); err != nil {
+				return nil, err
+			}
+			return putStream(ctx, in, src, options...)
+		}
+	}
+	if openWriterAt := ft.OpenWriterAt; openWriterAt != nil {
+		ft.OpenWriterAt = func(ctx context.Context, remote string, size int64) (fs.WriterAtCloser, error) {
+			if err := hold(ctx); err != nil {
+				return nil, err
+			}
+			return openWriterAt(ctx, remote, size)
+		}
+	}
+	if openChunkWriter := ft.OpenChunkWriter; openChunkWriter != nil {
+		ft.OpenChunkWriter = func(ctx context.Context, remote string, src fs.ObjectInfo, options ...fs.OpenOption) (fs.ChunkWriterInfo, fs.ChunkWriter, error) {
+			if err := hold(ctx); err != nil {
+				return fs.ChunkWriterInfo{}, nil, err
+			}
+			return openChunkWriter(ctx, remote, src, options...)
+		}
+	}
+	if listR := ft.ListR; listR != nil {
+		ft.ListR = func(ctx context.Context, dir string, callback fs.ListRCallback) error {
+			if err := hold(ctx); err != nil {
+				return err
+			}
+			return listR(ctx, dir, callback)
+		}
+	}
+	if listP := ft.ListP; listP != nil {
+		ft.ListP = func(ctx context.Context, dir string, callback fs.ListRCallback) error {
+			if err := hold(ctx); err != nil {
+				return err
+			}
+			return listP(ctx, dir, callback)
+		}
+	}
+	ft.UnWrap = func() fs.Fs { return f }
+	p.features = &ft
+	return p
+}
+
+// Features returns the wrapped remote's features, held where they start work
+func (p *pausableFs) Features() *fs.Features {
+	return p.features
+}
+
+// List waits for the gate, then lists the directory
+func (p *pausableFs) List(ctx context.Context, dir string) (fs.DirEntries, error) {
+	if err := p.gate.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return p.Fs.List(ctx, dir)
+}
+
+// NewObject waits for the gate, then finds the object
+func (p *pausableFs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	if err := p.gate.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return p.Fs.NewObject(ctx, remote)
+}
+
+// Put waits for the gate, then uploads the object
+func (p *pausableFs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	if err := p.gate.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return p.Fs.Put(ctx, in, src, options...)
+}
+
+// Mkdir waits for the gate, then makes the directory
+func (p *pausableFs) Mkdir(ctx context.Context, dir string) error {
+	if err := p.gate.Wait(ctx); err != nil {
+		return err
+	}
+	return p.Fs.Mkdir(ctx, dir)
+}
+
+// Rmdir waits for the gate, then removes the directory
+func (p *pausableFs) Rmdir(ctx context.Context, dir string) error {
+	if err := p.gate.Wait(ctx); err != nil {
+		return err
+	}
+	return p.Fs.Rmdir(ctx, dir)
+}
@@ -0,0 +1,172 @@
+package rclone
+
+import (
+	"context"
+	beConfig "desktop/backend/config"
+	"desktop/backend/dto"
+	"desktop/backend/models"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/cmd/bisync"
+	"github.com/rclone/rclone/fs/accounting"
+)
+
+// TestPauseGate tests that a paused copy transfers nothing until resumed
+func TestPauseGate(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+	writeTestFile(t, srcDir, "a.txt", "alpha")
+	writeTestFile(t, srcDir, "b.txt", "bravo")
+
+	ctx, err := NewTaskContext(context.Background(), 9021)
+	if err != nil {
+		t.Fatalf("NewTaskContext failed: %v", err)
+	}
+	gate := NewPauseGate()
+	if !gate.Pause() || gate.Pause() {
+		t.Fatal("expected only the first Pause to succeed")
+	}
+	ctx = WithPause(ctx, gate)
+
+	outStatus := make(chan *dto.SyncStatusDTO, 100)
+	go func() {
+		for range outStatus {
+		}
+	}()
+	done := make(chan error, 1)
+	go func() {
+		done <- Copy(ctx, beConfig.Config{}, models.Profile{From: srcDir, To: dstDir}, outStatus)
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("copy finished while paused: %v", err)
+	case <-time.After(500 * time.Millisecond):
+	}
+	if n := accounting.Stats(ctx).GetTransfers(); n != 0 {
+		t.Errorf("expected no transfers while paused, got %d", n)
+	}
+	if _, err := os.Stat(filepath.Join(dstDir, "a.txt")); err == nil {
+		t.Error("expected no file copied while paused")
+	}
+
+	if !gate.Resume() || gate.Resume() {
+		t.Fatal("expected only the first Resume to succeed")
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Copy failed: %v", err)
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatal("copy did not finish after resume")
+	}
+	close(outStatus)
+
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if _, err := os.Stat(filepath.Join(dstDir, name)); err != nil {
+			t.Errorf("expected %s copied after resume: %v", name, err)
+		}
+	}
+}
+
+// TestPauseBisync tests that pausing a bisync while it is transferring stops further
+// transfers until resumed, and that the run then completes
+func TestPauseBisync(t *testing.T) {
+	oldWorkdir := bisync.DefaultWorkdir
+	bisync.DefaultWorkdir = t.TempDir()
+	defer func() { bisync.DefaultWorkdir = oldWorkdir }()
+
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+	writeTestFile(t, srcDir, "seed.txt", "seed")
+	writeTestFile(t, dstDir, "seed.txt", "seed")
+
+	// Slow enough to pause mid-run: 4 MiB at 1 MB/s, one file at a time
+	profile := models.Profile{From: srcDir, To: dstDir, Parallel: 1, Bandwidth: 1}
+	runTestBiSync(t, profile, 9031)
+	content := strings.Repeat("x", 512*1024)
+	for i := range 8 {
+		writeTestFile(t, srcDir, fmt.Sprintf("file%d.bin", i), content)
+	}
+
+	ctx, err := NewTaskContext(context.Background(), 9032)
+	if err != nil {
+		t.Fatalf("NewTaskContext failed: %v", err)
+	}
+	gate := NewPauseGate()
+	ctx = WithPause(ctx, gate)
+	stats := accounting.Stats(ctx)
+
+	outStatus := make(chan *dto.SyncStatusDTO, 100)
+	go func() {
+		for range outStatus {
+		}
+	}()
+	done := make(chan error, 1)
+	go func() {
+		done <- BiSync(ctx, beConfig.Config{}, profile, false, outStatus)
+	}()
+
+	deadline := time.Now().Add(30 * time.Second)
+	for stats.GetBytes() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("bisync did not start transferring")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	gate.Pause()
+
+	// The file in flight finishes, then nothing more is transferred
+	time.Sleep(time.Second)
+	bytes := stats.GetBytes()
+	copied, _ := os.ReadDir(dstDir)
+	time.Sleep(1500 * time.Millisecond)
+	if got := stats.GetBytes(); got != bytes {
+		t.Errorf("expected no bytes transferred while paused, got %d more", got-bytes)
+	}
+	if now, _ := os.ReadDir(dstDir); len(now) != len(copied) {
+		t.Errorf("expected no files copied while paused, went from %d to %d", len(copied), len(now))
+	}
+	if bytes >= int64(8*len(content)) {
+		t.Fatalf("bisync finished before it was paused")
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("bisync finished while paused: %v", err)
+	default:
+	}
+
+	gate.Resume()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("BiSync failed: %v", err)
+		}
+	case <-time.After(60 * time.Second):
+		t.Fatal("bisync did not finish after resume")
+	}
+	close(outStatus)
+
+	for i := range 8 {
+		if _, err := os.Stat(filepath.Join(dstDir, fmt.Sprintf("file%d.bin", i))); err != nil {
+			t.Errorf("expected file%d.bin copied after resume: %v", i, err)
+		}
+	}
+}
+
+// TestPauseGateCancel tests that cancelling a paused task releases it
+func TestPauseGateCancel(t *testing.T) {
+	gate := NewPauseGate()
+	gate.Pause()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := gate.Wait(ctx); err == nil {
+		t.Error("expected the context error from a cancelled wait")
+	}
+}
@@ -0,0 +1,309 @@
+package rclone
+
+import (
+	"context"
+	beConfig "desktop/backend/config"
+	"desktop/backend/dto"
+	"desktop/backend/models"
+	"desktop/backend/utils"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/filter"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/operations"
+	fssync "github.com/rclone/rclone/fs/sync"
+)
+
+// PlanSync computes the changes a one-way "push", "pull" or "copy" of the profile would
+// make, without modifying either side. Renames are detected by pairing deletes and copies
+// of equal size whose hashes match, when source and destination share a hash type.
+func PlanSync(ctx context.Context, action string, profile models.Profile) ([]models.PlanChange, error) {
+	// Private config copy so the dry-run flag never leaks into the caller's context, and a
+	// separate stats group so planning doesn't count towards the caller's transfer stats
+	ctx, fsConfig := fs.AddConfig(ctx)
+	ctx = accounting.WithStatsGroup(ctx, "plan")
+	if profile.Parallel > 0 {
+		fsConfig.Transfers = profile.Parallel
+		fsConfig.Checkers = profile.Parallel
+	}
+
+	srcFs, dstFs, err := planFs(ctx, action, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = applyFiltersAndBandwidth(ctx, fsConfig, profile)
+
+	ctx, err = ApplyProfileOptions(ctx, profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply profile options: %w", err)
+	}
+
+	fsConfig.DryRun = true
+	if err := fsConfig.Reload(ctx); err != nil {
+		return nil, err
+	}
+
+	collector := &planCollector{}
+	ctx = operations.WithLogger(ctx, collector.log)
+
+	if action == "copy" {
+		err = fssync.CopyDir(ctx, dstFs, srcFs, false)
+	} else {
+		err = fssync.Sync(ctx, dstFs, srcFs, false)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute plan: %w", err)
+	}
+
+	return collector.changes(ctx, srcFs.Hashes().Overlap(dstFs.Hashes()).GetOne()), nil
+}
+
+// ApplyPlan executes exactly the given plan changes: renames are done server-side on
+// the destination, copies and updates are transferred through a files-from filter
+// and deletes remove only the listed destination files.
+func ApplyPlan(ctx context.Context, config beConfig.Config, action string, profile models.Profile, changes []models.PlanChange, outStatus chan *dto.SyncStatusDTO) error {
+	fsConfig := fs.GetConfig(ctx)
+	if profile.Parallel > 0 {
+		fsConfig.Transfers = profile.Parallel
+		fsConfig.Checkers = profile.Parallel
+	}
+
+	srcFs, dstFs, err := planFs(ctx, action, profile)
+	if err != nil {
+		return err
+	}
+
+	ctx = applyFiltersAndBandwidth(ctx, fsConfig, profile)
+
+	ctx, err = ApplyProfileOptions(ctx, profile)
+	if err != nil {
+		return fmt.Errorf("failed to apply profile options: %w", err)
+	}
+
+	// The plan was approved as a preview; applying it is always for real
+	fsConfig.DryRun = false
+	if err := fsConfig.Reload(ctx); err != nil {
+		return err
+	}
+
+	// Restrict transfers to the planned files only
+	filterOpt := newDefaultFilterOpts()
+	filesFrom, err := filter.NewFilter(&filterOpt)
+	if err != nil {
+		return fmt.Errorf("failed to create files-from filter: %w", err)
+	}
+	var renames, deletes []models.PlanChange
+	transfers := 0
+	for _, c := range changes {
+		switch c.Kind {
+		case models.PlanCopy, models.PlanUpdate:
+			if err := filesFrom.AddFile(c.Path); err != nil {
+				return fmt.Errorf("failed to add %q to files-from: %w", c.Path, err)
+			}
+			transfers++
+		case models.PlanRename:
+			renames = append(renames, c)
+		case models.PlanDelete:
+			deletes = append(deletes, c)
+		}
+	}
+	copyCtx := filter.ReplaceConfig(ctx, filesFrom)
+
+	return utils.RunRcloneWithRetryAndStats(ctx, false, false, outStatus, func() error {
+		for _, c := range renames {
+			if err := operations.MoveFile(ctx, dstFs, dstFs, c.Path, c.From); err != nil {
+				return fmt.Errorf("failed to rename %q to %q: %w", c.From, c.Path, err)
+			}
+		}
+
+		if transfers > 0 {
+			if err := utils.HandleError(fssync.CopyDir(copyCtx, dstFs, srcFs, false), "Apply failed", nil, nil); err != nil {
+				return err
+			}
+		}
+
+		for _, c := range deletes {
+			obj, err := dstFs.NewObject(ctx, c.Path)
+			if errors.Is(err, fs.ErrorObjectNotFound) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to find %q for deletion: %w", c.Path, err)
+			}
+			if err := operations.DeleteFile(ctx, obj); err != nil {
+				return fmt.Errorf("failed to delete %q: %w", c.Path, err)
+			}
+		}
+		return nil
+	})
+}
+
+// DiffPlanChanges compares an approved plan with a freshly computed one and describes
+// every change that was added, removed or altered since the plan was approved.
+func DiffPlanChanges(approved, current []models.PlanChange) []string {
+	key := func(c models.PlanChange) string {
+		return string(c.Kind) + "\x00" + c.From + "\x00" + c.Path
+	}
+
+	currentByKey := make(map[string]models.PlanChange, len(current))
+	for _, c := range current {
+		currentByKey[key(c)] = c
+	}
+
+	var drift []string
+	for _, a := range approved {
+		c, ok := currentByKey[key(a)]
+		if !ok {
+			drift = append(drift, fmt.Sprintf("%s %s no longer planned", a.Kind, a.Path))
+			continue
+		}
+		delete(currentByKey, key(a))
+		if c.Size != a.Size || !c.ModTime.Equal(a.ModTime) {
+			drift = append(drift, fmt.Sprintf("%s %s changed since planning", a.Kind, a.Path))
+		}
+	}
+	for _, c := range current {
+		if _, ok := currentByKey[key(c)]; ok {
+			drift = append(drift, fmt.Sprintf("%s %s not in approved plan", c.Kind, c.Path))
+		}
+	}
+	sort.Strings(drift)
+	return drift
+}
+
+// planFs resolves the source and destination filesystems, swapping them for "pull"
+func planFs(ctx context.Context, action string, profile models.Profile) (fs.Fs, fs.Fs, error) {
+	switch action {
+	case "push", "copy":
+	case "pull":
+		profile.From, profile.To = profile.To, profile.From
+	default:
+		return nil, nil, fmt.Errorf("plans are not supported for action %q", action)
+	}
+
+	srcFs, err := newTaskFs(ctx, profile.From)
+	if utils.HandleError(err, "Failed to initialize source filesystem", nil, nil) != nil {
+		return nil, nil, err
+	}
+
+	dstFs, err := newTaskFs(ctx, profile.To)
+	if utils.HandleError(err, "Failed to initialize destination filesystem", nil, nil) != nil {
+		return nil, nil, err
+	}
+	return srcFs, dstFs, nil
+}
+
+// planCollector records the dry-run decisions of rclone's sync logger
+type planCollector struct {
+	mu      sync.Mutex
+	copies  []fs.Object
+	updates []fs.Object
+	deletes []fs.Object
+}
+
+func (p *planCollector) log(ctx context.Context, sigil operations.Sigil, src, dst fs.DirEntry, err error) {
+	if err != nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch sigil {
+	case operations.MissingOnDst:
+		if obj, ok := src.(fs.Object); ok {
+			p.copies = append(p.copies, obj)
+		}
+	case operations.Differ:
+		if obj, ok := src.(fs.Object); ok {
+			p.updates = append(p.updates, obj)
+		}
+	case operations.MissingOnSrc:
+		if operations.GetLoggerOpt(ctx).DeleteModeOff {
+			return
+		}
+		if obj, ok := dst.(fs.Object); ok {
+			p.deletes = append(p.deletes, obj)
+		}
+	}
+}
+
+// changes builds the sorted change set, pairing deletes with copies into renames
+func (p *planCollector) changes(ctx context.Context, hashType hash.Type) []models.PlanChange {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var changes []models.PlanChange
+	renamedFrom := make(map[fs.Object]bool)
+
+	for _, src := range p.copies {
+		change := models.PlanChange{
+			Kind:    models.PlanCopy,
+			Path:    src.Remote(),
+			Size:    src.Size(),
+			ModTime: src.ModTime(ctx),
+		}
+		if dst := p.findRenameSource(ctx, hashType, src, renamedFrom); dst != nil {
+			renamedFrom[dst] = true
+			change.Kind = models.PlanRename
+			change.From = dst.Remote()
+		}
+		changes = append(changes, change)
+	}
+	for _, src := range p.updates {
+		changes = append(changes, models.PlanChange{
+			Kind:    models.PlanUpdate,
+			Path:    src.Remote(),
+			Size:    src.Size(),
+			ModTime: src.ModTime(ctx),
+		})
+	}
+	for _, dst := range p.deletes {
+		if renamedFrom[dst] {
+			continue
+		}
+		changes = append(changes, models.PlanChange{
+			Kind:    models.PlanDelete,
+			Path:    dst.Remote(),
+			Size:    dst.Size(),
+			ModTime: dst.ModTime(ctx),
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// findRenameSource returns an unclaimed destination-only object with the same size and
+// hash as src, or nil when there is none or no common hash type is available
+func (p *planCollector) findRenameSource(ctx context.Context, hashType hash.Type, src fs.Object, claimed map[fs.Object]bool) fs.Object {
+	if hashType == hash.None || src.Size() <= 0 {
+		return nil
+	}
+
+	var srcHash string
+	for _, dst := range p.deletes {
+		if claimed[dst] || dst.Size() != src.Size() {
+			continue
+		}
+		if srcHash == "" {
+			h, err := src.Hash(ctx, hashType)
+			if err != nil || h == "" {
+				return nil
+			}
+			srcHash = h
+		}
+		if dstHash, err := dst.Hash(ctx, hashType); err == nil && dstHash == srcHash {
+			return dst
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package rclone
+
+import (
+	"context"
+	beConfig "desktop/backend/config"
+	"desktop/backend/dto"
+	"desktop/backend/models"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTestFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+// TestPlanAndApply tests that a planned push is applied exactly and that drift is detected
+func TestPlanAndApply(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+
+	writeTestFile(t, srcDir, "new.txt", "new file")
+	writeTestFile(t, srcDir, "changed.txt", "changed content")
+	writeTestFile(t, srcDir, "moved.txt", "content that moved")
+	writeTestFile(t, dstDir, "changed.txt", "old")
+	writeTestFile(t, dstDir, "stale.txt", "only on destination")
+	writeTestFile(t, dstDir, "original.txt", "content that moved")
+
+	ctx := context.Background()
+	profile := models.Profile{Name: "plan-test", From: srcDir, To: dstDir, Parallel: 2}
+
+	changes, err := PlanSync(ctx, "push", profile)
+	if err != nil {
+		t.Fatalf("PlanSync failed: %v", err)
+	}
+
+	kinds := make(map[string]models.PlanChangeKind)
+	for _, c := range changes {
+		kinds[c.Path] = c.Kind
+	}
+	want := map[string]models.PlanChangeKind{
+		"new.txt":     models.PlanCopy,
+		"changed.txt": models.PlanUpdate,
+		"stale.txt":   models.PlanDelete,
+		"moved.txt":   models.PlanRename,
+	}
+	if len(kinds) != len(want) {
+		t.Fatalf("expected %d changes, got %+v", len(want), changes)
+	}
+	for path, kind := range want {
+		if kinds[path] != kind {
+			t.Errorf("%s: expected %s, got %s", path, kind, kinds[path])
+		}
+	}
+
+	// Planning must not modify the destination
+	if _, err := os.Stat(filepath.Join(dstDir, "stale.txt")); err != nil {
+		t.Fatalf("planning modified destination: %v", err)
+	}
+
+	// No drift against a fresh plan
+	current, err := PlanSync(ctx, "push", profile)
+	if err != nil {
+		t.Fatalf("PlanSync failed: %v", err)
+	}
+	if drift := DiffPlanChanges(changes, current); len(drift) != 0 {
+		t.Fatalf("unexpected drift: %v", drift)
+	}
+
+	// Drift after the source changes
+	later := time.Now().Add(time.Hour)
+	writeTestFile(t, srcDir, "new.txt", "new file, edited")
+	if err := os.Chtimes(filepath.Join(srcDir, "new.txt"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	current, err = PlanSync(ctx, "push", profile)
+	if err != nil {
+		t.Fatalf("PlanSync failed: %v", err)
+	}
+	if drift := DiffPlanChanges(changes, current); len(drift) == 0 {
+		t.Fatal("expected drift after source change")
+	}
+
+	// Apply the fresh plan
+	outStatus := make(chan *dto.SyncStatusDTO, 100)
+	go func() {
+		for range outStatus {
+		}
+	}()
+	taskCtx, err := NewTaskContext(ctx, 9001)
+	if err != nil {
+		t.Fatalf("NewTaskContext failed: %v", err)
+	}
+	err = ApplyPlan(taskCtx, beConfig.Config{}, "push", profile, current, outStatus)
+	close(outStatus)
+	if err != nil {
+		t.Fatalf("ApplyPlan failed: %v", err)
+	}
+
+	for name, content := range map[string]string{
+		"new.txt":     "new file, edited",
+		"changed.txt": "changed content",
+		"moved.txt":   "content that moved",
+	} {
+		data, err := os.ReadFile(filepath.Join(dstDir, name))
+		if err != nil || string(data) != content {
+			t.Errorf("%s: expected %q, got %q (%v)", name, content, data, err)
+		}
+	}
+	for _, name := range []string{"stale.txt", "original.txt"} {
+		if _, err := os.Stat(filepath.Join(dstDir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s should have been removed from destination", name)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package rclone
+
+import (
+	"desktop/backend/models"
+	"fmt"
+	"strings"
+
+	"github.com/rclone/rclone/fs"
+
+	// import fs drivers; every backend imported here must be declared in providers
+	_ "github.com/rclone/rclone/backend/cache"
+	_ "github.com/rclone/rclone/backend/crypt"
+	_ "github.com/rclone/rclone/backend/drive"
+	_ "github.com/rclone/rclone/backend/dropbox"
+	_ "github.com/rclone/rclone/backend/ftp"
+	_ "github.com/rclone/rclone/backend/googlephotos"
+	_ "github.com/rclone/rclone/backend/http"
+	_ "github.com/rclone/rclone/backend/iclouddrive"
+	_ "github.com/rclone/rclone/backend/local"
+	_ "github.com/rclone/rclone/backend/onedrive"
+	_ "github.com/rclone/rclone/backend/s3"
+	_ "github.com/rclone/rclone/backend/sftp"
+	_ "github.com/rclone/rclone/backend/webdav"
+	_ "github.com/rclone/rclone/backend/yandex"
+)
+
+// providers declares the storage backends the app supports
+var providers = []models.Provider{
+	{Type: "drive", Name: "Google Drive", Description: "Google Drive", OAuth: true},
+	{Type: "dropbox", Name: "Dropbox", Description: "Dropbox", OAuth: true},
+	{Type: "onedrive", Name: "OneDrive", Description: "Microsoft OneDrive", OAuth: true},
+	{Type: "yandex", Name: "Yandex Disk", Description: "Yandex Disk", OAuth: true},
+	{Type: "gphotos", Name: "Google Photos", Description: "Google Photos", OAuth: true},
+	{Type: "iclouddrive", Name: "iCloud Drive", Description: "Apple iCloud Drive"},
+	{Type: "s3", Name: "S3", Description: "Amazon S3 Compatible Storage (AWS, MinIO, Ceph, ...)", RequiredFields: []string{"provider"}},
+	{Type: "sftp", Name: "SFTP", Description: "SSH/SFTP server", RequiredFields: []string{"host"}},
+	{Type: "webdav", Name: "WebDAV", Description: "WebDAV server (Nextcloud, ownCloud, NAS, ...)", RequiredFields: []string{"url"}},
+	{Type: "ftp", Name: "FTP", Description: "FTP server", RequiredFields: []string{"host"}},
+	{Type: "http", Name: "HTTP", Description: "Read-only HTTP directory listing", RequiredFields: []string{"url"}},
+	{Type: "local", Name: "Local", Description: "Local Filesystem"},
+	{Type: "crypt", Name: "Crypt", Description: "Encrypted Remote", RequiredFields: []string{"remote", "password"}},
+	{Type: "cache", Name: "Cache", Description: "Cached Remote", RequiredFields: []string{"remote"}},
+}
+
+// Providers returns the supported storage backends
+func Providers() []models.Provider {
+	out := make([]models.Provider, len(providers))
+	copy(out, providers)
+	return out
+}
+
+// FindProvider returns the supported backend with the given rclone type, also accepting
+// the backend's other rclone names (e.g. "googlephotos" for "gphotos")
+func FindProvider(remoteType string) (models.Provider, bool) {
+	if info, err := fs.Find(remoteType); err == nil {
+		remoteType = info.Prefix
+	}
+	for _, p := range providers {
+		if p.Type == remoteType {
+			return p, true
+		}
+	}
+	return models.Provider{}, false
+}
+
+// ProviderDescription describes a remote type, falling back to the type itself for
+// remotes configured outside the app with an unsupported backend
+func ProviderDescription(remoteType string) string {
+	if p, ok := FindProvider(remoteType); ok {
+		return p.Description
+	}
+	return fmt.Sprintf("Remote type: %s", remoteType)
+}
+
+// ValidateProviderConfig checks that a remote type is supported, that the config sets
+// all of its required fields and that the values of known options are valid
+func ValidateProviderConfig(remoteType string, config map[string]string) error {
+	p, ok := FindProvider(remoteType)
+	if !ok {
+		return fmt.Errorf("unsupported remote type %q", remoteType)
+	}
+	if info, err := fs.Find(remoteType); err == nil {
+		for key, value := range config {
+			if opt := info.Options.Get(key); opt != nil && value != "" {
+				if err := validateOptionValue(opt, value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	var missing []string
+	for _, field := range p.RequiredFields {
+		if strings.TrimSpace(config[field]) == "" {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s remote requires %s", p.Name, strings.Join(missing, ", "))
+	}
+	return nil
+}
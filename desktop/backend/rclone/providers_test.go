@@ -2,10 +2,21 @@ package rclone
 
 import (
 	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	_ "github.com/rclone/rclone/cmd/serve/ftp"
+	_ "github.com/rclone/rclone/cmd/serve/http"
+	_ "github.com/rclone/rclone/cmd/serve/s3"
+	_ "github.com/rclone/rclone/cmd/serve/sftp"
+	_ "github.com/rclone/rclone/cmd/serve/webdav"
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/fs/config/obscure"
+	"github.com/rclone/rclone/fs/rc"
 )
 
 // TestProviderRegistration tests that all required providers are registered
@@ -23,6 +34,12 @@ func TestProviderRegistration(t *testing.T) {
 		{"iCloud Drive", "iclouddrive"},
 		{"Local", "local"},
 		{"Cache", "cache"},
+		{"Crypt", "crypt"},
+		{"S3", "s3"},
+		{"SFTP", "sftp"},
+		{"WebDAV", "webdav"},
+		{"FTP", "ftp"},
+		{"HTTP", "http"},
 	}
 
 	for _, tc := range testCases {
@@ -122,3 +139,112 @@ func TestConfigCreation(t *testing.T) {
 		})
 	}
 }
+
+// TestProviderRegistry tests that every declared provider is registered with rclone
+// and that required fields are enforced
+func TestProviderRegistry(t *testing.T) {
+	for _, p := range Providers() {
+		if _, err := fs.Find(p.Type); err != nil {
+			t.Errorf("Provider %s is declared but not registered: %v", p.Type, err)
+		}
+		if p.Name == "" || p.Description == "" {
+			t.Errorf("Provider %s needs a name and description", p.Type)
+		}
+	}
+
+	if err := ValidateProviderConfig("sftp", map[string]string{"host": "nas.local"}); err != nil {
+		t.Errorf("expected valid sftp config, got %v", err)
+	}
+	if err := ValidateProviderConfig("s3", map[string]string{}); err == nil || !strings.Contains(err.Error(), "provider") {
+		t.Errorf("expected missing provider error, got %v", err)
+	}
+	if err := ValidateProviderConfig("invalid", nil); err == nil {
+		t.Error("expected error for unsupported type")
+	}
+	if p, ok := FindProvider("googlephotos"); !ok || p.Type != "gphotos" {
+		t.Errorf("expected googlephotos to resolve to gphotos, got %+v", p)
+	}
+	if got := ProviderDescription("webdav"); got == "" || strings.HasPrefix(got, "Remote type") {
+		t.Errorf("unexpected webdav description %q", got)
+	}
+}
+
+// startTestServer serves dir with rclone's in-process server of the given type and
+// returns its address
+func startTestServer(t *testing.T, serveType, dir string, params rc.Params) string {
+	t.Helper()
+	in := rc.Params{"type": serveType, "fs": dir, "addr": "127.0.0.1:0"}
+	for k, v := range params {
+		in[k] = v
+	}
+	out, err := rc.Calls.Get("serve/start").Fn(context.Background(), in)
+	if err != nil {
+		t.Fatalf("failed to start %s server: %v", serveType, err)
+	}
+	t.Cleanup(func() {
+		_, _ = rc.Calls.Get("serve/stop").Fn(context.Background(), rc.Params{"id": out["id"]})
+	})
+	addr, _ := out["addr"].(string)
+	return addr
+}
+
+// TestProvidersAgainstLocalServers tests that the network backends can list and read
+// from in-process servers
+func TestProvidersAgainstLocalServers(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "bucket"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, dir, "bucket/hello.txt", "hello from the nas")
+	pass := obscure.MustObscure("secret")
+
+	testCases := []struct {
+		name   string
+		remote func(t *testing.T) string
+	}{
+		{"sftp", func(t *testing.T) string {
+			host, port, _ := net.SplitHostPort(startTestServer(t, "sftp", dir, rc.Params{"user": "nas", "pass": "secret"}))
+			return ":sftp,host=" + host + ",port=" + port + ",user=nas,pass=" + pass + ":bucket"
+		}},
+		{"ftp", func(t *testing.T) string {
+			// The ftp server reports the configured address, so pick a free port up front
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			addr := l.Addr().String()
+			_ = l.Close()
+			startTestServer(t, "ftp", dir, rc.Params{"addr": addr, "user": "nas", "pass": "secret"})
+			host, port, _ := net.SplitHostPort(addr)
+			return ":ftp,host=" + host + ",port=" + port + ",user=nas,pass=" + pass + ":bucket"
+		}},
+		{"webdav", func(t *testing.T) string {
+			addr := startTestServer(t, "webdav", dir, nil)
+			return ":webdav,url='http://" + addr + "/':bucket"
+		}},
+		{"http", func(t *testing.T) string {
+			addr := startTestServer(t, "http", dir, nil)
+			return ":http,url='http://" + addr + "/':bucket"
+		}},
+		{"s3", func(t *testing.T) string {
+			addr := startTestServer(t, "s3", dir, rc.Params{"auth_key": []string{"access,secretkey"}})
+			return ":s3,provider=Rclone,endpoint='http://" + addr + "',access_key_id=access,secret_access_key=secretkey:bucket"
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, err := NewTaskContext(context.Background(), 9010)
+			if err != nil {
+				t.Fatalf("NewTaskContext failed: %v", err)
+			}
+			entries, err := ListFiles(ctx, tc.remote(t), false)
+			if err != nil {
+				t.Fatalf("ListFiles failed: %v", err)
+			}
+			if len(entries) != 1 || entries[0].Name != "hello.txt" || entries[0].Size != int64(len("hello from the nas")) {
+				t.Errorf("unexpected listing: %+v", entries)
+			}
+		})
+	}
+}
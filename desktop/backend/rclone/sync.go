@@ -12,20 +12,6 @@ import (
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/filter"
 	fssync "github.com/rclone/rclone/fs/sync"
-
-	// import fs drivers
-
-	_ "github.com/rclone/rclone/backend/cache"
-
-	_ "github.com/rclone/rclone/backend/drive"
-
-	_ "github.com/rclone/rclone/backend/local"
-
-	_ "github.com/rclone/rclone/backend/dropbox"
-	_ "github.com/rclone/rclone/backend/googlephotos"
-	_ "github.com/rclone/rclone/backend/iclouddrive"
-	_ "github.com/rclone/rclone/backend/onedrive"
-	_ "github.com/rclone/rclone/backend/yandex"
 )
 
 func Sync(ctx context.Context, config beConfig.Config, task string, profile models.Profile, outStatus chan *dto.SyncStatusDTO) error {
@@ -39,21 +25,19 @@ func Sync(ctx context.Context, config beConfig.Config, task string, profile mode
 		profile.From, profile.To = profile.To, profile.From
 	}
 
-	srcFs, err := fs.NewFs(ctx, profile.From)
+	srcFs, err := newTaskFs(ctx, profile.From)
 	if utils.HandleError(err, "Failed to initialize source filesystem", nil, nil) != nil {
 		return err
 	}
 
-	dstFs, err := fs.NewFs(ctx, profile.To)
+	dstFs, err := newTaskFs(ctx, profile.To)
 	if utils.HandleError(err, "Failed to initialize destination filesystem", nil, nil) != nil {
 		return err
 	}
 
 	// Set bandwidth limit
-	if profile.Bandwidth > 0 {
-		if err := utils.HandleError(fsConfig.BwLimit.Set(fmt.Sprint(profile.Bandwidth)+"M"), "Failed to set bandwidth limit", nil, nil); err != nil {
-			return err
-		}
+	if err := utils.HandleError(setBandwidthLimit(fsConfig, profile), "Failed to set bandwidth limit", nil, nil); err != nil {
+		return err
 	}
 
 	// Set up filter rules (prefix with {{regexp:}} if UseRegex is enabled)
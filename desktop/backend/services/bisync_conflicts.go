@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"desktop/backend/models"
+	"desktop/backend/rclone"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetConflicts returns the recorded bisync conflicts, newest first. An empty status
+// returns all of them, otherwise only those with the given status ("open", "resolved").
+func (s *BisyncStateService) GetConflicts(ctx context.Context, status string) ([]models.BisyncConflict, error) {
+	return s.loadConflicts("", status)
+}
+
+// GetConflict returns a single conflict by ID
+func (s *BisyncStateService) GetConflict(ctx context.Context, conflictId string) (*models.BisyncConflict, error) {
+	conflicts, err := s.loadConflicts(conflictId, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) == 0 {
+		return nil, fmt.Errorf("conflict '%s' not found", conflictId)
+	}
+	return &conflicts[0], nil
+}
+
+// ResolveConflict applies "keep_source", "keep_destination" or "keep_both" to both
+// remotes of the pair and marks the conflict resolved
+func (s *BisyncStateService) ResolveConflict(ctx context.Context, conflictId, resolution string) error {
+	conflict, err := s.GetConflict(ctx, conflictId)
+	if err != nil {
+		return err
+	}
+	if conflict.Status != "open" {
+		return fmt.Errorf("conflict '%s' is already %s", conflictId, conflict.Status)
+	}
+
+	rcloneCtx, err := rclone.SimpleContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize rclone config: %w", err)
+	}
+	if err := rclone.ResolveBisyncConflict(rcloneCtx, *conflict, resolution); err != nil {
+		return err
+	}
+
+	db, err := GetSharedDB()
+	if err != nil {
+		return err
+	}
+	resolvedAt := time.Now()
+	if _, err := db.Exec("UPDATE bisync_conflicts SET status = 'resolved', resolution = ?, resolved_at = ? WHERE id = ?",
+		resolution, timePtrToNullable(&resolvedAt), conflictId); err != nil {
+		return fmt.Errorf("failed to update conflict: %w", err)
+	}
+	return nil
+}
+
+// OpenConflictVersions downloads both versions of a conflict to a temporary directory and
+// opens them with the system's default application. Returns the local source and
+// destination paths.
+func (s *BisyncStateService) OpenConflictVersions(ctx context.Context, conflictId string) ([]string, error) {
+	conflict, err := s.GetConflict(ctx, conflictId)
+	if err != nil {
+		return nil, err
+	}
+
+	rcloneCtx, err := rclone.SimpleContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize rclone config: %w", err)
+	}
+	dir := filepath.Join(os.TempDir(), "ns-drive-conflicts", conflictId)
+	srcLocal, dstLocal, err := rclone.DownloadConflictVersions(rcloneCtx, *conflict, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{srcLocal, dstLocal}
+	if s.app != nil {
+		for _, p := range paths {
+			if err := s.app.Browser.OpenFile(p); err != nil {
+				return paths, fmt.Errorf("failed to open %s: %w", p, err)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// DeleteConflict removes a conflict from the inbox without touching any files
+func (s *BisyncStateService) DeleteConflict(ctx context.Context, conflictId string) error {
+	db, err := GetSharedDB()
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec("DELETE FROM bisync_conflicts WHERE id = ?", conflictId); err != nil {
+		return fmt.Errorf("failed to delete conflict: %w", err)
+	}
+	return nil
+}
+
+// RecordConflicts stores the conflicts of a bisync run as open inbox items
+func (s *BisyncStateService) RecordConflicts(profile models.Profile, conflicts []models.BisyncConflict) error {
+	db, err := GetSharedDB()
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, c := range conflicts {
+		if c.Id == "" {
+			c.Id = uuid.New().String()
+		}
+		sourceJSON, err := json.Marshal(c.Source)
+		if err != nil {
+			return err
+		}
+		destinationJSON, err := json.Marshal(c.Destination)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`INSERT INTO bisync_conflicts (id, profile_name, from_path, to_path, path, winner,
+			source, destination, status, resolution, detected_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			c.Id, c.ProfileName, c.From, c.To, c.Path, c.Winner, string(sourceJSON), string(destinationJSON),
+			c.Status, c.Resolution, c.DetectedAt.UTC().Format(time.RFC3339)); err != nil {
+			return fmt.Errorf("failed to save conflict: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit conflicts: %w", err)
+	}
+	return nil
+}
+
+// loadConflicts loads all conflicts, or only the given one when conflictId is set
+func (s *BisyncStateService) loadConflicts(conflictId, status string) ([]models.BisyncConflict, error) {
+	db, err := GetSharedDB()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id, profile_name, from_path, to_path, path, winner, source, destination, status,
+		resolution, detected_at, resolved_at FROM bisync_conflicts`
+	var rows *sql.Rows
+	switch {
+	case conflictId != "":
+		rows, err = db.Query(query+" WHERE id = ?", conflictId)
+	case status != "":
+		rows, err = db.Query(query+" WHERE status = ? ORDER BY detected_at DESC", status)
+	default:
+		rows, err = db.Query(query + " ORDER BY detected_at DESC")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	conflicts := []models.BisyncConflict{}
+	for rows.Next() {
+		var c models.BisyncConflict
+		var sourceJSON, destinationJSON, detectedAt string
+		var resolvedAt *string
+		if err := rows.Scan(&c.Id, &c.ProfileName, &c.From, &c.To, &c.Path, &c.Winner, &sourceJSON,
+			&destinationJSON, &c.Status, &c.Resolution, &detectedAt, &resolvedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan conflict: %w", err)
+		}
+		if err := json.Unmarshal([]byte(sourceJSON), &c.Source); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal source of conflict %s: %w", c.Id, err)
+		}
+		if err := json.Unmarshal([]byte(destinationJSON), &c.Destination); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal destination of conflict %s: %w", c.Id, err)
+		}
+		if t, err := time.Parse(time.RFC3339, detectedAt); err == nil {
+			c.DetectedAt = t
+		}
+		c.ResolvedAt = parseNullableTime(resolvedAt)
+		conflicts = append(conflicts, c)
+	}
+	return conflicts, rows.Err()
+}
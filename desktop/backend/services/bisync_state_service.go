@@ -0,0 +1,425 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	beConfig "desktop/backend/config"
+	"desktop/backend/models"
+	"desktop/backend/rclone"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// BisyncStateService exposes the state of bisync pairs and keeps the resync bookkeeping
+// BiSync uses to decide when a pair must be resynced. It implements rclone.BisyncStateStore.
+type BisyncStateService struct {
+	app         *application.App
+	envConfig   beConfig.Config
+	mutex       sync.RWMutex
+	initialized bool
+}
+
+// NewBisyncStateService creates a new bisync state service
+func NewBisyncStateService(app *application.App) *BisyncStateService {
+	return &BisyncStateService{
+		app: app,
+	}
+}
+
+// SetApp sets the application reference
+func (s *BisyncStateService) SetApp(app *application.App) {
+	s.app = app
+}
+
+// SetEnvConfig sets the env config, used to locate the legacy resync file
+func (s *BisyncStateService) SetEnvConfig(config beConfig.Config) {
+	s.envConfig = config
+}
+
+// ServiceName returns the name of the service
+func (s *BisyncStateService) ServiceName() string {
+	return "BisyncStateService"
+}
+
+// ServiceStartup is called when the service starts.
+// Initialization is deferred to first access to speed up app startup.
+func (s *BisyncStateService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	log.Printf("BisyncStateService starting up (lazy init)...")
+	return nil
+}
+
+// ServiceShutdown is called when the service shuts down
+func (s *BisyncStateService) ServiceShutdown(ctx context.Context) error {
+	log.Printf("BisyncStateService shutting down...")
+	return nil
+}
+
+// ensureInitialized lazily initializes the service on first access.
+func (s *BisyncStateService) ensureInitialized() error {
+	s.mutex.RLock()
+	if s.initialized {
+		s.mutex.RUnlock()
+		return nil
+	}
+	s.mutex.RUnlock()
+	return s.initialize()
+}
+
+// initialize imports the legacy resync file on first use
+func (s *BisyncStateService) initialize() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.initialized {
+		return nil
+	}
+
+	db, err := GetSharedDB()
+	if err != nil {
+		return err
+	}
+	s.migrateResyncFile(db)
+
+	s.initialized = true
+	log.Printf("BisyncStateService initialized")
+	return nil
+}
+
+// ============ Public API ============
+
+// GetBisyncStates returns every known bisync pair with its listing and lock status
+func (s *BisyncStateService) GetBisyncStates(ctx context.Context) ([]models.BisyncState, error) {
+	states, err := s.loadStates("", "")
+	if err != nil {
+		return nil, err
+	}
+
+	rcloneCtx, err := rclone.SimpleContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize rclone config: %w", err)
+	}
+	for i := range states {
+		inspectBisyncFiles(rcloneCtx, &states[i])
+	}
+	return states, nil
+}
+
+// GetBisyncState returns the state of a single pair
+func (s *BisyncStateService) GetBisyncState(ctx context.Context, from, to string) (*models.BisyncState, error) {
+	states, err := s.loadStates(from, to)
+	if err != nil {
+		return nil, err
+	}
+	state := models.BisyncState{From: from, To: to}
+	if len(states) > 0 {
+		state = states[0]
+	}
+
+	rcloneCtx, err := rclone.SimpleContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize rclone config: %w", err)
+	}
+	inspectBisyncFiles(rcloneCtx, &state)
+	return &state, nil
+}
+
+// ForceResync makes the next bisync run of the pair a resync
+func (s *BisyncStateService) ForceResync(ctx context.Context, from, to string) error {
+	if err := s.ensureInitialized(); err != nil {
+		return err
+	}
+	db, err := GetSharedDB()
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(`INSERT INTO bisync_state (from_path, to_path, resync_pending) VALUES (?, ?, 1)
+		ON CONFLICT(from_path, to_path) DO UPDATE SET resync_pending = 1`, from, to); err != nil {
+		return fmt.Errorf("failed to force resync: %w", err)
+	}
+	return nil
+}
+
+// ClearLock removes the lock file of a pair left behind by a crashed or expired run.
+// Locks held by a bisync running in this process are never removed.
+func (s *BisyncStateService) ClearLock(ctx context.Context, from, to string) error {
+	session, err := s.getSession(ctx, from, to)
+	if err != nil {
+		return err
+	}
+
+	lock, err := session.ReadLock()
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		return nil
+	}
+	if !lock.Stale() {
+		return fmt.Errorf("bisync of '%s' and '%s' is still running", from, to)
+	}
+	return session.RemoveLock()
+}
+
+// DeleteState forgets a pair: its listings, lock file and bookkeeping are removed, so
+// its next run will be a resync
+func (s *BisyncStateService) DeleteState(ctx context.Context, from, to string) error {
+	session, err := s.getSession(ctx, from, to)
+	if err != nil {
+		return err
+	}
+
+	lock, err := session.ReadLock()
+	if err != nil {
+		return err
+	}
+	if lock != nil && !lock.Stale() {
+		return fmt.Errorf("bisync of '%s' and '%s' is still running", from, to)
+	}
+	if err := session.RemoveListings(); err != nil {
+		return err
+	}
+	if err := session.RemoveLock(); err != nil {
+		return err
+	}
+
+	db, err := GetSharedDB()
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec("DELETE FROM bisync_state WHERE from_path = ? AND to_path = ?", from, to); err != nil {
+		return fmt.Errorf("failed to delete bisync state: %w", err)
+	}
+	return nil
+}
+
+// ============ rclone.BisyncStateStore ============
+
+// PairState reports whether the pair has a row and whether a resync was requested for it
+func (s *BisyncStateService) PairState(profile models.Profile) (bool, bool, error) {
+	states, err := s.loadStates(profile.From, profile.To)
+	if err != nil {
+		return false, false, err
+	}
+	if len(states) == 0 {
+		return false, false, nil
+	}
+	return true, states[0].ResyncPending, nil
+}
+
+// ResyncReason reports why the pair must be resynced, or "" when a normal run is fine.
+// BiSync only asks once the pair has listings, so a pair without a row was synced before
+// the state was kept here (or outside the app): its state is recorded with the current
+// filter rules rather than forcing a resync, which could bring back deleted files.
+func (s *BisyncStateService) ResyncReason(profile models.Profile, filterHash string) (string, error) {
+	states, err := s.loadStates(profile.From, profile.To)
+	if err != nil {
+		return "", err
+	}
+	if len(states) == 0 {
+		db, err := GetSharedDB()
+		if err != nil {
+			return "", err
+		}
+		if _, err := db.Exec(`INSERT OR IGNORE INTO bisync_state (from_path, to_path, profile_name, filter_hash)
+			VALUES (?, ?, ?, ?)`, profile.From, profile.To, profile.Name, filterHash); err != nil {
+			return "", fmt.Errorf("failed to record bisync state: %w", err)
+		}
+		log.Printf("Recorded bisync state for existing pair %s <-> %s", profile.From, profile.To)
+		return "", nil
+	}
+
+	state := states[0]
+	switch {
+	case state.ResyncPending:
+		return "resync was requested", nil
+	case state.FilterHash != filterHash:
+		return "filter rules changed", nil
+	}
+	return "", nil
+}
+
+// RecordRun stores the outcome of a bisync run. The filter hash only advances after a
+// successful resync, and a failed resync is left pending, so it is retried on the next run.
+func (s *BisyncStateService) RecordRun(profile models.Profile, filterHash string, resync bool, runErr error) error {
+	if err := s.ensureInitialized(); err != nil {
+		return err
+	}
+	db, err := GetSharedDB()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	if _, err := db.Exec(`INSERT INTO bisync_state (from_path, to_path) VALUES (?, ?)
+		ON CONFLICT(from_path, to_path) DO NOTHING`, profile.From, profile.To); err != nil {
+		return fmt.Errorf("failed to record bisync run: %w", err)
+	}
+
+	if runErr != nil && resync {
+		_, err = db.Exec(`UPDATE bisync_state SET profile_name = ?, resync_pending = 1, last_run_at = ?, last_error = ?
+			WHERE from_path = ? AND to_path = ?`, profile.Name, now, runErr.Error(), profile.From, profile.To)
+	} else if runErr != nil {
+		_, err = db.Exec(`UPDATE bisync_state SET profile_name = ?, last_run_at = ?, last_error = ?
+			WHERE from_path = ? AND to_path = ?`, profile.Name, now, runErr.Error(), profile.From, profile.To)
+	} else if resync {
+		_, err = db.Exec(`UPDATE bisync_state SET profile_name = ?, filter_hash = ?, resync_pending = 0,
+			last_run_at = ?, last_success_at = ?, last_resync_at = ?, last_error = ''
+			WHERE from_path = ? AND to_path = ?`, profile.Name, filterHash, now, now, now, profile.From, profile.To)
+	} else {
+		_, err = db.Exec(`UPDATE bisync_state SET profile_name = ?, last_run_at = ?, last_success_at = ?, last_error = ''
+			WHERE from_path = ? AND to_path = ?`, profile.Name, now, now, profile.From, profile.To)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record bisync run: %w", err)
+	}
+	return nil
+}
+
+// ============ Helpers ============
+
+// getSession resolves the rclone session files of a pair
+func (s *BisyncStateService) getSession(ctx context.Context, from, to string) (*rclone.BisyncSession, error) {
+	rcloneCtx, err := rclone.SimpleContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize rclone config: %w", err)
+	}
+	return rclone.GetBisyncSession(rcloneCtx, from, to)
+}
+
+// inspectBisyncFiles fills in the listing and lock details of a state
+func inspectBisyncFiles(ctx context.Context, state *models.BisyncState) {
+	session, err := rclone.GetBisyncSession(ctx, state.From, state.To)
+	if err != nil {
+		state.InspectError = err.Error()
+		return
+	}
+
+	state.Workdir = session.Workdir
+	state.ListingPath1 = session.Listing1
+	state.ListingPath2 = session.Listing2
+	state.ListingsPresent = session.HasListings()
+	state.LockFile = session.LockFile
+
+	lock, err := session.ReadLock()
+	if err != nil {
+		state.InspectError = err.Error()
+		return
+	}
+	if lock != nil {
+		state.Locked = true
+		state.LockPID = lock.PID
+		state.LockStale = lock.Stale()
+		if !lock.ExpiresAt.IsZero() {
+			expires := lock.ExpiresAt
+			state.LockExpiresAt = &expires
+		}
+	}
+}
+
+// loadStates loads all pairs, or only the given one when from and to are set
+func (s *BisyncStateService) loadStates(from, to string) ([]models.BisyncState, error) {
+	if err := s.ensureInitialized(); err != nil {
+		return nil, err
+	}
+	db, err := GetSharedDB()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT from_path, to_path, profile_name, filter_hash, resync_pending,
+		last_run_at, last_success_at, last_resync_at, last_error FROM bisync_state`
+	var rows *sql.Rows
+	if from != "" || to != "" {
+		rows, err = db.Query(query+" WHERE from_path = ? AND to_path = ?", from, to)
+	} else {
+		rows, err = db.Query(query + " ORDER BY from_path, to_path")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bisync state: %w", err)
+	}
+	defer rows.Close()
+
+	states := []models.BisyncState{}
+	for rows.Next() {
+		var st models.BisyncState
+		var resyncPending int
+		var lastRun, lastSuccess, lastResync *string
+		if err := rows.Scan(&st.From, &st.To, &st.ProfileName, &st.FilterHash, &resyncPending,
+			&lastRun, &lastSuccess, &lastResync, &st.LastError); err != nil {
+			return nil, fmt.Errorf("failed to scan bisync state: %w", err)
+		}
+		st.ResyncPending = resyncPending != 0
+		st.LastRunAt = parseNullableTime(lastRun)
+		st.LastSuccessAt = parseNullableTime(lastSuccess)
+		st.LastResyncAt = parseNullableTime(lastResync)
+		states = append(states, st)
+	}
+	return states, rows.Err()
+}
+
+// parseNullableTime parses an optional RFC3339 column
+func parseNullableTime(s *string) *time.Time {
+	if s == nil {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, *s)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// migrateResyncFile imports the "From|To|hash" lines of the legacy resync file. The
+// file used to be resolved against the working directory, so both locations are tried.
+func (s *BisyncStateService) migrateResyncFile(db *sql.DB) {
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM bisync_state").Scan(&count); err != nil || count > 0 {
+		return
+	}
+	if s.envConfig.ResyncFilePath == "" {
+		return
+	}
+
+	candidates := []string{s.envConfig.ResyncFilePath}
+	if cfg := GetSharedConfig(); cfg != nil && cfg.WorkingDir != "" {
+		candidates = append(candidates, filepath.Join(cfg.WorkingDir, s.envConfig.ResyncFilePath))
+	}
+
+	migrated := 0
+	for _, path := range candidates {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			log.Printf("Warning: failed to read resync file %s for migration: %v", path, err)
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			parts := strings.Split(line, "|")
+			if len(parts) != 3 {
+				continue
+			}
+			res, err := db.Exec(`INSERT OR IGNORE INTO bisync_state (from_path, to_path, filter_hash) VALUES (?, ?, ?)`,
+				parts[0], parts[1], parts[2])
+			if err != nil {
+				continue
+			}
+			if n, _ := res.RowsAffected(); n > 0 {
+				migrated++
+			}
+		}
+	}
+	if migrated > 0 {
+		log.Printf("Migrated %d bisync pairs from resync file", migrated)
+	}
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"desktop/backend/models"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestBisyncStateService(t *testing.T) *BisyncStateService {
+	t.Helper()
+	// Clean DB table for test isolation
+	db, _ := GetSharedDB()
+	db.Exec("DELETE FROM bisync_state")
+	return &BisyncStateService{
+		initialized: true,
+	}
+}
+
+func TestBisyncStateService_ResyncBookkeeping(t *testing.T) {
+	s := newTestBisyncStateService(t)
+	profile := models.Profile{Name: "docs", From: "/src", To: "remote:dst"}
+
+	// A pair with listings but no state was synced before; it is adopted, not resynced
+	adopted := models.Profile{Name: "photos", From: "/photos", To: "remote:photos"}
+	reason, err := s.ResyncReason(adopted, "0")
+	if err != nil {
+		t.Fatalf("ResyncReason failed: %v", err)
+	}
+	if reason != "" {
+		t.Errorf("expected an existing pair to be adopted, got %q", reason)
+	}
+	if reason, _ := s.ResyncReason(adopted, "abc"); reason == "" {
+		t.Error("expected changed filters of an adopted pair to need a resync")
+	}
+
+	// A pair that never ran has no state, so its first run may resync
+	if known, _, err := s.PairState(profile); err != nil || known {
+		t.Fatalf("expected no state for a new pair, got known=%v (%v)", known, err)
+	}
+
+	// A failed resync must not store the filter hash and stays pending
+	if err := s.RecordRun(profile, "0", true, errors.New("boom")); err != nil {
+		t.Fatalf("RecordRun failed: %v", err)
+	}
+	if reason, _ := s.ResyncReason(profile, "0"); reason == "" {
+		t.Error("expected failed resync to be retried")
+	}
+	if known, pending, _ := s.PairState(profile); !known || !pending {
+		t.Errorf("expected a failed resync to stay pending, got known=%v pending=%v", known, pending)
+	}
+
+	if err := s.RecordRun(profile, "0", true, nil); err != nil {
+		t.Fatalf("RecordRun failed: %v", err)
+	}
+	if reason, _ := s.ResyncReason(profile, "0"); reason != "" {
+		t.Errorf("expected no resync after a successful one, got %q", reason)
+	}
+	if reason, _ := s.ResyncReason(profile, "abc"); reason == "" {
+		t.Error("expected changed filters to need a resync")
+	}
+	if known, pending, _ := s.PairState(profile); !known || pending {
+		t.Errorf("expected a synced pair without a pending resync, got known=%v pending=%v", known, pending)
+	}
+
+	if err := s.ForceResync(context.Background(), profile.From, profile.To); err != nil {
+		t.Fatalf("ForceResync failed: %v", err)
+	}
+	if reason, _ := s.ResyncReason(profile, "0"); reason == "" {
+		t.Error("expected forced resync")
+	}
+
+	states, err := s.loadStates(profile.From, profile.To)
+	if err != nil || len(states) != 1 {
+		t.Fatalf("expected 1 state, got %d (%v)", len(states), err)
+	}
+	st := states[0]
+	if st.ProfileName != "docs" || st.LastSuccessAt == nil || st.LastResyncAt == nil || st.LastError != "" {
+		t.Errorf("unexpected state: %+v", st)
+	}
+}
+
+func TestBisyncStateService_MigrateResyncFile(t *testing.T) {
+	s := newTestBisyncStateService(t)
+
+	path := filepath.Join(t.TempDir(), "resync")
+	if err := os.WriteFile(path, []byte("/a|remote:b|123\n/c|remote:d|0"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s.envConfig.ResyncFilePath = path
+
+	db, _ := GetSharedDB()
+	s.migrateResyncFile(db)
+
+	states, err := s.loadStates("", "")
+	if err != nil {
+		t.Fatalf("loadStates failed: %v", err)
+	}
+	if len(states) != 2 {
+		t.Fatalf("expected 2 migrated pairs, got %d", len(states))
+	}
+	if states[0].From != "/a" || states[0].To != "remote:b" || states[0].FilterHash != "123" {
+		t.Errorf("unexpected migrated state: %+v", states[0])
+	}
+}
+
+func TestBisyncStateService_ConflictInbox(t *testing.T) {
+	s := newTestBisyncStateService(t)
+	db, _ := GetSharedDB()
+	db.Exec("DELETE FROM bisync_conflicts")
+	ctx := context.Background()
+
+	profile := models.Profile{Name: "docs", From: "/src", To: "remote:dst"}
+	err := s.RecordConflicts(profile, []models.BisyncConflict{{
+		ProfileName: "docs",
+		From:        profile.From,
+		To:          profile.To,
+		Path:        "a.txt",
+		Winner:      "destination",
+		Source:      models.ConflictVersion{Path: "a.txt.conflict1", Size: 3},
+		Destination: models.ConflictVersion{Path: "a.txt", Size: 5},
+		Status:      "open",
+	}})
+	if err != nil {
+		t.Fatalf("RecordConflicts failed: %v", err)
+	}
+
+	open, err := s.GetConflicts(ctx, "open")
+	if err != nil {
+		t.Fatalf("GetConflicts failed: %v", err)
+	}
+	if len(open) != 1 || open[0].Id == "" || open[0].Source.Path != "a.txt.conflict1" || open[0].Destination.Size != 5 {
+		t.Fatalf("unexpected open conflicts: %+v", open)
+	}
+
+	// Keeping both versions changes no files, only the inbox
+	if err := s.ResolveConflict(ctx, open[0].Id, models.ConflictKeepBoth); err != nil {
+		t.Fatalf("ResolveConflict failed: %v", err)
+	}
+	c, err := s.GetConflict(ctx, open[0].Id)
+	if err != nil {
+		t.Fatalf("GetConflict failed: %v", err)
+	}
+	if c.Status != "resolved" || c.Resolution != models.ConflictKeepBoth || c.ResolvedAt == nil {
+		t.Errorf("unexpected resolved conflict: %+v", c)
+	}
+	if err := s.ResolveConflict(ctx, c.Id, models.ConflictKeepSource); err == nil {
+		t.Error("expected resolving twice to fail")
+	}
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"desktop/backend/events"
 	"desktop/backend/models"
+	"desktop/backend/rclone"
+	"desktop/backend/utils"
 	"encoding/json"
 	"fmt"
 	"log"
 	"sync"
 	"time"
 
+	"github.com/robfig/cron/v3"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
@@ -24,6 +27,7 @@ type BoardService struct {
 	// Dependencies
 	syncService         *SyncService
 	notificationService *NotificationService
+	schedulerService    *SchedulerService
 
 	// Active executions
 	activeFlows map[string]*FlowExecution
@@ -51,8 +55,11 @@ type FlowExecution struct {
 	BoardId      string
 	Cancel       context.CancelFunc
 	Status       *models.BoardExecutionStatus
-	StatusMu     sync.Mutex  // protects Status field from concurrent access
-	CleanupTimer *time.Timer // delayed cleanup timer; nil while running
+	StatusMu     sync.Mutex        // protects Status field from concurrent access
+	CleanupTimer *time.Timer       // delayed cleanup timer; nil while running
+	Pause        *rclone.PauseGate // holds edges from starting while paused
+	RunId        string            // persisted run, resumed if the app quits first
+	Done         chan struct{}     // closed when the execution finishes
 }
 
 // NewBoardService creates a new board service
@@ -94,6 +101,11 @@ func (b *BoardService) SetNotificationService(notificationService *NotificationS
 	b.notificationService = notificationService
 }
 
+// SetSchedulerService sets the scheduler that runs boards on their cron schedule
+func (b *BoardService) SetSchedulerService(schedulerService *SchedulerService) {
+	b.schedulerService = schedulerService
+}
+
 // ServiceName returns the name of the service
 func (b *BoardService) ServiceName() string {
 	return "BoardService"
@@ -224,11 +236,13 @@ func (b *BoardService) AddBoard(ctx context.Context, board models.Board) error {
 		board.CreatedAt = time.Now()
 	}
 	board.UpdatedAt = time.Now()
+	board.NextRun = b.scheduleBoard(board)
 
 	// Save to database
 	log.Printf("[BoardService] AddBoard: saving to DB...")
 	if err := b.saveBoardToDB(board); err != nil {
 		log.Printf("[BoardService] AddBoard: saveBoardToDB failed: %v", err)
+		b.unscheduleBoard(board.Id)
 		return fmt.Errorf("failed to save board: %w", err)
 	}
 	log.Printf("[BoardService] AddBoard: saved to DB successfully")
@@ -266,6 +280,10 @@ func (b *BoardService) UpdateBoard(ctx context.Context, board models.Board) erro
 			oldBoard = existing
 			board.UpdatedAt = time.Now()
 			board.CreatedAt = existing.CreatedAt
+			// The last run is kept by the scheduler, not the editor
+			board.LastRun = existing.LastRun
+			board.LastResult = existing.LastResult
+			board.NextRun = b.scheduleBoard(board)
 			b.boards[i] = board
 			found = true
 			break
@@ -281,6 +299,7 @@ func (b *BoardService) UpdateBoard(ctx context.Context, board models.Board) erro
 		for i, existing := range b.boards {
 			if existing.Id == board.Id {
 				b.boards[i] = oldBoard
+				b.scheduleBoard(oldBoard)
 				break
 			}
 		}
@@ -324,6 +343,7 @@ func (b *BoardService) DeleteBoard(ctx context.Context, boardId string) error {
 		b.boards = append(b.boards, deletedBoard)
 		return fmt.Errorf("failed to delete board: %w", err)
 	}
+	b.unscheduleBoard(boardId)
 
 	b.emitBoardEvent(events.BoardUpdated, boardId, "", "deleted", "Board deleted")
 
@@ -337,6 +357,15 @@ func (b *BoardService) DeleteBoard(ctx context.Context, boardId string) error {
 
 // ExecuteBoard starts executing a board flow
 func (b *BoardService) ExecuteBoard(ctx context.Context, boardId string) (*models.BoardExecutionStatus, error) {
+	flow, err := b.startFlow(ctx, boardId)
+	if err != nil {
+		return nil, err
+	}
+	return flow.Status, nil
+}
+
+// startFlow starts executing a board flow and returns the execution
+func (b *BoardService) startFlow(ctx context.Context, boardId string) (*FlowExecution, error) {
 	log.Printf("[BoardService] ExecuteBoard called: boardId=%s", boardId)
 
 	if err := b.ensureInitialized(); err != nil {
@@ -355,7 +384,7 @@ func (b *BoardService) ExecuteBoard(ctx context.Context, boardId string) (*model
 		existing.StatusMu.Lock()
 		status := existing.Status.Status
 		existing.StatusMu.Unlock()
-		if status == "running" {
+		if status == "running" || status == "paused" {
 			b.flowMutex.Unlock()
 			return nil, fmt.Errorf("board '%s' is already executing", boardId)
 		}
@@ -417,25 +446,35 @@ func (b *BoardService) ExecuteBoard(ctx context.Context, boardId string) (*model
 	}
 
 	// Create cancellable context from Background (not from the Wails RPC context,
-	// which gets cancelled when the method call returns)
-	flowCtx, cancel := context.WithCancel(context.Background())
+	// which gets cancelled when the method call returns), keeping the caller's queue
+	// priority for the edge syncs
+	flowCtx, cancel := context.WithCancel(utils.WithTaskPriority(context.Background(), utils.TaskPriorityFromContext(ctx)))
 
 	flow := &FlowExecution{
 		BoardId: boardId,
 		Cancel:  cancel,
 		Status:  status,
+		Pause:   rclone.NewPauseGate(),
+		RunId:   recordTaskRun(models.TaskRun{Kind: "board", Profile: models.Profile{Name: board.Name}, BoardId: boardId}),
+		Done:    make(chan struct{}),
 	}
 
 	b.flowMutex.Lock()
 	b.activeFlows[boardId] = flow
 	b.flowMutex.Unlock()
 
+	// Every run is recorded, whether started by hand, by its schedule or on recovery
+	b.updateBoardRun(boardId, func(board *models.Board) {
+		board.LastRun = &status.StartTime
+		board.LastResult = "running"
+	})
+
 	b.emitBoardEvent(events.BoardExecutionStarted, boardId, "", "running", "Board execution started")
 
 	// Execute in goroutine
 	go b.executeFlow(flowCtx, board, layers, flow)
 
-	return status, nil
+	return flow, nil
 }
 
 // StopBoardExecution cancels a running board execution
@@ -458,6 +497,75 @@ func (b *BoardService) StopBoardExecution(ctx context.Context, boardId string) e
 	return nil
 }
 
+// PauseBoardExecution pauses a running board execution: its running edge syncs are
+// paused and no further edge starts until it is resumed
+func (b *BoardService) PauseBoardExecution(ctx context.Context, boardId string) error {
+	b.flowMutex.RLock()
+	flow, exists := b.activeFlows[boardId]
+	b.flowMutex.RUnlock()
+	if !exists {
+		return fmt.Errorf("no active execution for board '%s'", boardId)
+	}
+
+	// Pause under StatusMu so executeEdge either sees the pause or records its task first
+	flow.StatusMu.Lock()
+	if flow.Status.Status != "running" || !flow.Pause.Pause() {
+		flow.StatusMu.Unlock()
+		return fmt.Errorf("board '%s' is not running", boardId)
+	}
+	flow.Status.Status = "paused"
+	taskIds := runningEdgeTasks(flow.Status)
+	flow.StatusMu.Unlock()
+
+	for _, taskId := range taskIds {
+		if err := b.syncService.PauseSync(ctx, taskId); err != nil {
+			log.Printf("[BoardService] PauseBoardExecution: failed to pause task %d: %v", taskId, err)
+		}
+	}
+
+	b.emitBoardEvent(events.BoardExecutionPaused, boardId, "", "paused", "Board execution paused")
+	return nil
+}
+
+// ResumeBoardExecution resumes a paused board execution
+func (b *BoardService) ResumeBoardExecution(ctx context.Context, boardId string) error {
+	b.flowMutex.RLock()
+	flow, exists := b.activeFlows[boardId]
+	b.flowMutex.RUnlock()
+	if !exists {
+		return fmt.Errorf("no active execution for board '%s'", boardId)
+	}
+
+	flow.StatusMu.Lock()
+	if flow.Status.Status != "paused" || !flow.Pause.Resume() {
+		flow.StatusMu.Unlock()
+		return fmt.Errorf("board '%s' is not paused", boardId)
+	}
+	flow.Status.Status = "running"
+	taskIds := runningEdgeTasks(flow.Status)
+	flow.StatusMu.Unlock()
+
+	for _, taskId := range taskIds {
+		if err := b.syncService.ResumeSync(ctx, taskId); err != nil {
+			log.Printf("[BoardService] ResumeBoardExecution: failed to resume task %d: %v", taskId, err)
+		}
+	}
+
+	b.emitBoardEvent(events.BoardExecutionResumed, boardId, "", "running", "Board execution resumed")
+	return nil
+}
+
+// runningEdgeTasks returns the sync task IDs of the running edges (call with StatusMu held)
+func runningEdgeTasks(status *models.BoardExecutionStatus) []int {
+	var taskIds []int
+	for _, es := range status.EdgeStatuses {
+		if es.Status == "running" && es.TaskId != 0 {
+			taskIds = append(taskIds, es.TaskId)
+		}
+	}
+	return taskIds
+}
+
 // GetBoardExecutionStatus returns the current execution status of a board
 func (b *BoardService) GetBoardExecutionStatus(ctx context.Context, boardId string) (*models.BoardExecutionStatus, error) {
 	b.flowMutex.RLock()
@@ -474,6 +582,77 @@ func (b *BoardService) GetBoardExecutionStatus(ctx context.Context, boardId stri
 	return &status, nil
 }
 
+// isFlowRunning reports whether the board has an execution that is running or paused
+func (b *BoardService) isFlowRunning(boardId string) bool {
+	b.flowMutex.Lock()
+	flow, exists := b.activeFlows[boardId]
+	b.flowMutex.Unlock()
+	if !exists {
+		return false
+	}
+	flow.StatusMu.Lock()
+	defer flow.StatusMu.Unlock()
+	return flow.Status.Status == "running" || flow.Status.Status == "paused"
+}
+
+// waitForFlow blocks until the execution finishes and returns its final status
+func (b *BoardService) waitForFlow(ctx context.Context, flow *FlowExecution) (string, error) {
+	select {
+	case <-flow.Done:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	flow.StatusMu.Lock()
+	defer flow.StatusMu.Unlock()
+	return flow.Status.Status, nil
+}
+
+// scheduleBoard registers the board's cron job with the scheduler and returns its next
+// run, or nil if the board isn't scheduled
+func (b *BoardService) scheduleBoard(board models.Board) *time.Time {
+	if b.schedulerService == nil {
+		return nil
+	}
+	return b.schedulerService.ScheduleBoard(board)
+}
+
+// unscheduleBoard removes the board's cron job from the scheduler
+func (b *BoardService) unscheduleBoard(boardId string) {
+	if b.schedulerService != nil {
+		b.schedulerService.UnscheduleBoard(boardId)
+	}
+}
+
+// boardRunResult maps the final status of an execution to the board's last result
+func boardRunResult(status string) string {
+	switch status {
+	case "completed":
+		return "success"
+	case "cancelled":
+		return "cancelled"
+	}
+	return "failed"
+}
+
+// updateBoardRun applies update to the board's schedule state and saves it. Boards that
+// were deleted meanwhile are ignored.
+func (b *BoardService) updateBoardRun(boardId string, update func(board *models.Board)) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for i := range b.boards {
+		if b.boards[i].Id == boardId {
+			update(&b.boards[i])
+			if err := b.saveBoardToDB(b.boards[i]); err != nil {
+				log.Printf("[BoardService] Failed to save schedule state of board %s: %v", boardId, err)
+				return
+			}
+			b.emitBoardEvent(events.BoardUpdated, boardId, "", "updated", "Board schedule updated")
+			return
+		}
+	}
+}
+
 // executeFlow runs the board execution through layers
 func (b *BoardService) executeFlow(ctx context.Context, board *models.Board, layers [][]models.BoardEdge, flow *FlowExecution) {
 	log.Printf("[BoardService] executeFlow started: boardId=%s layers=%d totalEdges=%d", board.Id, len(layers), len(board.Edges))
@@ -481,7 +660,13 @@ func (b *BoardService) executeFlow(ctx context.Context, board *models.Board, lay
 		endTime := time.Now()
 		flow.StatusMu.Lock()
 		flow.Status.EndTime = &endTime
+		result := boardRunResult(flow.Status.Status)
 		flow.StatusMu.Unlock()
+		b.updateBoardRun(board.Id, func(board *models.Board) {
+			board.LastResult = result
+		})
+		finishTaskRun(flow.RunId, ctx.Err() != nil)
+		close(flow.Done)
 		log.Printf("[BoardService] executeFlow finished: boardId=%s finalStatus=%s", board.Id, flow.Status.Status)
 		// Delay cleanup to give frontend polling time to catch the terminal status.
 		// The flow stays in activeFlows with its final status for a grace period.
@@ -584,17 +769,7 @@ func (b *BoardService) executeFlow(ctx context.Context, board *models.Board, lay
 
 // executeEdge executes a single edge sync operation
 func (b *BoardService) executeEdge(ctx context.Context, board *models.Board, edge *models.BoardEdge, flow *FlowExecution) error {
-	// Find source and target nodes
-	var sourceNode, targetNode *models.BoardNode
-	for i := range board.Nodes {
-		if board.Nodes[i].Id == edge.SourceId {
-			sourceNode = &board.Nodes[i]
-		}
-		if board.Nodes[i].Id == edge.TargetId {
-			targetNode = &board.Nodes[i]
-		}
-	}
-
+	sourceNode, targetNode := findEdgeNodes(board, edge)
 	if sourceNode == nil || targetNode == nil {
 		msg := "source or target node not found"
 		flow.StatusMu.Lock()
@@ -604,12 +779,11 @@ func (b *BoardService) executeEdge(ctx context.Context, board *models.Board, edg
 		return fmt.Errorf("%s", msg)
 	}
 
-	// Build profile from edge config with From/To from nodes
-	profile := edge.SyncConfig
-	profile.From = b.buildRemotePath(sourceNode)
-	profile.To = b.buildRemotePath(targetNode)
-	if profile.Name == "" {
-		profile.Name = fmt.Sprintf("%s->%s", sourceNode.Label, targetNode.Label)
+	profile := b.buildEdgeProfile(edge, sourceNode, targetNode)
+
+	// Don't start new edges while the board is paused
+	if err := flow.Pause.Wait(ctx); err != nil {
+		return err
 	}
 
 	log.Printf("[BoardService] executeEdge: action=%s from=%s to=%s", edge.Action, profile.From, profile.To)
@@ -637,6 +811,22 @@ func (b *BoardService) executeEdge(ctx context.Context, board *models.Board, edg
 		return err
 	}
 
+	// Record the task so pausing the board pauses it; pause it now if the board was
+	// paused while it started
+	flow.StatusMu.Lock()
+	for i := range flow.Status.EdgeStatuses {
+		if flow.Status.EdgeStatuses[i].EdgeId == edge.Id {
+			flow.Status.EdgeStatuses[i].TaskId = result.TaskId
+		}
+	}
+	paused := flow.Pause.Paused()
+	flow.StatusMu.Unlock()
+	if paused {
+		if err := b.syncService.PauseSync(ctx, result.TaskId); err != nil {
+			log.Printf("[BoardService] executeEdge: failed to pause task %d: %v", result.TaskId, err)
+		}
+	}
+
 	// Wait for task completion
 	log.Printf("[BoardService] executeEdge: waiting for task %d to complete", result.TaskId)
 	err = b.syncService.WaitForTask(ctx, result.TaskId)
@@ -661,15 +851,45 @@ func (b *BoardService) executeEdge(ctx context.Context, board *models.Board, edg
 	return nil
 }
 
+// findEdgeNodes returns the source and target nodes of an edge (nil if missing)
+func findEdgeNodes(board *models.Board, edge *models.BoardEdge) (*models.BoardNode, *models.BoardNode) {
+	var sourceNode, targetNode *models.BoardNode
+	for i := range board.Nodes {
+		if board.Nodes[i].Id == edge.SourceId {
+			sourceNode = &board.Nodes[i]
+		}
+		if board.Nodes[i].Id == edge.TargetId {
+			targetNode = &board.Nodes[i]
+		}
+	}
+	return sourceNode, targetNode
+}
+
+// buildEdgeProfile builds the profile for an edge from its sync config with From/To from nodes
+func (b *BoardService) buildEdgeProfile(edge *models.BoardEdge, sourceNode, targetNode *models.BoardNode) models.Profile {
+	profile := edge.SyncConfig
+	profile.From = b.buildRemotePath(sourceNode)
+	profile.To = b.buildRemotePath(targetNode)
+	if profile.Name == "" {
+		profile.Name = fmt.Sprintf("%s->%s", sourceNode.Label, targetNode.Label)
+	}
+	return profile
+}
+
 // buildRemotePath constructs rclone path from a board node
 func (b *BoardService) buildRemotePath(node *models.BoardNode) string {
-	if node.RemoteName == "local" || node.RemoteName == "" {
-		return node.Path
+	return remotePath(node.RemoteName, node.Path)
+}
+
+// remotePath constructs an rclone path from a remote name and a path on it
+func remotePath(remoteName, path string) string {
+	if remoteName == "local" || remoteName == "" {
+		return path
 	}
-	if node.Path == "" {
-		return node.RemoteName + ":"
+	if path == "" {
+		return remoteName + ":"
 	}
-	return node.RemoteName + ":" + node.Path
+	return remoteName + ":" + path
 }
 
 // computeExecutionLayers groups edges into execution layers using topological sort
@@ -831,14 +1051,17 @@ func (b *BoardService) validateBoard(board *models.Board) error {
 		}
 
 		// Validate action
-		switch edge.Action {
-		case "pull", "push", "bi", "bi-resync":
-			// valid
-		default:
+		if !IsValidSyncAction(edge.Action) {
 			return fmt.Errorf("edge '%s' has invalid action '%s'", edge.Id, edge.Action)
 		}
 	}
 
+	if board.ScheduleEnabled {
+		if _, err := cron.ParseStandard(board.CronExpr); err != nil {
+			return fmt.Errorf("invalid cron expression %q: %w", board.CronExpr, err)
+		}
+	}
+
 	// Check for cycles
 	return b.detectCycles(board)
 }
@@ -985,6 +1208,15 @@ func (b *BoardService) migrateFromProfiles() {
 	log.Printf("Migrated %d profiles to boards", len(profiles))
 }
 
+// renameRemotePath points an rclone path on remote oldName at remote newName instead,
+// reporting whether it changed
+func renameRemotePath(path, oldName, newName string) (string, bool) {
+	if oldName == "" || parseRemoteName(path) != oldName {
+		return path, false
+	}
+	return newName + path[len(oldName):], true
+}
+
 // parseRemoteName extracts the remote name from an rclone path (e.g., "gdrive:/path" -> "gdrive")
 func parseRemoteName(path string) string {
 	for i, c := range path {
@@ -1326,3 +1558,55 @@ func (b *BoardService) OnRemoteDeleted(remoteName string) error {
 
 	return nil
 }
+
+// OnRemoteRenamed points nodes (and edge sync configs) on remote oldName at newName
+func (b *BoardService) OnRemoteRenamed(oldName, newName string) error {
+	if err := b.ensureInitialized(); err != nil {
+		return err
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	modified := false
+	for i := range b.boards {
+		board := &b.boards[i]
+		changed := false
+
+		for j := range board.Nodes {
+			node := &board.Nodes[j]
+			if node.RemoteName != oldName {
+				continue
+			}
+			node.RemoteName = newName
+			if node.Label == oldName {
+				node.Label = newName
+			}
+			changed = true
+		}
+		for j := range board.Edges {
+			cfg := &board.Edges[j].SyncConfig
+			var fromChanged, toChanged bool
+			cfg.From, fromChanged = renameRemotePath(cfg.From, oldName, newName)
+			cfg.To, toChanged = renameRemotePath(cfg.To, oldName, newName)
+			changed = changed || fromChanged || toChanged
+		}
+
+		if !changed {
+			continue
+		}
+		board.UpdatedAt = time.Now()
+		modified = true
+
+		if err := b.saveBoardToDB(*board); err != nil {
+			return fmt.Errorf("failed to save board '%s' after remote rename: %w", board.Name, err)
+		}
+		log.Printf("Board '%s': remote '%s' renamed to '%s'", board.Name, oldName, newName)
+	}
+
+	if modified {
+		b.emitBoardEvent(events.BoardUpdated, "", "", "remote_renamed", fmt.Sprintf("Boards updated: remote '%s' was renamed to '%s'", oldName, newName))
+	}
+
+	return nil
+}
@@ -675,6 +675,57 @@ func TestParseRemoteName(t *testing.T) {
 	}
 }
 
+func TestRenameRemotePath(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		changed  bool
+	}{
+		{"vault:backups/2026", "vault2:backups/2026", true},
+		{"vault:", "vault2:", true},
+		{"vaulted:backups", "vaulted:backups", false},
+		{"/local/vault:x", "/local/vault:x", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, changed := renameRemotePath(tt.input, "vault", "vault2")
+		if got != tt.expected || changed != tt.changed {
+			t.Errorf("renameRemotePath(%q) = %q, %v, want %q, %v", tt.input, got, changed, tt.expected, tt.changed)
+		}
+	}
+}
+
+func TestBoardService_OnRemoteRenamed(t *testing.T) {
+	s := newTestBoardService(t)
+	ctx := context.Background()
+
+	board := makeTestBoard("board-rename", "Rename Board")
+	board.Nodes[1].Label = "remote2"
+	board.Edges[0].SyncConfig = models.Profile{From: "remote1:/data", To: "remote2:/backup"}
+	if err := s.AddBoard(ctx, board); err != nil {
+		t.Fatalf("AddBoard failed: %v", err)
+	}
+
+	if err := s.OnRemoteRenamed("remote2", "vault"); err != nil {
+		t.Fatalf("OnRemoteRenamed failed: %v", err)
+	}
+
+	got, err := s.GetBoard(ctx, "board-rename")
+	if err != nil {
+		t.Fatalf("GetBoard failed: %v", err)
+	}
+	if got.Nodes[0].RemoteName != "remote1" {
+		t.Errorf("expected untouched node, got %q", got.Nodes[0].RemoteName)
+	}
+	if got.Nodes[1].RemoteName != "vault" || got.Nodes[1].Label != "vault" {
+		t.Errorf("expected renamed node, got %+v", got.Nodes[1])
+	}
+	if got.Edges[0].SyncConfig.To != "vault:/backup" || got.Edges[0].SyncConfig.From != "remote1:/data" {
+		t.Errorf("unexpected edge sync config: %+v", got.Edges[0].SyncConfig)
+	}
+}
+
 func TestParseRemotePath(t *testing.T) {
 	tests := []struct {
 		input    string
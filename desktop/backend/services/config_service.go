@@ -5,6 +5,7 @@ import (
 	"desktop/backend/config"
 	"desktop/backend/events"
 	"desktop/backend/models"
+	"desktop/backend/rclone"
 	"desktop/backend/validation"
 	"fmt"
 	"log"
@@ -271,6 +272,56 @@ func (c *ConfigService) DeleteProfile(ctx context.Context, profileName string) e
 	return nil
 }
 
+// OnRemoteRenamed points the source and destination of profiles on remote oldName at
+// newName, returning the number of profiles changed
+func (c *ConfigService) OnRemoteRenamed(ctx context.Context, oldName, newName string) (int, error) {
+	c.mutex.RLock()
+	initialized := c.initialized
+	c.mutex.RUnlock()
+
+	if !initialized {
+		if err := c.initializeConfig(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var updated []models.Profile
+	for i, profile := range c.configInfo.Profiles {
+		from, fromChanged := renameRemotePath(profile.From, oldName, newName)
+		to, toChanged := renameRemotePath(profile.To, oldName, newName)
+		if !fromChanged && !toChanged {
+			continue
+		}
+		profile.From, profile.To = from, to
+		if err := c.saveProfileToDB(profile); err != nil {
+			return len(updated), fmt.Errorf("failed to save profile '%s': %w", profile.Name, err)
+		}
+		c.configInfo.Profiles[i] = profile
+		updated = append(updated, profile)
+	}
+
+	for _, profile := range updated {
+		c.emitConfigEvent(events.ProfileUpdated, profile.Name, profile)
+	}
+	if len(updated) > 0 {
+		log.Printf("Remote '%s' renamed to '%s' in %d profiles", oldName, newName, len(updated))
+	}
+	return len(updated), nil
+}
+
+// GetProfileWarnings reports settings of a profile that won't work as expected between
+// its remotes, e.g. checksum comparison when they share no hash type. Results of
+// RemoteService.ProbeRemote refine the remotes' declared capabilities.
+func (c *ConfigService) GetProfileWarnings(ctx context.Context, profile models.Profile) ([]string, error) {
+	if err := c.validateProfile(profile); err != nil {
+		return nil, err
+	}
+	return rclone.ProfileWarnings(ctx, profile)
+}
+
 // validateProfile validates a profile using the comprehensive validator
 func (c *ConfigService) validateProfile(profile models.Profile) error {
 	return c.validator.ValidateProfile(profile)
@@ -286,8 +337,9 @@ func (c *ConfigService) saveProfileToDB(p models.Profile) error {
 	_, err = db.Exec(`INSERT OR REPLACE INTO profiles (name, from_path, to_path, included_paths, excluded_paths,
 		bandwidth, parallel, backup_path, cache_path, min_size, max_size, filter_from_file,
 		exclude_if_present, use_regex, max_delete, immutable, conflict_resolution,
-		multi_thread_streams, buffer_size, fast_list, retries, low_level_retries, max_duration)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		multi_thread_streams, buffer_size, fast_list, retries, low_level_retries, max_duration,
+		bandwidth_schedule)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		p.Name, p.From, p.To,
 		marshalStringSlice(p.IncludedPaths), marshalStringSlice(p.ExcludedPaths),
 		p.Bandwidth, p.Parallel, p.BackupPath, p.CachePath,
@@ -295,7 +347,8 @@ func (c *ConfigService) saveProfileToDB(p models.Profile) error {
 		boolToInt(p.UseRegex), intPtrToNullable(p.MaxDelete), boolToInt(p.Immutable),
 		p.ConflictResolution, intPtrToNullable(p.MultiThreadStreams),
 		p.BufferSize, boolToInt(p.FastList),
-		intPtrToNullable(p.Retries), intPtrToNullable(p.LowLevelRetries), p.MaxDuration)
+		intPtrToNullable(p.Retries), intPtrToNullable(p.LowLevelRetries), p.MaxDuration,
+		p.BandwidthSchedule)
 	return err
 }
 
@@ -319,7 +372,8 @@ func (c *ConfigService) loadProfilesFromDB() ([]models.Profile, error) {
 	rows, err := db.Query(`SELECT name, from_path, to_path, included_paths, excluded_paths,
 		bandwidth, parallel, backup_path, cache_path, min_size, max_size, filter_from_file,
 		exclude_if_present, use_regex, max_delete, immutable, conflict_resolution,
-		multi_thread_streams, buffer_size, fast_list, retries, low_level_retries, max_duration
+		multi_thread_streams, buffer_size, fast_list, retries, low_level_retries, max_duration,
+		bandwidth_schedule
 		FROM profiles ORDER BY name`)
 	if err != nil {
 		return nil, err
@@ -338,7 +392,8 @@ func (c *ConfigService) loadProfilesFromDB() ([]models.Profile, error) {
 			&p.MinSize, &p.MaxSize, &p.FilterFromFile, &p.ExcludeIfPresent,
 			&useRegex, &maxDelete, &immutable, &p.ConflictResolution,
 			&multiThreadStreams, &p.BufferSize, &fastList,
-			&retries, &lowLevelRetries, &p.MaxDuration); err != nil {
+			&retries, &lowLevelRetries, &p.MaxDuration,
+			&p.BandwidthSchedule); err != nil {
 			return nil, fmt.Errorf("failed to scan profile: %w", err)
 		}
 
@@ -3,10 +3,15 @@ package services
 import (
 	"context"
 	"desktop/backend/events"
+	"desktop/backend/models"
+	"desktop/backend/rclone"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/rclone/rclone/fs/cache"
 	"github.com/rclone/rclone/fs/config"
 	"github.com/rclone/rclone/fs/config/obscure"
 	"github.com/rclone/rclone/fs/rc"
@@ -23,12 +28,35 @@ type CryptRemoteConfig struct {
 	DirectoryEncrypt bool   `json:"directory_encrypt"` // Encrypt directory names
 }
 
+// CryptRotationConfig describes a key rotation of an existing crypt remote. The content
+// is re-encrypted into WrappedRemote with the new keys; the old data is left in place.
+type CryptRotationConfig struct {
+	Name             string `json:"name"`              // Crypt remote to rotate
+	NewName          string `json:"new_name"`          // Name of the rotated remote; empty keeps Name
+	WrappedRemote    string `json:"wrapped_remote"`    // New location for the re-encrypted data
+	Password         string `json:"password"`          // New encryption password
+	Password2        string `json:"password2"`         // New salt password (optional)
+	FilenameEncrypt  string `json:"filename_encrypt"`  // "standard", "obfuscate", "off"
+	DirectoryEncrypt bool   `json:"directory_encrypt"` // Encrypt directory names
+}
+
+// CryptRotationResult describes a finished key rotation
+type CryptRotationResult struct {
+	Name            string              `json:"name"`             // Remote now using the new keys
+	RetiredName     string              `json:"retired_name"`     // Remote still reading the old data with the old keys
+	WrappedRemote   string              `json:"wrapped_remote"`   // Location of the re-encrypted data
+	ProfilesUpdated int                 `json:"profiles_updated"` // Profiles pointed at the new name
+	Verification    *models.CheckReport `json:"verification"`     // Comparison of the old and new remote
+}
+
 // CryptService manages crypt (encryption) remotes and config encryption
 type CryptService struct {
-	app         *application.App
-	eventBus    *events.WailsEventBus
-	mutex       sync.RWMutex
-	initialized bool
+	app              *application.App
+	eventBus         *events.WailsEventBus
+	configService    *ConfigService
+	operationService *OperationService
+	mutex            sync.RWMutex
+	initialized      bool
 }
 
 // NewCryptService creates a new crypt service
@@ -48,6 +76,16 @@ func (c *CryptService) SetApp(app *application.App) {
 	}
 }
 
+// SetConfigService sets the config service whose profiles follow renamed remotes
+func (c *CryptService) SetConfigService(configService *ConfigService) {
+	c.configService = configService
+}
+
+// SetOperationService sets the operation service that runs re-encryption copies
+func (c *CryptService) SetOperationService(operationService *OperationService) {
+	c.operationService = operationService
+}
+
 // ServiceName returns the name of the service
 func (c *CryptService) ServiceName() string {
 	return "CryptService"
@@ -102,6 +140,17 @@ func (c *CryptService) CreateCryptRemote(ctx context.Context, cfg CryptRemoteCon
 		}
 	}
 
+	if err := createCryptRemote(ctx, cfg); err != nil {
+		return err
+	}
+
+	c.emitCryptEvent(events.CryptRemoteCreated, cfg.Name, cfg)
+	log.Printf("Crypt remote '%s' created wrapping '%s'", cfg.Name, cfg.WrappedRemote)
+	return nil
+}
+
+// createCryptRemote writes a crypt section into the rclone config
+func createCryptRemote(ctx context.Context, cfg CryptRemoteConfig) error {
 	// Obscure password for storage
 	obscuredPassword, err := obscure.Obscure(cfg.Password)
 	if err != nil {
@@ -142,12 +191,175 @@ func (c *CryptService) CreateCryptRemote(ctx context.Context, cfg CryptRemoteCon
 	if err != nil {
 		return fmt.Errorf("failed to create crypt remote: %w", err)
 	}
+	return nil
+}
 
-	c.emitCryptEvent(events.CryptRemoteCreated, cfg.Name, cfg)
-	log.Printf("Crypt remote '%s' created wrapping '%s'", cfg.Name, cfg.WrappedRemote)
+// RotateCryptRemote changes the keys (or filename encryption) of a crypt remote. A
+// staging crypt remote with the new settings is created over cfg.WrappedRemote, the
+// content is copied into it with progress reported under tabId, and the copy is
+// verified. Only then is the config swapped in a single save:
+//   - without NewName, the old remote is kept as "<name>-retired-<date>" (numbered when
+//     that name is taken) and the new one takes over its name, so profiles, boards and
+//     flows need no change;
+//   - with NewName, the new remote gets that name and profiles, boards and flows
+//     referencing the old name are pointed at it. If that fails, they are pointed back
+//     at the old remote and an error is returned.
+//
+// The old data is never deleted. When the copy or verification fails, the staging
+// remote is removed and the existing remote is left untouched.
+func (c *CryptService) RotateCryptRemote(ctx context.Context, cfg CryptRotationConfig, tabId string) (*CryptRotationResult, error) {
+	if c.operationService == nil {
+		return nil, fmt.Errorf("operation service not available")
+	}
+	if cfg.WrappedRemote == "" {
+		return nil, fmt.Errorf("new wrapped remote path cannot be empty")
+	}
+	if cfg.Password == "" {
+		return nil, fmt.Errorf("new encryption password cannot be empty")
+	}
+
+	stagingName := cfg.Name + "-rotation"
+	c.mutex.Lock()
+	remoteType, _ := config.FileGetValue(cfg.Name, "type")
+	oldWrapped, _ := config.FileGetValue(cfg.Name, "remote")
+	var err error
+	switch {
+	case remoteType == "":
+		err = fmt.Errorf("remote '%s' not found", cfg.Name)
+	case remoteType != "crypt":
+		err = fmt.Errorf("remote '%s' is not a crypt remote (type: %s)", cfg.Name, remoteType)
+	case cfg.WrappedRemote == oldWrapped:
+		err = fmt.Errorf("the re-encrypted data needs a new location, '%s' is already used by '%s'", oldWrapped, cfg.Name)
+	case cfg.NewName != "" && cfg.NewName != cfg.Name && config.LoadedData().HasSection(cfg.NewName):
+		err = fmt.Errorf("remote '%s' already exists", cfg.NewName)
+	case config.LoadedData().HasSection(stagingName):
+		err = fmt.Errorf("remote '%s' already exists; a previous rotation was interrupted, delete it to start over", stagingName)
+	default:
+		err = createCryptRemote(ctx, CryptRemoteConfig{
+			Name:             stagingName,
+			WrappedRemote:    cfg.WrappedRemote,
+			Password:         cfg.Password,
+			Password2:        cfg.Password2,
+			FilenameEncrypt:  cfg.FilenameEncrypt,
+			DirectoryEncrypt: cfg.DirectoryEncrypt,
+		})
+	}
+	c.mutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	profile := models.Profile{
+		Name:     cfg.Name,
+		From:     cfg.Name + ":",
+		To:       stagingName + ":",
+		Parallel: 16,
+	}
+	report, err := c.operationService.ReEncrypt(ctx, profile, tabId)
+	if err != nil {
+		c.mutex.Lock()
+		config.DeleteRemote(stagingName)
+		c.mutex.Unlock()
+		return nil, fmt.Errorf("re-encryption of '%s' failed, the remote was left unchanged: %w", cfg.Name, err)
+	}
+
+	result := &CryptRotationResult{
+		Name:          cfg.Name,
+		RetiredName:   cfg.Name,
+		WrappedRemote: cfg.WrappedRemote,
+		Verification:  report,
+	}
+
+	c.mutex.Lock()
+	data := config.LoadedData()
+	if cfg.NewName != "" && cfg.NewName != cfg.Name {
+		result.Name = cfg.NewName
+	} else {
+		// Never reuse an existing retired section, it may hold the only copy of older keys
+		base := cfg.Name + "-retired-" + time.Now().Format("20060102")
+		result.RetiredName = base
+		for i := 2; data.HasSection(result.RetiredName); i++ {
+			result.RetiredName = fmt.Sprintf("%s-%d", base, i)
+		}
+		copyConfigSection(data, cfg.Name, result.RetiredName)
+		data.DeleteSection(cfg.Name)
+	}
+	copyConfigSection(data, stagingName, result.Name)
+	data.DeleteSection(stagingName)
+	config.SaveConfig()
+	cache.ClearConfig(cfg.Name)
+	cache.ClearConfig(stagingName)
+	c.mutex.Unlock()
+
+	if result.Name != cfg.Name {
+		if err := c.renameRemoteReferences(ctx, cfg.Name, result.Name, result); err != nil {
+			return nil, err
+		}
+	}
+
+	c.emitCryptEvent(events.CryptRemoteRotated, result.Name, result)
+	log.Printf("Crypt remote '%s' rotated into '%s' (old keys kept as '%s')", cfg.Name, result.Name, result.RetiredName)
+	return result, nil
+}
+
+// renameRemoteReferences points profiles, boards and flows at the rotated remote. If any
+// of them fails, the ones already updated are pointed back at the old remote, which still
+// reads the old data, so the swap either happens everywhere or nowhere.
+func (c *CryptService) renameRemoteReferences(ctx context.Context, oldName, newName string, result *CryptRotationResult) error {
+	type step struct {
+		what   string
+		rename func(from, to string) error
+	}
+	var steps []step
+	if c.configService != nil {
+		steps = append(steps, step{"profiles", func(from, to string) error {
+			n, err := c.configService.OnRemoteRenamed(ctx, from, to)
+			if to == newName {
+				result.ProfilesUpdated = n
+			}
+			return err
+		}})
+	}
+	if boardService := GetBoardService(); boardService != nil {
+		steps = append(steps, step{"boards", boardService.OnRemoteRenamed})
+	}
+	if flowService := GetFlowService(); flowService != nil {
+		steps = append(steps, step{"flows", func(from, to string) error {
+			return flowService.OnRemoteRenamed(ctx, from, to)
+		}})
+	}
+
+	for i, st := range steps {
+		err := st.rename(oldName, newName)
+		if err == nil {
+			continue
+		}
+		// Profiles and boards may be partly renamed, so the failed step is undone as well
+		var rollbackErrs []string
+		for j := i; j >= 0; j-- {
+			if rbErr := steps[j].rename(newName, oldName); rbErr != nil {
+				rollbackErrs = append(rollbackErrs, fmt.Sprintf("%s: %v", steps[j].what, rbErr))
+			}
+		}
+		if len(rollbackErrs) > 0 {
+			return fmt.Errorf("failed to update %s after rotating '%s' into '%s': %w; references are now split between both remotes, restoring them also failed (%s)",
+				st.what, oldName, newName, err, strings.Join(rollbackErrs, "; "))
+		}
+		return fmt.Errorf("failed to update %s after rotating '%s' into '%s': %w; references still point at '%s', the re-encrypted data is available as '%s'",
+			st.what, oldName, newName, err, oldName, newName)
+	}
 	return nil
 }
 
+// copyConfigSection copies every key of a config section into another section
+func copyConfigSection(data config.Storage, from, to string) {
+	for _, key := range data.GetKeyList(from) {
+		if value, ok := data.GetValue(from, key); ok {
+			data.SetValue(to, key, value)
+		}
+	}
+}
+
 // DeleteCryptRemote deletes a crypt remote
 func (c *CryptService) DeleteCryptRemote(ctx context.Context, name string) error {
 	c.mutex.Lock()
@@ -185,6 +397,19 @@ func (c *CryptService) ListCryptRemotes(ctx context.Context) ([]string, error) {
 	return cryptRemotes, nil
 }
 
+// DecodeNames maps encrypted object names (or paths) found on the remote underlying a
+// crypt remote back to their plaintext names
+func (c *CryptService) DecodeNames(ctx context.Context, name string, encryptedNames []string) ([]models.CryptName, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	opCtx, err := rclone.SimpleContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize rclone config: %w", err)
+	}
+	return rclone.DecodeCryptNames(opCtx, name+":", encryptedNames)
+}
+
 // emitCryptEvent emits a crypt event
 func (c *CryptService) emitCryptEvent(eventType events.EventType, remoteName string, data interface{}) {
 	event := events.NewCryptEvent(eventType, remoteName, data)
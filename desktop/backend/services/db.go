@@ -80,6 +80,15 @@ func InitDatabase() error {
 	// Add new columns to profiles table
 	migrateProfilesNewColumns(db)
 
+	// Add accounting columns to history table
+	migrateHistoryNewColumns(db)
+
+	// Add run outcome and policy columns to schedules table
+	migrateSchedulesNewColumns(db)
+
+	// Add flow execution columns to flows and task_runs tables
+	migrateFlowsNewColumns(db)
+
 	migrateFromJSON(db)
 	return nil
 }
@@ -136,7 +145,11 @@ func createAllTables(db *sql.DB) error {
 			last_run     TEXT,
 			next_run     TEXT,
 			last_result  TEXT NOT NULL DEFAULT '',
-			created_at   TEXT NOT NULL DEFAULT (datetime('now'))
+			created_at   TEXT NOT NULL DEFAULT (datetime('now')),
+			last_error   TEXT NOT NULL DEFAULT '',
+			last_duration TEXT NOT NULL DEFAULT '',
+			misfire_policy TEXT NOT NULL DEFAULT 'once',
+			overlap_policy TEXT NOT NULL DEFAULT 'skip'
 		);
 
 		-- Operation history (capped at 1000 rows)
@@ -151,10 +164,102 @@ func createAllTables(db *sql.DB) error {
 			files_transferred INTEGER NOT NULL DEFAULT 0,
 			bytes_transferred INTEGER NOT NULL DEFAULT 0,
 			errors            INTEGER NOT NULL DEFAULT 0,
-			error_message     TEXT NOT NULL DEFAULT ''
+			error_message     TEXT NOT NULL DEFAULT '',
+			checks            INTEGER NOT NULL DEFAULT 0,
+			deletes           INTEGER NOT NULL DEFAULT 0,
+			renames           INTEGER NOT NULL DEFAULT 0
 		);
 		CREATE INDEX IF NOT EXISTS idx_history_start_time ON history(start_time DESC);
 
+		CREATE TABLE IF NOT EXISTS transfer_journal (
+			id           INTEGER PRIMARY KEY AUTOINCREMENT,
+			run_id       TEXT NOT NULL,
+			profile_name TEXT NOT NULL DEFAULT '',
+			action       TEXT NOT NULL DEFAULT '',
+			path         TEXT NOT NULL DEFAULT '',
+			outcome      TEXT NOT NULL DEFAULT '',
+			size         INTEGER NOT NULL DEFAULT 0,
+			error        TEXT NOT NULL DEFAULT '',
+			timestamp    TEXT NOT NULL DEFAULT '',
+			FOREIGN KEY (run_id) REFERENCES history(id) ON DELETE CASCADE
+		);
+		CREATE INDEX IF NOT EXISTS idx_transfer_journal_run_id ON transfer_journal(run_id);
+		CREATE INDEX IF NOT EXISTS idx_transfer_journal_path ON transfer_journal(path);
+
+		-- Sync plans
+		CREATE TABLE IF NOT EXISTS sync_plans (
+			id           TEXT PRIMARY KEY,
+			profile_name TEXT NOT NULL DEFAULT '',
+			action       TEXT NOT NULL DEFAULT '',
+			profile      TEXT NOT NULL DEFAULT '{}',
+			board_id     TEXT NOT NULL DEFAULT '',
+			edge_id      TEXT NOT NULL DEFAULT '',
+			changes      TEXT NOT NULL DEFAULT '[]',
+			status       TEXT NOT NULL DEFAULT 'pending',
+			message      TEXT NOT NULL DEFAULT '',
+			created_at   TEXT NOT NULL DEFAULT (datetime('now')),
+			applied_at   TEXT
+		);
+
+		-- Remote benchmarks
+		CREATE TABLE IF NOT EXISTS benchmark_runs (
+			id         TEXT PRIMARY KEY,
+			remote     TEXT NOT NULL DEFAULT '',
+			config     TEXT NOT NULL DEFAULT '{}',
+			results    TEXT NOT NULL DEFAULT '[]',
+			status     TEXT NOT NULL DEFAULT '',
+			message    TEXT NOT NULL DEFAULT '',
+			started_at TEXT NOT NULL DEFAULT (datetime('now')),
+			duration   TEXT NOT NULL DEFAULT ''
+		);
+		CREATE INDEX IF NOT EXISTS idx_benchmark_runs_remote ON benchmark_runs(remote, started_at DESC);
+
+		-- Runs in progress, kept to report and resume interrupted work after a restart
+		CREATE TABLE IF NOT EXISTS task_runs (
+			id             TEXT PRIMARY KEY,
+			kind           TEXT NOT NULL DEFAULT '',
+			action         TEXT NOT NULL DEFAULT '',
+			profile        TEXT NOT NULL DEFAULT '{}',
+			tab_id         TEXT NOT NULL DEFAULT '',
+			board_id       TEXT NOT NULL DEFAULT '',
+			flow_id        TEXT NOT NULL DEFAULT '',
+			options        TEXT NOT NULL DEFAULT '{}',
+			status         TEXT NOT NULL DEFAULT 'running',
+			message        TEXT NOT NULL DEFAULT '',
+			started_at     TEXT NOT NULL DEFAULT (datetime('now')),
+			interrupted_at TEXT
+		);
+
+		-- Bisync pair bookkeeping
+		CREATE TABLE IF NOT EXISTS bisync_state (
+			from_path       TEXT NOT NULL,
+			to_path         TEXT NOT NULL,
+			profile_name    TEXT NOT NULL DEFAULT '',
+			filter_hash     TEXT NOT NULL DEFAULT '',
+			resync_pending  INTEGER NOT NULL DEFAULT 0,
+			last_run_at     TEXT,
+			last_success_at TEXT,
+			last_resync_at  TEXT,
+			last_error      TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (from_path, to_path)
+		);
+
+		CREATE TABLE IF NOT EXISTS bisync_conflicts (
+			id           TEXT PRIMARY KEY,
+			profile_name TEXT NOT NULL DEFAULT '',
+			from_path    TEXT NOT NULL DEFAULT '',
+			to_path      TEXT NOT NULL DEFAULT '',
+			path         TEXT NOT NULL DEFAULT '',
+			winner       TEXT NOT NULL DEFAULT 'none',
+			source       TEXT NOT NULL DEFAULT '{}',
+			destination  TEXT NOT NULL DEFAULT '{}',
+			status       TEXT NOT NULL DEFAULT 'open',
+			resolution   TEXT NOT NULL DEFAULT '',
+			detected_at  TEXT NOT NULL DEFAULT (datetime('now')),
+			resolved_at  TEXT
+		);
+		CREATE INDEX IF NOT EXISTS idx_bisync_conflicts_status ON bisync_conflicts(status);
+
 		-- Boards
 		CREATE TABLE IF NOT EXISTS boards (
 			id               TEXT PRIMARY KEY,
@@ -201,7 +306,8 @@ func createAllTables(db *sql.DB) error {
 			cron_expr        TEXT NOT NULL DEFAULT '',
 			sort_order       INTEGER NOT NULL DEFAULT 0,
 			created_at       TEXT NOT NULL DEFAULT (datetime('now')),
-			updated_at       TEXT NOT NULL DEFAULT (datetime('now'))
+			updated_at       TEXT NOT NULL DEFAULT (datetime('now')),
+			continue_on_failure INTEGER NOT NULL DEFAULT 0
 		);
 		CREATE INDEX IF NOT EXISTS idx_flows_sort_order ON flows(sort_order);
 
@@ -743,6 +849,7 @@ func migrateProfilesNewColumns(db *sql.DB) {
 		{"check_access", "INTEGER NOT NULL DEFAULT 0"},
 		{"conflict_loser", "TEXT NOT NULL DEFAULT ''"},
 		{"conflict_suffix", "TEXT NOT NULL DEFAULT ''"},
+		{"bandwidth_schedule", "TEXT NOT NULL DEFAULT ''"},
 	}
 	for _, col := range newCols {
 		// Errors are expected for columns that already exist; silently ignore
@@ -750,6 +857,43 @@ func migrateProfilesNewColumns(db *sql.DB) {
 	}
 }
 
+// migrateHistoryNewColumns adds rclone accounting columns to the history table.
+func migrateHistoryNewColumns(db *sql.DB) {
+	newCols := []struct{ name, typeDef string }{
+		{"checks", "INTEGER NOT NULL DEFAULT 0"},
+		{"deletes", "INTEGER NOT NULL DEFAULT 0"},
+		{"renames", "INTEGER NOT NULL DEFAULT 0"},
+	}
+	for _, col := range newCols {
+		// Errors are expected for columns that already exist; silently ignore
+		db.Exec(fmt.Sprintf("ALTER TABLE history ADD COLUMN %s %s", col.name, col.typeDef))
+	}
+}
+
+// migrateSchedulesNewColumns adds the last run's error and duration and the misfire and
+// overlap policies to the schedules table. Existing schedules skip missed runs, as they
+// did before; new schedules get their policy from the scheduler.
+func migrateSchedulesNewColumns(db *sql.DB) {
+	newCols := []struct{ name, typeDef string }{
+		{"last_error", "TEXT NOT NULL DEFAULT ''"},
+		{"last_duration", "TEXT NOT NULL DEFAULT ''"},
+		{"misfire_policy", "TEXT NOT NULL DEFAULT 'skip'"},
+		{"overlap_policy", "TEXT NOT NULL DEFAULT 'skip'"},
+	}
+	for _, col := range newCols {
+		// Errors are expected for columns that already exist; silently ignore
+		db.Exec(fmt.Sprintf("ALTER TABLE schedules ADD COLUMN %s %s", col.name, col.typeDef))
+	}
+}
+
+// migrateFlowsNewColumns adds the failure policy column to the flows table and the flow
+// of a run to the task_runs table.
+func migrateFlowsNewColumns(db *sql.DB) {
+	// Errors are expected for columns that already exist; silently ignore
+	db.Exec("ALTER TABLE flows ADD COLUMN continue_on_failure INTEGER NOT NULL DEFAULT 0")
+	db.Exec("ALTER TABLE task_runs ADD COLUMN flow_id TEXT NOT NULL DEFAULT ''")
+}
+
 // ============ Helpers ============
 
 func boolToStr(b bool) string {
@@ -2,21 +2,35 @@ package services
 
 import (
 	"context"
+	"desktop/backend/events"
 	"desktop/backend/models"
+	"desktop/backend/utils"
 	"encoding/json"
 	"fmt"
 	"log"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
-// FlowService manages flow persistence using the shared SQLite database
+// FlowService manages flow persistence using the shared SQLite database and runs the
+// operations of a flow in order
 type FlowService struct {
 	app         *application.App
+	eventBus    *events.WailsEventBus
 	mutex       sync.RWMutex
 	initialized bool
+
+	// Dependencies
+	syncService         *SyncService
+	notificationService *NotificationService
+	schedulerService    *SchedulerService
+
+	// Active executions
+	activeRuns map[string]*FlowRun
+	runMutex   sync.RWMutex
 }
 
 // Singleton instance for cross-service access
@@ -35,16 +49,48 @@ func SetFlowServiceInstance(fs *FlowService) {
 	})
 }
 
+// FlowRun tracks a running flow execution
+type FlowRun struct {
+	FlowId       string
+	Cancel       context.CancelFunc
+	Status       *models.FlowExecutionStatus
+	StatusMu     sync.Mutex    // protects Status field from concurrent access
+	CleanupTimer *time.Timer   // delayed cleanup timer; nil while running
+	RunId        string        // persisted run, resumed if the app quits first
+	Done         chan struct{} // closed when the execution finishes
+}
+
 // NewFlowService creates a new flow service
 func NewFlowService(app *application.App) *FlowService {
 	return &FlowService{
-		app: app,
+		app:        app,
+		activeRuns: make(map[string]*FlowRun),
 	}
 }
 
 // SetApp sets the application reference
 func (s *FlowService) SetApp(app *application.App) {
 	s.app = app
+	if bus := GetSharedEventBus(); bus != nil {
+		s.eventBus = bus
+	} else {
+		s.eventBus = events.NewEventBus(app)
+	}
+}
+
+// SetSyncService sets the sync service dependency for running operations
+func (s *FlowService) SetSyncService(syncService *SyncService) {
+	s.syncService = syncService
+}
+
+// SetNotificationService sets the notification service dependency
+func (s *FlowService) SetNotificationService(notificationService *NotificationService) {
+	s.notificationService = notificationService
+}
+
+// SetSchedulerService sets the scheduler that runs flows on their cron schedule
+func (s *FlowService) SetSchedulerService(schedulerService *SchedulerService) {
+	s.schedulerService = schedulerService
 }
 
 // ServiceName returns the name of the service
@@ -66,6 +112,17 @@ func (s *FlowService) ServiceStartup(ctx context.Context, options application.Se
 // ServiceShutdown is called when the service shuts down
 func (s *FlowService) ServiceShutdown(ctx context.Context) error {
 	log.Printf("FlowService shutting down...")
+	// Cancel all active runs and stop cleanup timers
+	s.runMutex.Lock()
+	for _, run := range s.activeRuns {
+		if run.Cancel != nil {
+			run.Cancel()
+		}
+		if run.CleanupTimer != nil {
+			run.CleanupTimer.Stop()
+		}
+	}
+	s.runMutex.Unlock()
 	return nil
 }
 
@@ -111,7 +168,7 @@ func (s *FlowService) GetFlows(ctx context.Context) ([]models.Flow, error) {
 
 	// Query flows
 	rows, err := db.Query(`
-		SELECT id, name, is_collapsed, schedule_enabled, cron_expr, sort_order, created_at, updated_at
+		SELECT id, name, is_collapsed, schedule_enabled, cron_expr, continue_on_failure, sort_order, created_at, updated_at
 		FROM flows ORDER BY sort_order
 	`)
 	if err != nil {
@@ -122,12 +179,13 @@ func (s *FlowService) GetFlows(ctx context.Context) ([]models.Flow, error) {
 	var flows []models.Flow
 	for rows.Next() {
 		var f models.Flow
-		var isCollapsed, scheduleEnabled int
-		if err := rows.Scan(&f.Id, &f.Name, &isCollapsed, &scheduleEnabled, &f.CronExpr, &f.SortOrder, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		var isCollapsed, scheduleEnabled, continueOnFailure int
+		if err := rows.Scan(&f.Id, &f.Name, &isCollapsed, &scheduleEnabled, &f.CronExpr, &continueOnFailure, &f.SortOrder, &f.CreatedAt, &f.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan flow: %w", err)
 		}
 		f.IsCollapsed = isCollapsed != 0
 		f.ScheduleEnabled = scheduleEnabled != 0
+		f.ContinueOnFailure = continueOnFailure != 0
 		f.Operations = []models.Operation{}
 		flows = append(flows, f)
 	}
@@ -181,8 +239,8 @@ func (s *FlowService) SaveFlows(ctx context.Context, flows []models.Flow) error
 
 	// Insert flows
 	flowStmt, err := tx.Prepare(`
-		INSERT INTO flows (id, name, is_collapsed, schedule_enabled, cron_expr, sort_order, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO flows (id, name, is_collapsed, schedule_enabled, cron_expr, continue_on_failure, sort_order, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to prepare flow insert: %w", err)
@@ -213,7 +271,7 @@ func (s *FlowService) SaveFlows(ctx context.Context, flows []models.Flow) error
 			createdAt = now
 		}
 
-		if _, err := flowStmt.Exec(f.Id, f.Name, isCollapsed, scheduleEnabled, f.CronExpr, i, createdAt, now); err != nil {
+		if _, err := flowStmt.Exec(f.Id, f.Name, isCollapsed, scheduleEnabled, f.CronExpr, boolToInt(f.ContinueOnFailure), i, createdAt, now); err != nil {
 			return fmt.Errorf("failed to insert flow %s: %w", f.Id, err)
 		}
 
@@ -237,6 +295,11 @@ func (s *FlowService) SaveFlows(ctx context.Context, flows []models.Flow) error
 		return err
 	}
 
+	// Register the cron jobs of scheduled flows
+	if s.schedulerService != nil {
+		s.schedulerService.ScheduleFlows(flows)
+	}
+
 	// Refresh tray menu to reflect flow changes
 	if ts := GetTrayService(); ts != nil {
 		go ts.RefreshMenu()
@@ -274,6 +337,367 @@ func (s *FlowService) OnRemoteDeleted(ctx context.Context, remoteName string) er
 	return nil
 }
 
+// OnRemoteRenamed points operations on remote oldName at newName
+func (s *FlowService) OnRemoteRenamed(ctx context.Context, oldName, newName string) error {
+	if err := s.ensureInitialized(); err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	db, err := GetSharedDB()
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		"UPDATE operations SET source_remote = ? WHERE source_remote = ?", newName, oldName,
+	); err != nil {
+		return fmt.Errorf("failed to rename source remote: %w", err)
+	}
+	if _, err := tx.Exec(
+		"UPDATE operations SET target_remote = ? WHERE target_remote = ?", newName, oldName,
+	); err != nil {
+		return fmt.Errorf("failed to rename target remote: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit remote rename: %w", err)
+	}
+
+	log.Printf("FlowService: renamed remote '%s' to '%s' in operations", oldName, newName)
+	return nil
+}
+
+// ============ Execution ============
+
+// ExecuteFlow starts running the operations of a flow in order. An operation that fails
+// stops the flow unless the flow continues on failure.
+func (s *FlowService) ExecuteFlow(ctx context.Context, flowId string) (*models.FlowExecutionStatus, error) {
+	run, err := s.startRun(ctx, flowId)
+	if err != nil {
+		return nil, err
+	}
+	return run.snapshot(), nil
+}
+
+// StopFlow cancels a running flow execution
+func (s *FlowService) StopFlow(ctx context.Context, flowId string) error {
+	s.runMutex.RLock()
+	run, exists := s.activeRuns[flowId]
+	s.runMutex.RUnlock()
+	if !exists {
+		// Execution already finished — not an error
+		return nil
+	}
+
+	run.Cancel()
+	return nil
+}
+
+// GetFlowExecutionStatus returns the status of the current or last execution of a flow
+func (s *FlowService) GetFlowExecutionStatus(ctx context.Context, flowId string) (*models.FlowExecutionStatus, error) {
+	s.runMutex.RLock()
+	run, exists := s.activeRuns[flowId]
+	s.runMutex.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("no active execution for flow '%s'", flowId)
+	}
+	return run.snapshot(), nil
+}
+
+// startRun checks the flow can run and starts executing it
+func (s *FlowService) startRun(ctx context.Context, flowId string) (*FlowRun, error) {
+	log.Printf("[FlowService] ExecuteFlow called: flowId=%s", flowId)
+
+	if s.syncService == nil {
+		return nil, fmt.Errorf("sync service not available")
+	}
+
+	flow, err := s.getFlow(ctx, flowId)
+	if err != nil {
+		return nil, err
+	}
+	if len(flow.Operations) == 0 {
+		return nil, fmt.Errorf("flow '%s' has no operations to execute", flowName(flow))
+	}
+	for _, op := range flow.Operations {
+		if op.SourceRemote == "" || op.TargetRemote == "" {
+			return nil, fmt.Errorf("operation '%s' is missing its source or target remote", op.Id)
+		}
+		if !IsValidSyncAction(op.Action) {
+			return nil, fmt.Errorf("operation '%s' has invalid action '%s'", op.Id, op.Action)
+		}
+	}
+
+	// Check if flow is already executing
+	s.runMutex.Lock()
+	defer s.runMutex.Unlock()
+	if existing, exists := s.activeRuns[flowId]; exists {
+		select {
+		case <-existing.Done:
+			// Finished: stop cleanup timer, remove stale entry
+			if existing.CleanupTimer != nil {
+				existing.CleanupTimer.Stop()
+			}
+			delete(s.activeRuns, flowId)
+		default:
+			return nil, fmt.Errorf("flow '%s' is already executing", flowName(flow))
+		}
+	}
+
+	operations := make([]models.OperationExecutionStatus, len(flow.Operations))
+	for i, op := range flow.Operations {
+		operations[i] = models.OperationExecutionStatus{
+			OperationId: op.Id,
+			Status:      "pending",
+		}
+	}
+
+	// Create cancellable context from Background (not from the Wails RPC context,
+	// which gets cancelled when the method call returns), keeping the caller's queue
+	// priority for the operation syncs
+	runCtx, cancel := context.WithCancel(utils.WithTaskPriority(context.Background(), utils.TaskPriorityFromContext(ctx)))
+
+	run := &FlowRun{
+		FlowId: flowId,
+		Cancel: cancel,
+		Status: &models.FlowExecutionStatus{
+			FlowId:     flowId,
+			Status:     "running",
+			Operations: operations,
+			StartTime:  time.Now(),
+		},
+		RunId: recordTaskRun(models.TaskRun{Kind: "flow", Profile: models.Profile{Name: flowName(flow)}, FlowId: flowId}),
+		Done:  make(chan struct{}),
+	}
+	s.activeRuns[flowId] = run
+
+	s.emitFlowEvent(events.FlowExecutionStarted, flowId, "", "running", "Flow execution started")
+
+	go s.runFlow(runCtx, flow, run)
+	return run, nil
+}
+
+// runFlow runs the operations of the flow one after another
+func (s *FlowService) runFlow(ctx context.Context, flow *models.Flow, run *FlowRun) {
+	log.Printf("[FlowService] runFlow started: flowId=%s operations=%d", flow.Id, len(flow.Operations))
+	defer func() {
+		endTime := time.Now()
+		run.StatusMu.Lock()
+		run.Status.EndTime = &endTime
+		run.StatusMu.Unlock()
+		finishTaskRun(run.RunId, ctx.Err() != nil)
+		close(run.Done)
+		log.Printf("[FlowService] runFlow finished: flowId=%s finalStatus=%s", flow.Id, run.Status.Status)
+		// Keep the final status for a grace period so pollers can catch it
+		s.runMutex.Lock()
+		run.CleanupTimer = time.AfterFunc(10*time.Second, func() {
+			s.runMutex.Lock()
+			if s.activeRuns[flow.Id] == run {
+				delete(s.activeRuns, flow.Id)
+			}
+			s.runMutex.Unlock()
+		})
+		s.runMutex.Unlock()
+	}()
+
+	failed := false
+	for _, op := range flow.Operations {
+		if ctx.Err() != nil {
+			s.setOperationStatus(run, op.Id, "cancelled", "Flow execution cancelled")
+			continue
+		}
+		if failed && !flow.ContinueOnFailure {
+			s.setOperationStatus(run, op.Id, "skipped", "Skipped: an earlier operation failed")
+			continue
+		}
+		if err := s.executeOperation(ctx, flow, &op, run); err != nil && ctx.Err() == nil {
+			failed = true
+		}
+	}
+
+	status := "completed"
+	switch {
+	case ctx.Err() != nil:
+		status = "cancelled"
+	case failed:
+		status = "failed"
+	}
+	run.StatusMu.Lock()
+	run.Status.Status = status
+	run.StatusMu.Unlock()
+
+	switch status {
+	case "cancelled":
+		s.emitFlowEvent(events.FlowExecutionCancelled, flow.Id, "", status, "Flow execution cancelled")
+	case "failed":
+		s.emitFlowEvent(events.FlowExecutionFailed, flow.Id, "", status, "Flow execution completed with failures")
+		s.sendFlowNotification(flow, run)
+	default:
+		s.emitFlowEvent(events.FlowExecutionCompleted, flow.Id, "", status, "Flow execution completed successfully")
+		s.sendFlowNotification(flow, run)
+	}
+}
+
+// executeOperation runs one operation of the flow and waits for it to finish
+func (s *FlowService) executeOperation(ctx context.Context, flow *models.Flow, op *models.Operation, run *FlowRun) error {
+	profile := op.SyncConfig
+	profile.From = remotePath(op.SourceRemote, op.SourcePath)
+	profile.To = remotePath(op.TargetRemote, op.TargetPath)
+	if profile.Name == "" {
+		profile.Name = fmt.Sprintf("%s->%s", op.SourceRemote, op.TargetRemote)
+	}
+
+	log.Printf("[FlowService] executeOperation: action=%s from=%s to=%s", op.Action, profile.From, profile.To)
+	s.setOperationStatus(run, op.Id, "running", fmt.Sprintf("Syncing %s -> %s", profile.From, profile.To))
+
+	tabId := fmt.Sprintf("flow-%s-%s", flow.Id, op.Id)
+	result, err := s.syncService.StartSync(ctx, op.Action, profile, tabId)
+	if err != nil {
+		s.setOperationStatus(run, op.Id, "failed", fmt.Sprintf("Failed to start sync: %v", err))
+		return err
+	}
+
+	run.StatusMu.Lock()
+	for i := range run.Status.Operations {
+		if run.Status.Operations[i].OperationId == op.Id {
+			run.Status.Operations[i].TaskId = result.TaskId
+		}
+	}
+	run.StatusMu.Unlock()
+
+	// Stopping the flow cancels the sync, so wait for its real outcome
+	err = s.syncService.WaitForTask(context.Background(), result.TaskId)
+	switch {
+	case ctx.Err() != nil:
+		s.setOperationStatus(run, op.Id, "cancelled", "Sync cancelled")
+	case err != nil:
+		s.setOperationStatus(run, op.Id, "failed", fmt.Sprintf("Sync failed: %v", err))
+	default:
+		s.setOperationStatus(run, op.Id, "completed", "Sync completed")
+	}
+	return err
+}
+
+// setOperationStatus updates an operation of the run and emits its progress
+func (s *FlowService) setOperationStatus(run *FlowRun, operationId, status, message string) {
+	now := time.Now()
+	run.StatusMu.Lock()
+	for i := range run.Status.Operations {
+		op := &run.Status.Operations[i]
+		if op.OperationId != operationId {
+			continue
+		}
+		op.Status = status
+		op.Message = message
+		if status == "running" {
+			op.StartTime = &now
+		} else if op.StartTime != nil {
+			op.EndTime = &now
+		}
+	}
+	run.StatusMu.Unlock()
+
+	s.emitFlowEvent(events.FlowExecutionProgress, run.FlowId, operationId, status, message)
+}
+
+// snapshot returns a copy of the execution status
+func (r *FlowRun) snapshot() *models.FlowExecutionStatus {
+	r.StatusMu.Lock()
+	defer r.StatusMu.Unlock()
+	status := *r.Status
+	status.Operations = slices.Clone(r.Status.Operations)
+	return &status
+}
+
+// getFlow returns the flow with the given ID
+func (s *FlowService) getFlow(ctx context.Context, flowId string) (*models.Flow, error) {
+	flows, err := s.GetFlows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range flows {
+		if flows[i].Id == flowId {
+			return &flows[i], nil
+		}
+	}
+	return nil, fmt.Errorf("flow '%s' not found", flowId)
+}
+
+// flowName returns the name of the flow for messages
+func flowName(flow *models.Flow) string {
+	if flow.Name == "" {
+		return "(Unnamed Flow)"
+	}
+	return flow.Name
+}
+
+// sendFlowNotification sends a desktop notification for flow completion or failure
+func (s *FlowService) sendFlowNotification(flow *models.Flow, run *FlowRun) {
+	if s.notificationService == nil {
+		return
+	}
+
+	run.StatusMu.Lock()
+	completed, failed := 0, 0
+	for _, op := range run.Status.Operations {
+		switch op.Status {
+		case "completed":
+			completed++
+		case "failed":
+			failed++
+		}
+	}
+	success := run.Status.Status == "completed"
+	run.StatusMu.Unlock()
+
+	title := "Flow Completed"
+	body := fmt.Sprintf("Flow \"%s\" completed successfully. %d operation(s) executed.", flowName(flow), completed)
+	if !success {
+		title = "Flow Execution Failed"
+		body = fmt.Sprintf("Flow \"%s\" completed with %d failure(s).", flowName(flow), failed)
+	}
+
+	// Send notification (context.Background() since run context may be cancelled)
+	if err := s.notificationService.SendNotification(context.Background(), title, body); err != nil {
+		log.Printf("Failed to send flow notification: %v", err)
+	}
+}
+
+// notifyFlowNotStarted tells the user a flow started without the window could not run
+func (s *FlowService) notifyFlowNotStarted(flowId string, err error) {
+	if s.notificationService == nil {
+		return
+	}
+	name := flowId
+	if flow, ferr := s.getFlow(context.Background(), flowId); ferr == nil {
+		name = flowName(flow)
+	}
+	body := fmt.Sprintf("Flow \"%s\" did not run: %v", name, err)
+	if err := s.notificationService.SendNotification(context.Background(), "Flow Execution Failed", body); err != nil {
+		log.Printf("Failed to send flow notification: %v", err)
+	}
+}
+
+// emitFlowEvent emits a flow event
+func (s *FlowService) emitFlowEvent(eventType events.EventType, flowId, operationId, status, message string) {
+	event := events.NewFlowEvent(eventType, flowId, operationId, status, message)
+	if s.eventBus != nil {
+		if err := s.eventBus.EmitFlowEvent(event); err != nil {
+			log.Printf("Failed to emit flow event: %v", err)
+		}
+	} else if s.app != nil {
+		s.app.Event.Emit("tofe", event)
+	}
+}
+
 // ============ Private Helpers ============
 
 func (s *FlowService) getOperationsForFlow(flowId string) ([]models.Operation, error) {
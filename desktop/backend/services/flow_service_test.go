@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"desktop/backend/models"
+	"testing"
+)
+
+func newTestFlowService(t *testing.T) *FlowService {
+	t.Helper()
+	// Clean DB tables for test isolation
+	db, _ := GetSharedDB()
+	db.Exec("DELETE FROM operations")
+	db.Exec("DELETE FROM flows")
+	s := NewFlowService(nil)
+	s.initialized = true
+	return s
+}
+
+func makeTestFlow(id string) models.Flow {
+	return models.Flow{
+		Id:   id,
+		Name: "Backup",
+		Operations: []models.Operation{
+			{Id: "op1", SourceRemote: "local", SourcePath: "/data", TargetRemote: "remote1", TargetPath: "/backup", Action: "push"},
+			{Id: "op2", SourceRemote: "remote1", SourcePath: "/backup", TargetRemote: "remote2", TargetPath: "/", Action: "copy"},
+		},
+	}
+}
+
+func TestFlowService_SaveFlows_ContinueOnFailure(t *testing.T) {
+	s := newTestFlowService(t)
+	ctx := context.Background()
+
+	flow := makeTestFlow("flow-1")
+	flow.ContinueOnFailure = true
+	if err := s.SaveFlows(ctx, []models.Flow{flow}); err != nil {
+		t.Fatalf("SaveFlows failed: %v", err)
+	}
+
+	flows, err := s.GetFlows(ctx)
+	if err != nil {
+		t.Fatalf("GetFlows failed: %v", err)
+	}
+	if len(flows) != 1 || !flows[0].ContinueOnFailure || len(flows[0].Operations) != 2 {
+		t.Errorf("expected the flow with its failure policy and operations, got %+v", flows)
+	}
+}
+
+func TestFlowService_ExecuteFlow_NoSyncService(t *testing.T) {
+	s := newTestFlowService(t)
+	_, err := s.ExecuteFlow(context.Background(), "flow-1")
+	if err == nil {
+		t.Error("expected error without a sync service")
+	}
+}
+
+func TestFlowService_ExecuteFlow_Invalid(t *testing.T) {
+	s := newTestFlowService(t)
+	s.SetSyncService(NewSyncService(nil))
+	ctx := context.Background()
+
+	empty := models.Flow{Id: "empty", Name: "Empty"}
+	incomplete := makeTestFlow("incomplete")
+	incomplete.Operations[1].TargetRemote = ""
+	if err := s.SaveFlows(ctx, []models.Flow{empty, incomplete}); err != nil {
+		t.Fatalf("SaveFlows failed: %v", err)
+	}
+
+	for _, flowId := range []string{"missing", "empty", "incomplete"} {
+		if _, err := s.ExecuteFlow(ctx, flowId); err == nil {
+			t.Errorf("expected error executing flow '%s'", flowId)
+		}
+	}
+	if _, err := s.GetFlowExecutionStatus(ctx, "incomplete"); err == nil {
+		t.Error("expected no execution status for a flow that didn't start")
+	}
+}
+
+func TestFlowService_StopFlow_NotRunning(t *testing.T) {
+	s := newTestFlowService(t)
+	if err := s.StopFlow(context.Background(), "flow-1"); err != nil {
+		t.Errorf("expected no error stopping a flow that isn't running, got %v", err)
+	}
+}
@@ -4,16 +4,23 @@ import (
 	"context"
 	"desktop/backend/events"
 	"desktop/backend/models"
+	"desktop/backend/rclone"
+	"desktop/backend/utils"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
 const maxHistoryEntries = 1000
 
+// defaultTransferQueryLimit caps journal queries that don't specify a limit
+const defaultTransferQueryLimit = 500
+
 // HistoryService manages operation history with SQLite persistence
 type HistoryService struct {
 	app         *application.App
@@ -118,7 +125,8 @@ func (h *HistoryService) GetHistory(ctx context.Context, limit, offset int) ([]m
 	}
 
 	rows, err := db.Query(`SELECT id, profile_name, action, status, start_time, end_time,
-		duration, files_transferred, bytes_transferred, errors, error_message
+		duration, files_transferred, bytes_transferred, errors, error_message,
+		checks, deletes, renames
 		FROM history ORDER BY start_time DESC LIMIT ? OFFSET ?`, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query history: %w", err)
@@ -144,7 +152,8 @@ func (h *HistoryService) GetHistoryForProfile(ctx context.Context, profileName s
 	}
 
 	rows, err := db.Query(`SELECT id, profile_name, action, status, start_time, end_time,
-		duration, files_transferred, bytes_transferred, errors, error_message
+		duration, files_transferred, bytes_transferred, errors, error_message,
+		checks, deletes, renames
 		FROM history WHERE profile_name = ? ORDER BY start_time DESC`, profileName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query history for profile: %w", err)
@@ -236,6 +245,120 @@ func (h *HistoryService) ClearHistory(ctx context.Context) error {
 	return nil
 }
 
+// GetTransfers returns transfer journal records matching the query, newest first
+func (h *HistoryService) GetTransfers(ctx context.Context, query models.TransferQuery) ([]models.TransferRecord, error) {
+	if err := h.ensureInitialized(); err != nil {
+		return nil, err
+	}
+
+	db, err := GetSharedDB()
+	if err != nil {
+		return nil, err
+	}
+
+	where := []string{"1 = 1"}
+	var args []interface{}
+	if query.RunId != "" {
+		where = append(where, "run_id = ?")
+		args = append(args, query.RunId)
+	}
+	if query.ProfileName != "" {
+		where = append(where, "profile_name = ?")
+		args = append(args, query.ProfileName)
+	}
+	if query.Path != "" {
+		where = append(where, "path = ?")
+		args = append(args, query.Path)
+	}
+	if query.PathPrefix != "" {
+		where = append(where, `path LIKE ? ESCAPE '\'`)
+		args = append(args, escapeLike(query.PathPrefix)+"%")
+	}
+	if query.Outcome != "" {
+		where = append(where, "outcome = ?")
+		args = append(args, query.Outcome)
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultTransferQueryLimit
+	}
+	args = append(args, limit, query.Offset)
+
+	rows, err := db.Query(`SELECT id, run_id, profile_name, action, path, outcome, size, error, timestamp
+		FROM transfer_journal WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY timestamp DESC, id DESC LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transfer journal: %w", err)
+	}
+	defer rows.Close()
+
+	records := []models.TransferRecord{}
+	for rows.Next() {
+		var rec models.TransferRecord
+		var ts string
+		if err := rows.Scan(&rec.Id, &rec.RunId, &rec.ProfileName, &rec.Action, &rec.Path,
+			&rec.Outcome, &rec.Size, &rec.Error, &ts); err != nil {
+			return nil, fmt.Errorf("failed to scan transfer record: %w", err)
+		}
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			rec.Timestamp = t
+		}
+		records = append(records, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// GetFileHistory returns the journal records for an exact file path, newest first,
+// answering when the file last changed and which profile touched it
+func (h *HistoryService) GetFileHistory(ctx context.Context, path string, limit int) ([]models.TransferRecord, error) {
+	return h.GetTransfers(ctx, models.TransferQuery{Path: path, Limit: limit})
+}
+
+// escapeLike escapes LIKE wildcards so the value matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// NewHistoryEntry builds a history entry for a finished task. statsCtx is the rclone
+// task context whose accounting stats hold the final totals; it may be nil when the
+// task failed before its context was created.
+func NewHistoryEntry(statsCtx context.Context, profileName, action, status string, startTime time.Time, taskErr error) models.HistoryEntry {
+	endTime := time.Now()
+	entry := models.HistoryEntry{
+		Id:          uuid.New().String(),
+		ProfileName: profileName,
+		Action:      action,
+		Status:      status,
+		StartTime:   startTime,
+		EndTime:     endTime,
+		Duration:    endTime.Sub(startTime).Round(time.Millisecond).String(),
+	}
+
+	if statsCtx != nil {
+		stats := utils.SnapshotStats(statsCtx, startTime)
+		entry.FilesTransferred = stats.FilesTransferred
+		entry.BytesTransferred = stats.BytesTransferred
+		entry.Errors = stats.Errors
+		entry.Checks = stats.Checks
+		entry.Deletes = stats.Deletes
+		entry.Renames = stats.Renames
+	}
+
+	if taskErr != nil && status != "cancelled" {
+		entry.ErrorMessage = taskErr.Error()
+	}
+
+	if journal := rclone.TransferJournalFromContext(statsCtx); journal != nil {
+		entry.Journal = journal.Records(statsCtx)
+	}
+
+	return entry
+}
+
 // saveHistoryEntryToDB inserts a single history entry into the database
 func (h *HistoryService) saveHistoryEntryToDB(e models.HistoryEntry) error {
 	db, err := GetSharedDB()
@@ -243,13 +366,44 @@ func (h *HistoryService) saveHistoryEntryToDB(e models.HistoryEntry) error {
 		return err
 	}
 
-	_, err = db.Exec(`INSERT OR REPLACE INTO history (id, profile_name, action, status, start_time, end_time,
-		duration, files_transferred, bytes_transferred, errors, error_message)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT OR REPLACE INTO history (id, profile_name, action, status, start_time, end_time,
+		duration, files_transferred, bytes_transferred, errors, error_message, checks, deletes, renames)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		e.Id, e.ProfileName, e.Action, e.Status,
 		e.StartTime.UTC().Format(time.RFC3339), e.EndTime.UTC().Format(time.RFC3339),
-		e.Duration, e.FilesTransferred, e.BytesTransferred, e.Errors, e.ErrorMessage)
-	return err
+		e.Duration, e.FilesTransferred, e.BytesTransferred, e.Errors, e.ErrorMessage,
+		e.Checks, e.Deletes, e.Renames)
+	if err != nil {
+		return err
+	}
+
+	if len(e.Journal) > 0 {
+		stmt, err := tx.Prepare(`INSERT INTO transfer_journal (run_id, profile_name, action, path, outcome,
+			size, error, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, rec := range e.Journal {
+			ts := rec.Timestamp
+			if ts.IsZero() {
+				ts = e.EndTime
+			}
+			if _, err := stmt.Exec(e.Id, e.ProfileName, e.Action, rec.Path, rec.Outcome,
+				rec.Size, rec.Error, ts.UTC().Format(time.RFC3339Nano)); err != nil {
+				return fmt.Errorf("failed to save transfer record %q: %w", rec.Path, err)
+			}
+		}
+	}
+
+	return tx.Commit()
 }
 
 // enforceHistoryCap deletes oldest entries exceeding the max count
@@ -271,7 +425,8 @@ func (h *HistoryService) scanHistoryRows(rows interface{ Next() bool; Scan(...in
 		var e models.HistoryEntry
 		var startTime, endTime string
 		if err := rows.Scan(&e.Id, &e.ProfileName, &e.Action, &e.Status, &startTime, &endTime,
-			&e.Duration, &e.FilesTransferred, &e.BytesTransferred, &e.Errors, &e.ErrorMessage); err != nil {
+			&e.Duration, &e.FilesTransferred, &e.BytesTransferred, &e.Errors, &e.ErrorMessage,
+			&e.Checks, &e.Deletes, &e.Renames); err != nil {
 			return nil, fmt.Errorf("failed to scan history entry: %w", err)
 		}
 		if t, err := time.Parse(time.RFC3339, startTime); err == nil {
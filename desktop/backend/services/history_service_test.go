@@ -257,3 +257,94 @@ func TestHistoryService_Pagination(t *testing.T) {
 	}
 }
 
+
+func TestHistoryService_AccountingColumns(t *testing.T) {
+	h := newTestHistoryService(t)
+	ctx := context.Background()
+
+	entry := NewHistoryEntry(nil, "acct-profile", "pull", "failed", time.Now().Add(-time.Second), fmt.Errorf("boom"))
+	entry.Checks = 7
+	entry.Deletes = 2
+	entry.Renames = 1
+
+	if err := h.AddEntry(ctx, entry); err != nil {
+		t.Fatalf("AddEntry failed: %v", err)
+	}
+
+	entries, err := h.GetHistoryForProfile(ctx, "acct-profile")
+	if err != nil {
+		t.Fatalf("GetHistoryForProfile failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	got := entries[0]
+	if got.Checks != 7 || got.Deletes != 2 || got.Renames != 1 {
+		t.Errorf("unexpected accounting values: checks=%d deletes=%d renames=%d", got.Checks, got.Deletes, got.Renames)
+	}
+	if got.ErrorMessage != "boom" {
+		t.Errorf("expected error message 'boom', got %q", got.ErrorMessage)
+	}
+	if got.Id == "" {
+		t.Error("expected generated id")
+	}
+}
+
+func TestHistoryService_TransferJournal(t *testing.T) {
+	h := newTestHistoryService(t)
+	ctx := context.Background()
+
+	entry := NewHistoryEntry(nil, "journal-profile", "push", "completed", time.Now().Add(-time.Second), nil)
+	entry.Journal = []models.TransferRecord{
+		{Path: "docs/a.txt", Outcome: "copied", Size: 10},
+		{Path: "docs/b.txt", Outcome: "updated", Size: 20},
+		{Path: "photos/c.jpg", Outcome: "failed", Error: "permission denied"},
+	}
+	if err := h.AddEntry(ctx, entry); err != nil {
+		t.Fatalf("AddEntry failed: %v", err)
+	}
+
+	all, err := h.GetTransfers(ctx, models.TransferQuery{RunId: entry.Id})
+	if err != nil {
+		t.Fatalf("GetTransfers failed: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(all))
+	}
+
+	docs, err := h.GetTransfers(ctx, models.TransferQuery{PathPrefix: "docs/"})
+	if err != nil {
+		t.Fatalf("GetTransfers by prefix failed: %v", err)
+	}
+	if len(docs) != 2 {
+		t.Errorf("expected 2 records under docs/, got %d", len(docs))
+	}
+
+	failed, err := h.GetTransfers(ctx, models.TransferQuery{Outcome: "failed"})
+	if err != nil {
+		t.Fatalf("GetTransfers by outcome failed: %v", err)
+	}
+	if len(failed) != 1 || failed[0].Error != "permission denied" {
+		t.Errorf("unexpected failed records: %+v", failed)
+	}
+
+	fileHistory, err := h.GetFileHistory(ctx, "docs/b.txt", 10)
+	if err != nil {
+		t.Fatalf("GetFileHistory failed: %v", err)
+	}
+	if len(fileHistory) != 1 || fileHistory[0].ProfileName != "journal-profile" {
+		t.Errorf("unexpected file history: %+v", fileHistory)
+	}
+
+	// Clearing history removes the journal with it
+	if err := h.ClearHistory(ctx); err != nil {
+		t.Fatalf("ClearHistory failed: %v", err)
+	}
+	all, err = h.GetTransfers(ctx, models.TransferQuery{})
+	if err != nil {
+		t.Fatalf("GetTransfers failed: %v", err)
+	}
+	if len(all) != 0 {
+		t.Errorf("expected journal to be cleared, got %d records", len(all))
+	}
+}
@@ -2,8 +2,14 @@ package services
 
 import (
 	"context"
+	"desktop/backend/rclone"
+	"desktop/backend/utils"
+	"encoding/json"
+	"fmt"
 	"log"
+	"maps"
 	"os"
+	"strconv"
 	"sync"
 
 	"github.com/emersion/go-autostart"
@@ -12,11 +18,16 @@ import (
 
 // AppSettings holds persisted application settings
 type AppSettings struct {
-	NotificationsEnabled    bool `json:"notifications_enabled"`
-	DebugMode               bool `json:"debug_mode"`
-	MinimizeToTray          bool `json:"minimize_to_tray"`
-	StartAtLogin            bool `json:"start_at_login"`
-	MinimizeToTrayOnStartup bool `json:"minimize_to_tray_on_startup"`
+	NotificationsEnabled    bool           `json:"notifications_enabled"`
+	DebugMode               bool           `json:"debug_mode"`
+	MinimizeToTray          bool           `json:"minimize_to_tray"`
+	StartAtLogin            bool           `json:"start_at_login"`
+	MinimizeToTrayOnStartup bool           `json:"minimize_to_tray_on_startup"`
+	DefaultBandwidth        string         `json:"default_bandwidth"`            // --bwlimit timetable for profiles without their own limit
+	ShutdownTimeout         int            `json:"shutdown_timeout"`             // seconds a quit waits for running tasks; 0 cancels them at once
+	ResumeInterrupted       bool           `json:"resume_interrupted"`           // resume tasks interrupted by a crash or quit on startup
+	MaxConcurrentTasks      int            `json:"max_concurrent_tasks"`         // tasks the queue runs at once; 0 means unlimited
+	RemoteConcurrency       map[string]int `json:"remote_concurrency,omitempty"` // remote name -> tasks using it at once
 }
 
 // NotificationService handles desktop notifications and app settings persistence
@@ -186,6 +197,127 @@ func (n *NotificationService) IsMinimizeToTrayOnStartup(ctx context.Context) boo
 	return n.settings.MinimizeToTrayOnStartup
 }
 
+// SetDefaultBandwidth sets the bandwidth timetable for operations whose profile has no
+// limit of its own, e.g. "10M" or "Mon-08:00,2M Mon-18:00,off". Empty means unlimited.
+func (n *NotificationService) SetDefaultBandwidth(ctx context.Context, schedule string) error {
+	timetable, err := rclone.ParseBandwidth(schedule)
+	if err != nil {
+		return err
+	}
+	utils.SetDefaultBandwidth(timetable)
+
+	n.mutex.Lock()
+	n.settings.DefaultBandwidth = schedule
+	n.mutex.Unlock()
+	n.saveSetting("default_bandwidth", schedule)
+	return nil
+}
+
+// GetDefaultBandwidth returns the bandwidth timetable for operations without their own limit
+func (n *NotificationService) GetDefaultBandwidth(ctx context.Context) string {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	return n.settings.DefaultBandwidth
+}
+
+// SetShutdownTimeout sets how many seconds quitting the app waits for running tasks to
+// finish before cancelling them. Tasks cancelled by a quit are resumed on the next start.
+func (n *NotificationService) SetShutdownTimeout(ctx context.Context, seconds int) error {
+	if seconds < 0 {
+		return fmt.Errorf("shutdown timeout cannot be negative")
+	}
+
+	n.mutex.Lock()
+	n.settings.ShutdownTimeout = seconds
+	n.mutex.Unlock()
+	n.saveSetting("shutdown_timeout", strconv.Itoa(seconds))
+	return nil
+}
+
+// GetShutdownTimeout returns how many seconds quitting the app waits for running tasks
+func (n *NotificationService) GetShutdownTimeout(ctx context.Context) int {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	return n.settings.ShutdownTimeout
+}
+
+// SetResumeInterrupted enables or disables resuming interrupted tasks on startup
+func (n *NotificationService) SetResumeInterrupted(ctx context.Context, enabled bool) {
+	n.mutex.Lock()
+	n.settings.ResumeInterrupted = enabled
+	n.mutex.Unlock()
+	n.saveSetting("resume_interrupted", boolToStr(enabled))
+}
+
+// IsResumeInterrupted returns whether interrupted tasks are resumed on startup
+func (n *NotificationService) IsResumeInterrupted(ctx context.Context) bool {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	return n.settings.ResumeInterrupted
+}
+
+// SetMaxConcurrentTasks sets how many syncs and operations run at once; the others wait
+// in the task queue. 0 means unlimited.
+func (n *NotificationService) SetMaxConcurrentTasks(ctx context.Context, limit int) error {
+	if limit < 0 {
+		return fmt.Errorf("maximum concurrent tasks cannot be negative")
+	}
+	utils.SetMaxConcurrentTasks(limit)
+
+	n.mutex.Lock()
+	n.settings.MaxConcurrentTasks = limit
+	n.mutex.Unlock()
+	n.saveSetting("max_concurrent_tasks", strconv.Itoa(limit))
+	return nil
+}
+
+// GetMaxConcurrentTasks returns how many tasks run at once, 0 meaning unlimited
+func (n *NotificationService) GetMaxConcurrentTasks(ctx context.Context) int {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	return n.settings.MaxConcurrentTasks
+}
+
+// SetRemoteConcurrency caps how many tasks use a remote at once, e.g. to stay below a
+// provider's rate limits. 0 removes the cap.
+func (n *NotificationService) SetRemoteConcurrency(ctx context.Context, remote string, limit int) error {
+	if remote == "" {
+		return fmt.Errorf("remote name cannot be empty")
+	}
+	if limit < 0 {
+		return fmt.Errorf("remote concurrency cannot be negative")
+	}
+
+	n.mutex.Lock()
+	// Copy so settings returned earlier aren't changed under their readers
+	limits := maps.Clone(n.settings.RemoteConcurrency)
+	if limits == nil {
+		limits = map[string]int{}
+	}
+	if limit > 0 {
+		limits[remote] = limit
+	} else {
+		delete(limits, remote)
+	}
+	n.settings.RemoteConcurrency = limits
+	n.mutex.Unlock()
+
+	utils.SetRemoteConcurrency(limits)
+	data, err := json.Marshal(limits)
+	if err != nil {
+		return err
+	}
+	n.saveSetting("remote_concurrency", string(data))
+	return nil
+}
+
+// GetRemoteConcurrency returns the per-remote caps on concurrent tasks
+func (n *NotificationService) GetRemoteConcurrency(ctx context.Context) map[string]int {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	return n.settings.RemoteConcurrency
+}
+
 // LoadSettings loads settings from the database. Exported for early loading in main.go.
 func (n *NotificationService) LoadSettings() {
 	db, err := GetSharedDB()
@@ -220,6 +352,33 @@ func (n *NotificationService) LoadSettings() {
 			n.settings.StartAtLogin = value == "true"
 		case "minimize_to_tray_on_startup":
 			n.settings.MinimizeToTrayOnStartup = value == "true"
+		case "default_bandwidth":
+			timetable, err := rclone.ParseBandwidth(value)
+			if err != nil {
+				log.Printf("Warning: Ignoring default bandwidth: %v", err)
+				continue
+			}
+			n.settings.DefaultBandwidth = value
+			utils.SetDefaultBandwidth(timetable)
+		case "shutdown_timeout":
+			if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+				n.settings.ShutdownTimeout = seconds
+			}
+		case "resume_interrupted":
+			n.settings.ResumeInterrupted = value == "true"
+		case "max_concurrent_tasks":
+			if limit, err := strconv.Atoi(value); err == nil && limit >= 0 {
+				n.settings.MaxConcurrentTasks = limit
+				utils.SetMaxConcurrentTasks(limit)
+			}
+		case "remote_concurrency":
+			var limits map[string]int
+			if err := json.Unmarshal([]byte(value), &limits); err != nil {
+				log.Printf("Warning: Ignoring remote concurrency: %v", err)
+				continue
+			}
+			n.settings.RemoteConcurrency = limits
+			utils.SetRemoteConcurrency(limits)
 		}
 	}
 }
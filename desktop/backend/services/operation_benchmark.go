@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"desktop/backend/dto"
+	"desktop/backend/models"
+	"desktop/backend/rclone"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Benchmark starts measuring a remote's upload and download throughput, request latency
+// and error rate with generated files of several sizes at several parallelism levels.
+// The results are stored for comparison over time. Returns the task ID.
+func (o *OperationService) Benchmark(ctx context.Context, config models.BenchmarkConfig, tabId string) (int, error) {
+	if config.Remote == "" {
+		return 0, fmt.Errorf("benchmark remote cannot be empty")
+	}
+	config = rclone.BenchmarkDefaults(config)
+	return o.startTask(ctx, &OperationTask{
+		Operation:       "benchmark",
+		Profile:         models.Profile{Name: config.Remote, From: config.Remote},
+		TabId:           tabId,
+		BenchmarkConfig: &config,
+	})
+}
+
+// GetBenchmarks returns the stored benchmarks of a remote path, or of every remote when
+// remote is empty, newest first
+func (o *OperationService) GetBenchmarks(ctx context.Context, remote string) ([]models.BenchmarkRun, error) {
+	return loadBenchmarksFromDB(remote)
+}
+
+// DeleteBenchmark removes a stored benchmark
+func (o *OperationService) DeleteBenchmark(ctx context.Context, benchmarkId string) error {
+	db, err := GetSharedDB()
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec("DELETE FROM benchmark_runs WHERE id = ?", benchmarkId); err != nil {
+		return fmt.Errorf("failed to delete benchmark: %w", err)
+	}
+	return nil
+}
+
+// benchmark runs the benchmark and stores the run, including partial results of a
+// failed or cancelled one
+func (o *OperationService) benchmark(ctx context.Context, config models.BenchmarkConfig, outStatus chan *dto.SyncStatusDTO) (*models.BenchmarkRun, error) {
+	run := &models.BenchmarkRun{
+		Id:        uuid.New().String(),
+		Remote:    config.Remote,
+		Config:    config,
+		Status:    "completed",
+		StartedAt: time.Now(),
+	}
+
+	results, err := rclone.Benchmark(ctx, config, outStatus)
+	run.Results = results
+	if run.Results == nil {
+		run.Results = []models.BenchmarkResult{}
+	}
+	run.Duration = time.Since(run.StartedAt).Round(time.Second).String()
+	switch {
+	case ctx.Err() != nil:
+		run.Status = "cancelled"
+	case err != nil:
+		run.Status = "failed"
+		run.Message = err.Error()
+	}
+
+	if saveErr := saveBenchmarkToDB(run); saveErr != nil {
+		log.Printf("Failed to save benchmark of %s: %v", run.Remote, saveErr)
+	}
+	return run, err
+}
+
+// ============ SQLite persistence ============
+
+// saveBenchmarkToDB inserts or replaces a benchmark run
+func saveBenchmarkToDB(r *models.BenchmarkRun) error {
+	db, err := GetSharedDB()
+	if err != nil {
+		return err
+	}
+
+	configJSON, err := json.Marshal(r.Config)
+	if err != nil {
+		return err
+	}
+	resultsJSON, err := json.Marshal(r.Results)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`INSERT OR REPLACE INTO benchmark_runs (id, remote, config, results, status, message,
+		started_at, duration) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		r.Id, r.Remote, string(configJSON), string(resultsJSON), r.Status, r.Message,
+		r.StartedAt.UTC().Format(time.RFC3339), r.Duration)
+	return err
+}
+
+// loadBenchmarksFromDB loads the benchmark runs of a remote, or all runs when remote is empty
+func loadBenchmarksFromDB(remote string) ([]models.BenchmarkRun, error) {
+	db, err := GetSharedDB()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id, remote, config, results, status, message, started_at, duration FROM benchmark_runs`
+	var rows *sql.Rows
+	if remote != "" {
+		rows, err = db.Query(query+" WHERE remote = ? ORDER BY started_at DESC", remote)
+	} else {
+		rows, err = db.Query(query + " ORDER BY started_at DESC")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query benchmarks: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []models.BenchmarkRun{}
+	for rows.Next() {
+		var r models.BenchmarkRun
+		var configJSON, resultsJSON, startedAt string
+		if err := rows.Scan(&r.Id, &r.Remote, &configJSON, &resultsJSON, &r.Status, &r.Message,
+			&startedAt, &r.Duration); err != nil {
+			return nil, fmt.Errorf("failed to scan benchmark: %w", err)
+		}
+		if err := json.Unmarshal([]byte(configJSON), &r.Config); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal config of benchmark %s: %w", r.Id, err)
+		}
+		if err := json.Unmarshal([]byte(resultsJSON), &r.Results); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal results of benchmark %s: %w", r.Id, err)
+		}
+		if t, err := time.Parse(time.RFC3339, startedAt); err == nil {
+			r.StartedAt = t
+		}
+		runs = append(runs, r)
+	}
+	return runs, rows.Err()
+}
@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"desktop/backend/dto"
+	"desktop/backend/models"
+	"desktop/backend/rclone"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreatePlan computes and persists a reviewable change set for a one-way "push", "pull"
+// or "copy" of the profile. Nothing is modified until the plan is applied.
+func (o *OperationService) CreatePlan(ctx context.Context, action string, profile models.Profile) (*models.SyncPlan, error) {
+	return o.createPlan(ctx, action, profile, "", "")
+}
+
+// CreateBoardEdgePlan computes and persists a change set for a single board edge
+func (o *OperationService) CreateBoardEdgePlan(ctx context.Context, boardId, edgeId string) (*models.SyncPlan, error) {
+	boardService := GetBoardService()
+	if boardService == nil {
+		return nil, fmt.Errorf("board service not available")
+	}
+
+	board, err := boardService.GetBoard(ctx, boardId)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range board.Edges {
+		edge := &board.Edges[i]
+		if edge.Id != edgeId {
+			continue
+		}
+		sourceNode, targetNode := findEdgeNodes(board, edge)
+		if sourceNode == nil || targetNode == nil {
+			return nil, fmt.Errorf("source or target node not found for edge '%s'", edgeId)
+		}
+		profile := boardService.buildEdgeProfile(edge, sourceNode, targetNode)
+		return o.createPlan(ctx, edge.Action, profile, boardId, edgeId)
+	}
+	return nil, fmt.Errorf("edge '%s' not found in board '%s'", edgeId, boardId)
+}
+
+// GetPlans returns all persisted plans, newest first
+func (o *OperationService) GetPlans(ctx context.Context) ([]models.SyncPlan, error) {
+	return loadPlansFromDB("")
+}
+
+// GetPlan returns a single plan by ID
+func (o *OperationService) GetPlan(ctx context.Context, planId string) (*models.SyncPlan, error) {
+	plans, err := loadPlansFromDB(planId)
+	if err != nil {
+		return nil, err
+	}
+	if len(plans) == 0 {
+		return nil, fmt.Errorf("plan '%s' not found", planId)
+	}
+	return &plans[0], nil
+}
+
+// DeletePlan removes a plan
+func (o *OperationService) DeletePlan(ctx context.Context, planId string) error {
+	db, err := GetSharedDB()
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec("DELETE FROM sync_plans WHERE id = ?", planId); err != nil {
+		return fmt.Errorf("failed to delete plan: %w", err)
+	}
+	return nil
+}
+
+// ApplyPlan starts an operation that executes exactly the approved plan. The operation
+// fails without touching anything if the source or destination drifted since planning.
+func (o *OperationService) ApplyPlan(ctx context.Context, planId string, tabId string) (int, error) {
+	plan, err := o.GetPlan(ctx, planId)
+	if err != nil {
+		return 0, err
+	}
+	if err := claimPlan(planId); err != nil {
+		return 0, err
+	}
+
+	return o.startTask(ctx, &OperationTask{
+		Operation: "apply",
+		PlanId:    planId,
+		Profile:   plan.Profile,
+		TabId:     tabId,
+	})
+}
+
+// createPlan runs the dry-run planner and persists the result
+func (o *OperationService) createPlan(ctx context.Context, action string, profile models.Profile, boardId, edgeId string) (*models.SyncPlan, error) {
+	opCtx, err := rclone.SimpleContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize rclone config: %w", err)
+	}
+
+	changes, err := rclone.PlanSync(opCtx, action, profile)
+	if err != nil {
+		return nil, err
+	}
+	if changes == nil {
+		changes = []models.PlanChange{}
+	}
+
+	plan := &models.SyncPlan{
+		Id:          uuid.New().String(),
+		ProfileName: profile.Name,
+		Action:      action,
+		Profile:     profile,
+		BoardId:     boardId,
+		EdgeId:      edgeId,
+		Changes:     changes,
+		Status:      "pending",
+		CreatedAt:   time.Now(),
+	}
+	plan.Summarize()
+
+	if err := savePlanToDB(plan); err != nil {
+		return nil, fmt.Errorf("failed to save plan: %w", err)
+	}
+	return plan, nil
+}
+
+// applyPlan re-plans to detect drift and then executes the approved change set. The
+// plan was set to "applying" by ApplyPlan.
+func (o *OperationService) applyPlan(ctx context.Context, planId string, outStatus chan *dto.SyncStatusDTO) error {
+	plan, err := o.GetPlan(ctx, planId)
+	if err != nil {
+		return err
+	}
+
+	current, err := rclone.PlanSync(ctx, plan.Action, plan.Profile)
+	if err != nil {
+		if ctx.Err() != nil {
+			return err // nothing applied yet; releasePlan makes it pending again
+		}
+		_ = updatePlanStatus(planId, "failed", err.Error(), nil)
+		return fmt.Errorf("failed to verify plan: %w", err)
+	}
+
+	if drift := rclone.DiffPlanChanges(plan.Changes, current); len(drift) > 0 {
+		msg := fmt.Sprintf("source or destination changed since planning: %s", strings.Join(drift, "; "))
+		_ = updatePlanStatus(planId, "stale", msg, nil)
+		return errors.New(msg)
+	}
+
+	if err := rclone.ApplyPlan(ctx, o.envConfig, plan.Action, plan.Profile, plan.Changes, outStatus); err != nil {
+		if ctx.Err() != nil {
+			// Part of the plan may be applied, so it no longer matches the remotes
+			_ = updatePlanStatus(planId, "interrupted", "Cancelled while applying; some changes may already be applied", nil)
+		} else {
+			_ = updatePlanStatus(planId, "failed", err.Error(), nil)
+		}
+		return err
+	}
+
+	appliedAt := time.Now()
+	return updatePlanStatus(planId, "applied", "", &appliedAt)
+}
+
+// ============ SQLite persistence ============
+
+// savePlanToDB inserts or replaces a plan
+func savePlanToDB(p *models.SyncPlan) error {
+	db, err := GetSharedDB()
+	if err != nil {
+		return err
+	}
+
+	profileJSON, err := json.Marshal(p.Profile)
+	if err != nil {
+		return err
+	}
+	changesJSON, err := json.Marshal(p.Changes)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`INSERT OR REPLACE INTO sync_plans (id, profile_name, action, profile, board_id, edge_id,
+		changes, status, message, created_at, applied_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.Id, p.ProfileName, p.Action, string(profileJSON), p.BoardId, p.EdgeId,
+		string(changesJSON), p.Status, p.Message, p.CreatedAt.UTC().Format(time.RFC3339),
+		timePtrToNullable(p.AppliedAt))
+	return err
+}
+
+// claimPlan moves a pending plan to "applying", so that it is applied only once
+func claimPlan(planId string) error {
+	db, err := GetSharedDB()
+	if err != nil {
+		return err
+	}
+	result, err := db.Exec("UPDATE sync_plans SET status = 'applying', message = '' WHERE id = ? AND status = 'pending'", planId)
+	if err != nil {
+		return fmt.Errorf("failed to update plan status: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		var status string
+		if err := db.QueryRow("SELECT status FROM sync_plans WHERE id = ?", planId).Scan(&status); err != nil {
+			return fmt.Errorf("plan '%s' not found", planId)
+		}
+		return fmt.Errorf("plan '%s' is %s and cannot be applied", planId, status)
+	}
+	return nil
+}
+
+// releasePlan makes a plan still "applying" pending again once its operation ended
+// before applying anything, e.g. when it was cancelled in the queue
+func releasePlan(planId string) {
+	db, err := GetSharedDB()
+	if err != nil {
+		return
+	}
+	_, _ = db.Exec("UPDATE sync_plans SET status = 'pending' WHERE id = ? AND status = 'applying'", planId)
+}
+
+// updatePlanStatus updates the status, message and applied time of a plan
+func updatePlanStatus(planId, status, message string, appliedAt *time.Time) error {
+	db, err := GetSharedDB()
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.Exec("UPDATE sync_plans SET status = ?, message = ?, applied_at = ? WHERE id = ?",
+		status, message, timePtrToNullable(appliedAt), planId); err != nil {
+		return fmt.Errorf("failed to update plan status: %w", err)
+	}
+	return nil
+}
+
+// loadPlansFromDB loads all plans, or only the given one when planId is set
+func loadPlansFromDB(planId string) ([]models.SyncPlan, error) {
+	db, err := GetSharedDB()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id, profile_name, action, profile, board_id, edge_id, changes, status, message,
+		created_at, applied_at FROM sync_plans`
+	var rows *sql.Rows
+	if planId != "" {
+		rows, err = db.Query(query+" WHERE id = ?", planId)
+	} else {
+		rows, err = db.Query(query + " ORDER BY created_at DESC")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query plans: %w", err)
+	}
+	defer rows.Close()
+
+	plans := []models.SyncPlan{}
+	for rows.Next() {
+		var p models.SyncPlan
+		var profileJSON, changesJSON, createdAt string
+		var appliedAt *string
+		if err := rows.Scan(&p.Id, &p.ProfileName, &p.Action, &profileJSON, &p.BoardId, &p.EdgeId,
+			&changesJSON, &p.Status, &p.Message, &createdAt, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan plan: %w", err)
+		}
+		if err := json.Unmarshal([]byte(profileJSON), &p.Profile); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal profile of plan %s: %w", p.Id, err)
+		}
+		if err := json.Unmarshal([]byte(changesJSON), &p.Changes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal changes of plan %s: %w", p.Id, err)
+		}
+		if t, err := time.Parse(time.RFC3339, createdAt); err == nil {
+			p.CreatedAt = t
+		}
+		if appliedAt != nil {
+			if t, err := time.Parse(time.RFC3339, *appliedAt); err == nil {
+				p.AppliedAt = &t
+			}
+		}
+		p.Summarize()
+		plans = append(plans, p)
+	}
+	return plans, rows.Err()
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"desktop/backend/models"
+	"testing"
+	"time"
+)
+
+func TestOperationPlan_ClaimOnce(t *testing.T) {
+	db, _ := GetSharedDB()
+	db.Exec("DELETE FROM sync_plans")
+
+	plan := &models.SyncPlan{Id: "plan-1", Action: "push", Changes: []models.PlanChange{}, Status: "pending", CreatedAt: time.Now()}
+	if err := savePlanToDB(plan); err != nil {
+		t.Fatalf("savePlanToDB failed: %v", err)
+	}
+
+	if err := claimPlan(plan.Id); err != nil {
+		t.Fatalf("claimPlan failed: %v", err)
+	}
+	if err := claimPlan(plan.Id); err == nil {
+		t.Error("expected a plan already applying to be refused")
+	}
+
+	// A plan released before applying anything can be applied again
+	releasePlan(plan.Id)
+	if err := claimPlan(plan.Id); err != nil {
+		t.Fatalf("expected the released plan to be claimable: %v", err)
+	}
+
+	// One that was interrupted while applying stays refused
+	if err := updatePlanStatus(plan.Id, "interrupted", "", nil); err != nil {
+		t.Fatalf("updatePlanStatus failed: %v", err)
+	}
+	releasePlan(plan.Id)
+	if err := claimPlan(plan.Id); err == nil {
+		t.Error("expected an interrupted plan to be refused")
+	}
+}
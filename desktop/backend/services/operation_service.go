@@ -8,6 +8,7 @@ import (
 	"desktop/backend/models"
 	"desktop/backend/rclone"
 	"desktop/backend/utils"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -20,23 +21,45 @@ import (
 // OperationTask represents an active non-sync operation
 type OperationTask struct {
 	Id        int
-	Operation string // "copy", "move", "check", "dedupe"
+	Operation string // "copy", "move", "check", "cryptcheck", "reencrypt", "dedupe", "manifest", "verify-manifest", "apply", "benchmark"
+	PlanId    string // plan being applied (only for "apply")
 	Profile   models.Profile
 	TabId     string
 	Cancel    context.CancelFunc
 	StartTime time.Time
 	EndTime   *time.Time
-	Status    string
+	Status    string             // guarded by OperationService.mutex
+	Done      chan struct{}      // closed when the task completes
+	Err       error              // result of the task, set before Done is closed
+	Pause     *rclone.PauseGate  // holds the task while paused
+	Ticket    *utils.QueueTicket // the task's place in the task queue
+	RunId     string             // persisted run, resumed if the app quits first; empty if not resumable
+
+	CheckDownload bool                // compare contents by downloading (for "check" and manifests)
+	CheckReport   *models.CheckReport // result of a finished "check", "cryptcheck" or "reencrypt"
+
+	DedupeMode   string               // dedupe mode (only for "dedupe")
+	DedupeByHash bool                 // group duplicates by hash instead of name (only for "dedupe")
+	DedupeReport *models.DedupeReport // duplicate groups of a finished "list" dedupe
+
+	ManifestPath   string                 // manifest to write or verify against
+	HashType       string                 // manifest hash type, e.g. "sha256" or "md5"
+	ManifestFiles  int                    // files written to a finished "manifest"
+	ManifestReport *models.ManifestReport // result of a finished "verify-manifest"
+
+	BenchmarkConfig *models.BenchmarkConfig // benchmark to run (only for "benchmark")
+	BenchmarkRun    *models.BenchmarkRun    // stored result of a finished "benchmark"
 }
 
 // OperationService handles non-sync rclone operations (copy, move, check, dedupe, file browser, etc.)
 type OperationService struct {
-	app         *application.App
-	eventBus    *events.WailsEventBus
-	activeTasks map[int]*OperationTask
-	taskCounter int
-	mutex       sync.RWMutex
-	envConfig   beConfig.Config
+	app            *application.App
+	eventBus       *events.WailsEventBus
+	historyService *HistoryService
+	activeTasks    map[int]*OperationTask
+	taskCounter    int
+	mutex          sync.RWMutex
+	envConfig      beConfig.Config
 }
 
 // NewOperationService creates a new operation service
@@ -66,6 +89,11 @@ func (o *OperationService) SetEnvConfig(config beConfig.Config) {
 	}
 }
 
+// SetHistoryService sets the history service used to record finished operations
+func (o *OperationService) SetHistoryService(historyService *HistoryService) {
+	o.historyService = historyService
+}
+
 // ServiceName returns the name of the service
 func (o *OperationService) ServiceName() string {
 	return "OperationService"
@@ -100,9 +128,158 @@ func (o *OperationService) Move(ctx context.Context, profile models.Profile, tab
 	return o.startOperation(ctx, "move", profile, tabId)
 }
 
-// Check starts a check/verify operation
-func (o *OperationService) CheckFiles(ctx context.Context, profile models.Profile, tabId string) (int, error) {
-	return o.startOperation(ctx, "check", profile, tabId)
+// CheckFiles compares source and destination and returns a structured report with a
+// per-directory rollup. With download set, file contents are compared by downloading
+// them, for remotes that share no common hash. Progress is reported like other operations.
+func (o *OperationService) CheckFiles(ctx context.Context, profile models.Profile, tabId string, download bool) (*models.CheckReport, error) {
+	task := &OperationTask{
+		Operation:     "check",
+		Profile:       profile,
+		TabId:         tabId,
+		CheckDownload: download,
+	}
+	if _, err := o.startTask(ctx, task); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-task.Done:
+		if task.Err != nil && task.CheckReport == nil {
+			return nil, task.Err
+		}
+		return task.CheckReport, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// CryptCheck verifies the profile's encrypted destination (a crypt remote) against its
+// plaintext source by comparing hashes, without downloading, and waits for the report
+func (o *OperationService) CryptCheck(ctx context.Context, profile models.Profile, tabId string) (*models.CheckReport, error) {
+	task := &OperationTask{
+		Operation: "cryptcheck",
+		Profile:   profile,
+		TabId:     tabId,
+	}
+	if _, err := o.startTask(ctx, task); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-task.Done:
+		if task.Err != nil && task.CheckReport == nil {
+			return nil, task.Err
+		}
+		return task.CheckReport, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ReEncrypt copies everything from one crypt remote to another, e.g. one with new keys,
+// then verifies the copy and waits for the verification report. The copy fails when
+// the report shows differences.
+func (o *OperationService) ReEncrypt(ctx context.Context, profile models.Profile, tabId string) (*models.CheckReport, error) {
+	task := &OperationTask{
+		Operation: "reencrypt",
+		Profile:   profile,
+		TabId:     tabId,
+	}
+	if _, err := o.startTask(ctx, task); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-task.Done:
+		return task.CheckReport, task.Err
+	case <-ctx.Done():
+		// The caller cleans up the target, so the copy must have stopped writing to it
+		task.Cancel()
+		<-task.Done
+		return nil, ctx.Err()
+	}
+}
+
+// Dedupe starts resolving duplicate files at the given remote path. mode is one of
+// "skip", "first", "newest", "oldest", "largest", "smallest", "rename" or "list"; with
+// byHash, files with identical content are treated as duplicates instead of files
+// sharing a name.
+func (o *OperationService) Dedupe(ctx context.Context, remotePath, mode string, byHash bool, tabId string) (int, error) {
+	if _, err := rclone.ParseDedupeMode(mode); err != nil {
+		return 0, err
+	}
+	return o.startTask(ctx, &OperationTask{
+		Operation:    "dedupe",
+		Profile:      models.Profile{Name: remotePath, From: remotePath},
+		TabId:        tabId,
+		DedupeMode:   mode,
+		DedupeByHash: byHash,
+	})
+}
+
+// ListDuplicates finds duplicate files at the given remote path without changing
+// anything and waits for the groups. Progress is reported like other operations.
+func (o *OperationService) ListDuplicates(ctx context.Context, remotePath string, byHash bool, tabId string) (*models.DedupeReport, error) {
+	task := &OperationTask{
+		Operation:    "dedupe",
+		Profile:      models.Profile{Name: remotePath, From: remotePath},
+		TabId:        tabId,
+		DedupeMode:   models.DedupeList,
+		DedupeByHash: byHash,
+	}
+	if _, err := o.startTask(ctx, task); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-task.Done:
+		if task.Err != nil {
+			return nil, task.Err
+		}
+		return task.DedupeReport, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// GenerateManifest hashes every file at remotePath and writes an rclone hashsum
+// compatible manifest to manifestPath. With download set, files are downloaded and
+// hashed locally instead of using the provider's checksums. Returns the task ID.
+func (o *OperationService) GenerateManifest(ctx context.Context, remotePath, manifestPath, hashType string, download bool, tabId string) (int, error) {
+	return o.startTask(ctx, &OperationTask{
+		Operation:     "manifest",
+		Profile:       models.Profile{Name: remotePath, From: remotePath},
+		TabId:         tabId,
+		CheckDownload: download,
+		ManifestPath:  manifestPath,
+		HashType:      hashType,
+	})
+}
+
+// VerifyManifest re-hashes the files at remotePath, compares them with a stored
+// manifest and waits for the report of changed, missing and extra files
+func (o *OperationService) VerifyManifest(ctx context.Context, remotePath, manifestPath, hashType string, download bool, tabId string) (*models.ManifestReport, error) {
+	task := &OperationTask{
+		Operation:     "verify-manifest",
+		Profile:       models.Profile{Name: remotePath, From: remotePath},
+		TabId:         tabId,
+		CheckDownload: download,
+		ManifestPath:  manifestPath,
+		HashType:      hashType,
+	}
+	if _, err := o.startTask(ctx, task); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-task.Done:
+		if task.Err != nil && task.ManifestReport == nil {
+			return nil, task.Err
+		}
+		return task.ManifestReport, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 }
 
 // DryRun runs the specified action in dry-run mode (preview only)
@@ -164,7 +341,8 @@ func (o *OperationService) GetSize(ctx context.Context, remotePath string) (int6
 	return rclone.GetSize(opCtx, remotePath)
 }
 
-// StopOperation stops a running operation by task ID
+// StopOperation stops a running operation by task ID. The task stays active, with status
+// "cancelled", until it has wound down; see SyncService.StopSync.
 func (o *OperationService) StopOperation(ctx context.Context, taskId int) error {
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
@@ -173,6 +351,9 @@ func (o *OperationService) StopOperation(ctx context.Context, taskId int) error
 	if !exists {
 		return fmt.Errorf("task %d not found", taskId)
 	}
+	if task.Status == "cancelled" {
+		return nil
+	}
 
 	if task.Cancel != nil {
 		task.Cancel()
@@ -180,43 +361,134 @@ func (o *OperationService) StopOperation(ctx context.Context, taskId int) error
 
 	task.Status = "cancelled"
 	o.emitOperationEvent(events.OperationFailed, task.TabId, task.Operation, "cancelled", "Operation cancelled")
-	delete(o.activeTasks, taskId)
 	return nil
 }
 
-// GetActiveTasks returns a copy of active operation tasks
+// PauseOperation pauses a running operation; see SyncService.PauseSync
+func (o *OperationService) PauseOperation(ctx context.Context, taskId int) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	task, exists := o.activeTasks[taskId]
+	if !exists {
+		return fmt.Errorf("task %d not found", taskId)
+	}
+	if task.Status == "cancelled" {
+		return fmt.Errorf("task %d is being cancelled", taskId)
+	}
+	if !task.Pause.Pause() {
+		return fmt.Errorf("task %d is already paused", taskId)
+	}
+	task.Status = "paused"
+	o.emitOperationEvent(events.OperationPaused, task.TabId, task.Operation, "paused", "Operation paused")
+	return nil
+}
+
+// ResumeOperation resumes a paused operation
+func (o *OperationService) ResumeOperation(ctx context.Context, taskId int) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	task, exists := o.activeTasks[taskId]
+	if !exists {
+		return fmt.Errorf("task %d not found", taskId)
+	}
+	if task.Status == "cancelled" {
+		return fmt.Errorf("task %d is being cancelled", taskId)
+	}
+	if !task.Pause.Resume() {
+		return fmt.Errorf("task %d is not paused", taskId)
+	}
+	task.Status = "running"
+	o.emitOperationEvent(events.OperationResumed, task.TabId, task.Operation, "running", "Operation resumed")
+	return nil
+}
+
+// SetTaskBandwidth caps the bandwidth of a running operation until it finishes and
+// returns the shared limit now in force; see SyncService.SetTaskBandwidth
+func (o *OperationService) SetTaskBandwidth(ctx context.Context, taskId int, limit string) (string, error) {
+	o.mutex.RLock()
+	_, exists := o.activeTasks[taskId]
+	o.mutex.RUnlock()
+
+	if !exists {
+		return "", fmt.Errorf("task %d not found", taskId)
+	}
+	return rclone.SetTaskBandwidth(taskId, limit)
+}
+
+// GetActiveTasks returns a snapshot of active operation tasks
 func (o *OperationService) GetActiveTasks(ctx context.Context) (map[int]*OperationTask, error) {
 	o.mutex.RLock()
 	defer o.mutex.RUnlock()
 
+	// Copy the tasks, as their status and results change under the lock
 	tasks := make(map[int]*OperationTask)
 	for id, task := range o.activeTasks {
-		tasks[id] = task
+		snapshot := *task
+		tasks[id] = &snapshot
 	}
 	return tasks, nil
 }
 
 // startOperation starts an async operation
 func (o *OperationService) startOperation(ctx context.Context, operation string, profile models.Profile, tabId string) (int, error) {
+	return o.startTask(ctx, &OperationTask{
+		Operation: operation,
+		Profile:   profile,
+		TabId:     tabId,
+	})
+}
+
+// startTask registers a prepared task and runs it asynchronously
+func (o *OperationService) startTask(ctx context.Context, task *OperationTask) (int, error) {
 	o.mutex.Lock()
 	o.taskCounter++
 	taskId := o.taskCounter
 	taskCtx, cancel := context.WithCancel(ctx)
 
-	task := &OperationTask{
-		Id:        taskId,
-		Operation: operation,
-		Profile:   profile,
-		TabId:     tabId,
-		Cancel:    cancel,
-		StartTime: time.Now(),
-		Status:    "starting",
+	task.Id = taskId
+	task.Cancel = cancel
+	task.StartTime = time.Now()
+	task.Status = "starting"
+	task.Done = make(chan struct{})
+	task.Pause = rclone.NewPauseGate()
+	if isResumableOperation(task) {
+		task.RunId = recordTaskRun(models.TaskRun{
+			Kind:    "operation",
+			Action:  task.Operation,
+			Profile: task.Profile,
+			TabId:   task.TabId,
+			Options: models.TaskOptions{
+				CheckDownload: task.CheckDownload,
+				DedupeMode:    task.DedupeMode,
+				DedupeByHash:  task.DedupeByHash,
+				ManifestPath:  task.ManifestPath,
+				HashType:      task.HashType,
+			},
+		})
+	}
+
+	task.Ticket = utils.SubmitTask(ctx, utils.QueueEntry{
+		Kind:    "operation",
+		TaskId:  taskId,
+		TabId:   task.TabId,
+		Name:    task.Profile.Name,
+		Action:  task.Operation,
+		Remotes: rclone.TaskRemotes(task.Profile.From, task.Profile.To),
+	}, cancel)
+	queued := task.Ticket.Queued()
+	if queued {
+		task.Status = "queued"
 	}
 
 	o.activeTasks[taskId] = task
 	o.mutex.Unlock()
 
-	o.emitOperationEvent(events.OperationStarted, tabId, operation, "starting", fmt.Sprintf("Starting %s operation", operation))
+	o.emitOperationEvent(events.OperationStarted, task.TabId, task.Operation, "starting", fmt.Sprintf("Starting %s operation", task.Operation))
+	if queued {
+		o.emitOperationEvent(events.OperationQueued, task.TabId, task.Operation, "queued", fmt.Sprintf("%s operation queued", task.Operation))
+	}
 
 	go o.executeOperation(taskCtx, task)
 	return taskId, nil
@@ -224,18 +496,49 @@ func (o *OperationService) startOperation(ctx context.Context, operation string,
 
 // executeOperation runs the operation asynchronously
 func (o *OperationService) executeOperation(ctx context.Context, task *OperationTask) {
+	var taskErr error
+	var statsCtx context.Context
 	defer func() {
+		status := o.taskStatus(task)
+		o.recordHistory(statsCtx, task, status, taskErr)
+		if task.PlanId != "" {
+			releasePlan(task.PlanId)
+		}
+		finishTaskRun(task.RunId, status == "cancelled")
+		task.Ticket.Release()
 		o.mutex.Lock()
 		delete(o.activeTasks, task.Id)
 		o.mutex.Unlock()
+		task.Err = taskErr
+		close(task.Done)
 	}()
 
+	// Wait for the task queue to start the task
+	if err := task.Ticket.Wait(ctx); err != nil {
+		o.setTaskStatus(task, "cancelled")
+		taskErr = err
+		o.emitOperationEvent(events.OperationFailed, task.TabId, task.Operation, "cancelled", "Operation was cancelled")
+		return
+	}
+	startTaskRun(task.RunId)
+
 	// Initialize rclone config with isolated context
 	ctx, err := rclone.NewTaskContext(ctx, task.Id)
 	if err != nil {
+		o.setTaskStatus(task, "failed")
+		taskErr = fmt.Errorf("failed to initialize rclone config: %w", err)
 		o.handleOperationError(task, fmt.Sprintf("Failed to initialize rclone config: %v", err))
 		return
 	}
+	if task.Operation == "copy" || task.Operation == "move" || task.Operation == "apply" {
+		run := rclone.JournalCopy
+		if task.Operation == "move" {
+			run = rclone.JournalMove
+		}
+		ctx = rclone.WithTransferJournal(ctx, rclone.NewTransferJournal(run))
+	}
+	statsCtx = ctx
+	ctx = rclone.WithPause(ctx, task.Pause)
 
 	outStatus := make(chan *dto.SyncStatusDTO, 100)
 
@@ -255,6 +558,9 @@ func (o *OperationService) executeOperation(ctx context.Context, task *Operation
 			status.Id = &task.Id
 			status.TabId = &task.TabId
 			status.Action = task.Operation
+			if task.Pause.Paused() {
+				status.Status = "paused"
+			}
 
 			// Extract LogMessages for text-based event consumers
 			for _, logMsg := range status.LogMessages {
@@ -275,28 +581,61 @@ func (o *OperationService) executeOperation(ctx context.Context, task *Operation
 		}
 	}()
 
-	task.Status = "running"
+	// Update task status (a task paused or stopped before it got here stays so)
+	o.mutex.Lock()
+	if task.Status != "paused" && task.Status != "cancelled" {
+		task.Status = "running"
+	}
+	o.mutex.Unlock()
 	o.emitOperationEvent(events.OperationProgress, task.TabId, task.Operation, "running", "Operation in progress")
 
 	config := o.envConfig
 
 	// Handle dry-run prefix: "dryrun:copy" -> set DryRun flag and run "copy"
 	operation := task.Operation
+	profile := task.Profile
 	if strings.HasPrefix(operation, "dryrun:") {
 		operation = strings.TrimPrefix(operation, "dryrun:")
-		task.Profile.DryRun = true
+		profile.DryRun = true
 	}
 
+	// Results are stored on the task under the lock once the operation returns
+	var result OperationTask
 	switch operation {
 	case "copy":
-		err = rclone.Copy(ctx, config, task.Profile, outStatus)
+		err = rclone.Copy(ctx, config, profile, outStatus)
 	case "move":
-		err = rclone.Move(ctx, config, task.Profile, outStatus)
+		err = rclone.Move(ctx, config, profile, outStatus)
 	case "check":
-		err = rclone.Check(ctx, config, task.Profile, outStatus)
+		result.CheckReport, err = rclone.Check(ctx, config, profile, task.CheckDownload, outStatus)
+	case "cryptcheck":
+		result.CheckReport, err = rclone.CryptCheck(ctx, config, profile, outStatus)
+	case "reencrypt":
+		result.CheckReport, err = o.reEncrypt(ctx, profile, outStatus)
+	case "dedupe":
+		if task.DedupeMode == models.DedupeList {
+			result.DedupeReport, err = rclone.ListDuplicates(ctx, profile.From, task.DedupeByHash, outStatus)
+		} else {
+			err = rclone.Dedupe(ctx, profile.From, task.DedupeMode, task.DedupeByHash, outStatus)
+		}
+	case "manifest":
+		result.ManifestFiles, err = rclone.GenerateManifest(ctx, profile.From, task.ManifestPath, task.HashType, task.CheckDownload, outStatus)
+	case "verify-manifest":
+		result.ManifestReport, err = rclone.VerifyManifest(ctx, profile.From, task.ManifestPath, task.HashType, task.CheckDownload, outStatus)
+	case "apply":
+		err = o.applyPlan(ctx, task.PlanId, outStatus)
+	case "benchmark":
+		result.BenchmarkRun, err = o.benchmark(ctx, *task.BenchmarkConfig, outStatus)
 	default:
 		err = fmt.Errorf("unknown operation: %s", operation)
 	}
+	o.mutex.Lock()
+	task.CheckReport = result.CheckReport
+	task.DedupeReport = result.DedupeReport
+	task.ManifestFiles = result.ManifestFiles
+	task.ManifestReport = result.ManifestReport
+	task.BenchmarkRun = result.BenchmarkRun
+	o.mutex.Unlock()
 	if task.TabId != "" {
 		utils.RemoveTabMapping(task.Id)
 	}
@@ -304,24 +643,74 @@ func (o *OperationService) executeOperation(ctx context.Context, task *Operation
 	// Check if cancelled
 	select {
 	case <-ctx.Done():
-		task.Status = "cancelled"
+		o.setTaskStatus(task, "cancelled")
+		taskErr = ctx.Err()
 		o.emitOperationEvent(events.OperationFailed, task.TabId, task.Operation, "cancelled", "Operation was cancelled")
 		return
 	default:
 	}
 
 	if err != nil {
-		task.Status = "failed"
+		o.setTaskStatus(task, "failed")
+		taskErr = fmt.Errorf("operation failed: %w", err)
 		o.handleOperationError(task, fmt.Sprintf("Operation failed: %v", err))
 		return
 	}
 
-	task.Status = "completed"
-	endTime := time.Now()
-	task.EndTime = &endTime
+	o.setTaskStatus(task, "completed")
 	o.emitOperationEvent(events.OperationCompleted, task.TabId, task.Operation, "completed", "Operation completed successfully")
 }
 
+// reEncrypt copies between crypt remotes and verifies the result with cryptcheck, or by
+// downloading when the underlying remote has no hashes
+func (o *OperationService) reEncrypt(ctx context.Context, profile models.Profile, outStatus chan *dto.SyncStatusDTO) (*models.CheckReport, error) {
+	if err := rclone.Copy(ctx, o.envConfig, profile, outStatus); err != nil {
+		return nil, fmt.Errorf("copy failed: %w", err)
+	}
+
+	report, err := rclone.CryptCheck(ctx, o.envConfig, profile, outStatus)
+	if errors.Is(err, rclone.ErrCryptNoHashes) {
+		report, err = rclone.Check(ctx, o.envConfig, profile, true, outStatus)
+	}
+	if err != nil {
+		return report, fmt.Errorf("verification failed: %w", err)
+	}
+	if !report.InSync {
+		return report, fmt.Errorf("verification found %d differences", report.Differences())
+	}
+	return report, nil
+}
+
+// setTaskStatus changes the status of a task under the lock; see SyncService.setTaskStatus
+func (o *OperationService) setTaskStatus(task *OperationTask, status string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	task.Status = status
+	if status == "completed" {
+		endTime := time.Now()
+		task.EndTime = &endTime
+	}
+}
+
+// taskStatus returns the current status of a task
+func (o *OperationService) taskStatus(task *OperationTask) string {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	return task.Status
+}
+
+// recordHistory persists a history entry with the final accounting stats of a finished operation
+func (o *OperationService) recordHistory(statsCtx context.Context, task *OperationTask, status string, taskErr error) {
+	if o.historyService == nil {
+		return
+	}
+
+	entry := NewHistoryEntry(statsCtx, task.Profile.Name, task.Operation, status, task.StartTime, taskErr)
+	if err := o.historyService.AddEntry(context.Background(), entry); err != nil {
+		log.Printf("Failed to record history for operation %d: %v", task.Id, err)
+	}
+}
+
 // emitOperationEvent emits an operation event
 func (o *OperationService) emitOperationEvent(eventType events.EventType, tabId, operation, status, message string) {
 	event := events.NewOperationEvent(eventType, tabId, operation, status, message)
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"desktop/backend/events"
+	"desktop/backend/utils"
+	"log"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// QueueService exposes the task queue every sync and operation goes through. Tasks start
+// by priority (manual, then scheduled, then background) while below the maximum number
+// of concurrent tasks and the caps of their remotes, set in NotificationService.
+type QueueService struct {
+	app      *application.App
+	eventBus *events.WailsEventBus
+}
+
+// NewQueueService creates a new queue service
+func NewQueueService(app *application.App) *QueueService {
+	return &QueueService{
+		app: app,
+	}
+}
+
+// SetApp sets the application reference for events
+func (q *QueueService) SetApp(app *application.App) {
+	q.app = app
+	if bus := GetSharedEventBus(); bus != nil {
+		q.eventBus = bus
+	} else {
+		q.eventBus = events.NewEventBus(app)
+	}
+}
+
+// ServiceName returns the name of the service
+func (q *QueueService) ServiceName() string {
+	return "QueueService"
+}
+
+// ServiceStartup emits the queue to the frontend whenever it changes
+func (q *QueueService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	log.Printf("QueueService starting up...")
+	utils.OnQueueChange(func(entries []utils.QueueEntry) {
+		if q.eventBus == nil {
+			return
+		}
+		if err := q.eventBus.EmitWithType(events.QueueUpdated, entries); err != nil {
+			log.Printf("Failed to emit queue event: %v", err)
+		}
+	})
+	return nil
+}
+
+// ServiceShutdown is called when the service shuts down
+func (q *QueueService) ServiceShutdown(ctx context.Context) error {
+	log.Printf("QueueService shutting down...")
+	return nil
+}
+
+// GetQueue returns the running tasks followed by the queued ones in start order
+func (q *QueueService) GetQueue(ctx context.Context) []utils.QueueEntry {
+	return utils.QueuedTasks()
+}
+
+// MoveQueuedTask moves a queued task to the given position among the queued tasks,
+// 0 being the next to start
+func (q *QueueService) MoveQueuedTask(ctx context.Context, entryId, position int) error {
+	return utils.MoveQueuedTask(entryId, position)
+}
+
+// CancelQueuedTask cancels a task that is still waiting in the queue
+func (q *QueueService) CancelQueuedTask(ctx context.Context, entryId int) error {
+	return utils.CancelQueuedTask(entryId)
+}
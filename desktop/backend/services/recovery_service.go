@@ -0,0 +1,471 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"desktop/backend/events"
+	"desktop/backend/models"
+	"desktop/backend/utils"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// shuttingDown is set when the app starts quitting, so runs cancelled by the shutdown
+// are kept as interrupted instead of being forgotten
+var shuttingDown atomic.Bool
+
+// RecoveryService persists running syncs, operations and board executions and resumes
+// the ones cut short by a crash, reboot or quit. Resuming starts the run again: files
+// already transferred are skipped, so it continues where it stopped.
+type RecoveryService struct {
+	app      *application.App
+	eventBus *events.WailsEventBus
+
+	// Dependencies injected after creation
+	syncService         *SyncService
+	operationService    *OperationService
+	boardService        *BoardService
+	flowService         *FlowService
+	notificationService *NotificationService
+}
+
+// NewRecoveryService creates a new recovery service
+func NewRecoveryService(app *application.App) *RecoveryService {
+	return &RecoveryService{
+		app: app,
+	}
+}
+
+// SetApp sets the application reference for events
+func (r *RecoveryService) SetApp(app *application.App) {
+	r.app = app
+	if bus := GetSharedEventBus(); bus != nil {
+		r.eventBus = bus
+	} else {
+		r.eventBus = events.NewEventBus(app)
+	}
+}
+
+// SetSyncService sets the sync service used to resume syncs
+func (r *RecoveryService) SetSyncService(syncService *SyncService) {
+	r.syncService = syncService
+}
+
+// SetOperationService sets the operation service used to resume operations
+func (r *RecoveryService) SetOperationService(operationService *OperationService) {
+	r.operationService = operationService
+}
+
+// SetBoardService sets the board service used to resume board executions
+func (r *RecoveryService) SetBoardService(boardService *BoardService) {
+	r.boardService = boardService
+}
+
+// SetFlowService sets the flow service used to resume flow executions
+func (r *RecoveryService) SetFlowService(flowService *FlowService) {
+	r.flowService = flowService
+}
+
+// SetNotificationService sets the notification service for settings and notifications
+func (r *RecoveryService) SetNotificationService(notificationService *NotificationService) {
+	r.notificationService = notificationService
+}
+
+// ServiceName returns the name of the service
+func (r *RecoveryService) ServiceName() string {
+	return "RecoveryService"
+}
+
+// ServiceStartup marks the runs left over from the previous session as interrupted,
+// reports them and resumes them if enabled. It must start before any service that
+// starts tasks, or their fresh runs would be taken for leftovers.
+func (r *RecoveryService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	log.Printf("RecoveryService starting up...")
+	if err := markTaskRunsInterrupted("", "The app exited before the task finished"); err != nil {
+		log.Printf("Warning: Could not mark interrupted tasks: %v", err)
+		return nil
+	}
+
+	runs, err := loadTaskRuns("interrupted")
+	if err != nil {
+		log.Printf("Warning: Could not load interrupted tasks: %v", err)
+		return nil
+	}
+	if len(runs) > 0 {
+		log.Printf("RecoveryService found %d interrupted tasks", len(runs))
+		go r.recover(runs)
+	}
+	return nil
+}
+
+// ServiceShutdown is called when the service shuts down
+func (r *RecoveryService) ServiceShutdown(ctx context.Context) error {
+	log.Printf("RecoveryService shutting down...")
+	return nil
+}
+
+// ShutdownTasks stops the running tasks when the app quits. Queued tasks are held back.
+// With a shutdown timeout set, it first waits up to that long for running tasks to
+// finish. Tasks still running or queued are marked interrupted and cancelled, to be
+// resumed on the next start.
+func (r *RecoveryService) ShutdownTasks() {
+	shuttingDown.Store(true)
+	utils.HoldTaskQueue()
+
+	if timeout := r.shutdownTimeout(); timeout > 0 && r.runningTasks() > 0 {
+		log.Printf("RecoveryService waiting up to %s for running tasks", timeout)
+		deadline := time.Now().Add(timeout)
+		for r.runningTasks() > 0 && time.Now().Before(deadline) {
+			time.Sleep(500 * time.Millisecond)
+		}
+	}
+
+	if err := markTaskRunsInterrupted("", "The app quit before the task finished"); err != nil {
+		log.Printf("Warning: Could not mark interrupted tasks: %v", err)
+	}
+
+	// context.Background() since the app context is already cancelled
+	if r.flowService != nil {
+		_ = r.flowService.ServiceShutdown(context.Background())
+	}
+	if r.boardService != nil {
+		_ = r.boardService.ServiceShutdown(context.Background())
+	}
+	if r.operationService != nil {
+		_ = r.operationService.ServiceShutdown(context.Background())
+	}
+	if r.syncService != nil {
+		_ = r.syncService.ServiceShutdown(context.Background())
+	}
+}
+
+// GetInterruptedTasks returns the runs interrupted by a crash, reboot or quit
+func (r *RecoveryService) GetInterruptedTasks(ctx context.Context) ([]models.TaskRun, error) {
+	return loadTaskRuns("interrupted")
+}
+
+// ResumeInterruptedTask starts an interrupted run again and forgets the interrupted one
+func (r *RecoveryService) ResumeInterruptedTask(ctx context.Context, runId string) error {
+	run, err := loadTaskRun(runId)
+	if err != nil {
+		return err
+	}
+	if run.Status != "interrupted" {
+		return fmt.Errorf("task '%s' is %s and cannot be resumed", runId, run.Status)
+	}
+
+	if err := r.startRun(ctx, run); err != nil {
+		return fmt.Errorf("failed to resume task '%s': %w", runId, err)
+	}
+	log.Printf("RecoveryService resumed %s %s of %q", run.Kind, run.Action, run.Profile.Name)
+	return deleteTaskRun(runId)
+}
+
+// ResumeInterruptedTasks resumes every interrupted run and returns how many started
+func (r *RecoveryService) ResumeInterruptedTasks(ctx context.Context) (int, error) {
+	runs, err := loadTaskRuns("interrupted")
+	if err != nil {
+		return 0, err
+	}
+
+	resumed := 0
+	for _, run := range runs {
+		if err := r.ResumeInterruptedTask(ctx, run.Id); err != nil {
+			log.Printf("RecoveryService: %v", err)
+			continue
+		}
+		resumed++
+	}
+	return resumed, nil
+}
+
+// DiscardInterruptedTask forgets an interrupted run without resuming it
+func (r *RecoveryService) DiscardInterruptedTask(ctx context.Context, runId string) error {
+	return deleteTaskRun(runId)
+}
+
+// recover reports the interrupted runs and resumes them if enabled
+func (r *RecoveryService) recover(runs []models.TaskRun) {
+	if r.eventBus != nil {
+		if err := r.eventBus.EmitWithType(events.TasksInterrupted, runs); err != nil {
+			log.Printf("Failed to emit interrupted tasks event: %v", err)
+		}
+	}
+	if r.notificationService == nil {
+		return
+	}
+
+	if r.notificationService.IsResumeInterrupted(context.Background()) {
+		ctx := utils.WithTaskPriority(context.Background(), utils.PriorityBackground)
+		resumed, err := r.ResumeInterruptedTasks(ctx)
+		if err != nil {
+			log.Printf("Failed to resume interrupted tasks: %v", err)
+			return
+		}
+		body := fmt.Sprintf("Resumed %d of %d interrupted tasks.", resumed, len(runs))
+		if err := r.notificationService.SendNotification(context.Background(), "Tasks Resumed", body); err != nil {
+			log.Printf("Failed to send recovery notification: %v", err)
+		}
+		return
+	}
+
+	body := fmt.Sprintf("%d tasks were interrupted and can be resumed.", len(runs))
+	if err := r.notificationService.SendNotification(context.Background(), "Tasks Interrupted", body); err != nil {
+		log.Printf("Failed to send recovery notification: %v", err)
+	}
+}
+
+// startRun starts a persisted run again with the queue priority of ctx. Tasks are started
+// from Background, not from the caller's context, as they outlive the call.
+func (r *RecoveryService) startRun(ctx context.Context, run *models.TaskRun) error {
+	taskCtx := utils.WithTaskPriority(context.Background(), utils.TaskPriorityFromContext(ctx))
+
+	switch run.Kind {
+	case "sync":
+		if r.syncService == nil {
+			return fmt.Errorf("sync service not available")
+		}
+		_, err := r.syncService.StartSync(taskCtx, run.Action, run.Profile, run.TabId)
+		return err
+	case "operation":
+		if r.operationService == nil {
+			return fmt.Errorf("operation service not available")
+		}
+		_, err := r.operationService.startTask(taskCtx, &OperationTask{
+			Operation:     run.Action,
+			Profile:       run.Profile,
+			TabId:         run.TabId,
+			CheckDownload: run.Options.CheckDownload,
+			DedupeMode:    run.Options.DedupeMode,
+			DedupeByHash:  run.Options.DedupeByHash,
+			ManifestPath:  run.Options.ManifestPath,
+			HashType:      run.Options.HashType,
+		})
+		return err
+	case "board":
+		if r.boardService == nil {
+			return fmt.Errorf("board service not available")
+		}
+		_, err := r.boardService.ExecuteBoard(taskCtx, run.BoardId)
+		return err
+	case "flow":
+		if r.flowService == nil {
+			return fmt.Errorf("flow service not available")
+		}
+		_, err := r.flowService.ExecuteFlow(taskCtx, run.FlowId)
+		return err
+	default:
+		return fmt.Errorf("unknown task kind: %s", run.Kind)
+	}
+}
+
+// runningTasks counts the tasks a graceful shutdown waits for. Queued tasks won't start
+// and paused ones wouldn't finish in time, so both are left out; so are boards, whose
+// remaining edges stay queued.
+func (r *RecoveryService) runningTasks() int {
+	count := 0
+	if r.syncService != nil {
+		tasks, _ := r.syncService.GetActiveTasks(context.Background())
+		for _, task := range tasks {
+			if !task.Pause.Paused() && !task.Ticket.Queued() {
+				count++
+			}
+		}
+	}
+	if r.operationService != nil {
+		tasks, _ := r.operationService.GetActiveTasks(context.Background())
+		for _, task := range tasks {
+			if !task.Pause.Paused() && !task.Ticket.Queued() {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// shutdownTimeout returns how long a quit waits for running tasks
+func (r *RecoveryService) shutdownTimeout() time.Duration {
+	if r.notificationService == nil {
+		return 0
+	}
+	return time.Duration(r.notificationService.GetShutdownTimeout(context.Background())) * time.Second
+}
+
+// ============ Run tracking ============
+
+// recordTaskRun persists a run as queued and returns its ID. Runs that can't be saved
+// still execute, they just can't be resumed.
+func recordTaskRun(run models.TaskRun) string {
+	run.Id = uuid.New().String()
+	run.Status = "queued"
+	run.StartedAt = time.Now()
+	if err := saveTaskRun(&run); err != nil {
+		log.Printf("Warning: Could not persist %s %s: %v", run.Kind, run.Action, err)
+		return ""
+	}
+	return run.Id
+}
+
+// startTaskRun marks a run as running once the task queue starts it
+func startTaskRun(runId string) {
+	if runId == "" {
+		return
+	}
+	db, err := GetSharedDB()
+	if err != nil {
+		return
+	}
+	if _, err := db.Exec("UPDATE task_runs SET status = 'running' WHERE id = ? AND status = 'queued'", runId); err != nil {
+		log.Printf("Warning: Could not mark task run %s running: %v", runId, err)
+	}
+}
+
+// finishTaskRun forgets the run of a finished task. A task cancelled by the app quitting
+// keeps its run, which the shutdown marked interrupted.
+func finishTaskRun(runId string, cancelled bool) {
+	if runId == "" {
+		return
+	}
+	if cancelled && shuttingDown.Load() {
+		if err := markTaskRunsInterrupted(runId, "The app quit before the task finished"); err != nil {
+			log.Printf("Warning: Could not mark task run %s interrupted: %v", runId, err)
+		}
+		return
+	}
+	if err := deleteTaskRun(runId); err != nil {
+		log.Printf("Warning: Could not remove task run %s: %v", runId, err)
+	}
+}
+
+// isResumableOperation reports whether an operation changes data and is worth resuming.
+// Checks, previews, benchmarks and plan applications (which would see their own partial
+// changes as drift) are not.
+func isResumableOperation(task *OperationTask) bool {
+	switch task.Operation {
+	case "copy", "move", "reencrypt", "manifest":
+		return true
+	case "dedupe":
+		return task.DedupeMode != models.DedupeList
+	}
+	return false
+}
+
+// ============ SQLite persistence ============
+
+// saveTaskRun inserts or replaces a task run
+func saveTaskRun(run *models.TaskRun) error {
+	db, err := GetSharedDB()
+	if err != nil {
+		return err
+	}
+
+	profileJSON, err := json.Marshal(run.Profile)
+	if err != nil {
+		return err
+	}
+	optionsJSON, err := json.Marshal(run.Options)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`INSERT OR REPLACE INTO task_runs (id, kind, action, profile, tab_id, board_id, flow_id, options,
+		status, message, started_at, interrupted_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		run.Id, run.Kind, run.Action, string(profileJSON), run.TabId, run.BoardId, run.FlowId, string(optionsJSON),
+		run.Status, run.Message, run.StartedAt.UTC().Format(time.RFC3339), timePtrToNullable(run.InterruptedAt))
+	return err
+}
+
+// markTaskRunsInterrupted marks a running or queued run, or all of them when runId is
+// empty, as interrupted
+func markTaskRunsInterrupted(runId, message string) error {
+	db, err := GetSharedDB()
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE task_runs SET status = 'interrupted', message = ?, interrupted_at = ? WHERE status IN ('queued', 'running')"
+	args := []interface{}{message, time.Now().UTC().Format(time.RFC3339)}
+	if runId != "" {
+		query += " AND id = ?"
+		args = append(args, runId)
+	}
+	_, err = db.Exec(query, args...)
+	return err
+}
+
+// deleteTaskRun removes a task run
+func deleteTaskRun(runId string) error {
+	db, err := GetSharedDB()
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec("DELETE FROM task_runs WHERE id = ?", runId); err != nil {
+		return fmt.Errorf("failed to delete task run: %w", err)
+	}
+	return nil
+}
+
+// loadTaskRun loads a single task run
+func loadTaskRun(runId string) (*models.TaskRun, error) {
+	runs, err := queryTaskRuns("WHERE id = ?", runId)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("task '%s' not found", runId)
+	}
+	return &runs[0], nil
+}
+
+// loadTaskRuns loads the task runs with the given status, oldest first
+func loadTaskRuns(status string) ([]models.TaskRun, error) {
+	return queryTaskRuns("WHERE status = ? ORDER BY started_at, rowid", status)
+}
+
+// queryTaskRuns loads the task runs matching a WHERE clause
+func queryTaskRuns(where string, args ...interface{}) ([]models.TaskRun, error) {
+	db, err := GetSharedDB()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT id, kind, action, profile, tab_id, board_id, flow_id, options, status, message,
+		started_at, interrupted_at FROM task_runs `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []models.TaskRun{}
+	for rows.Next() {
+		var run models.TaskRun
+		var profileJSON, optionsJSON, startedAt string
+		var interruptedAt sql.NullString
+		if err := rows.Scan(&run.Id, &run.Kind, &run.Action, &profileJSON, &run.TabId, &run.BoardId, &run.FlowId,
+			&optionsJSON, &run.Status, &run.Message, &startedAt, &interruptedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan task run: %w", err)
+		}
+		if err := json.Unmarshal([]byte(profileJSON), &run.Profile); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal profile of task run %s: %w", run.Id, err)
+		}
+		if err := json.Unmarshal([]byte(optionsJSON), &run.Options); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal options of task run %s: %w", run.Id, err)
+		}
+		if t, err := time.Parse(time.RFC3339, startedAt); err == nil {
+			run.StartedAt = t
+		}
+		if interruptedAt.Valid {
+			if t, err := time.Parse(time.RFC3339, interruptedAt.String); err == nil {
+				run.InterruptedAt = &t
+			}
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"desktop/backend/models"
+	"testing"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+func newTestRecoveryService(t *testing.T) *RecoveryService {
+	t.Helper()
+	// Clean DB table for test isolation
+	db, _ := GetSharedDB()
+	db.Exec("DELETE FROM task_runs")
+	shuttingDown.Store(false)
+	t.Cleanup(func() { shuttingDown.Store(false) })
+	return NewRecoveryService(nil)
+}
+
+func TestRecoveryService_FinishedRunsAreForgotten(t *testing.T) {
+	r := newTestRecoveryService(t)
+	ctx := context.Background()
+
+	completed := recordTaskRun(models.TaskRun{Kind: "sync", Action: "push", Profile: models.Profile{Name: "docs"}})
+	stopped := recordTaskRun(models.TaskRun{Kind: "sync", Action: "pull", Profile: models.Profile{Name: "photos"}})
+	if completed == "" || stopped == "" {
+		t.Fatal("expected runs to be recorded")
+	}
+
+	finishTaskRun(completed, false)
+	finishTaskRun(stopped, true) // stopped by the user, not by a quit
+
+	runs, err := loadTaskRuns("queued")
+	if err != nil {
+		t.Fatalf("loadTaskRuns failed: %v", err)
+	}
+	if len(runs) != 0 {
+		t.Errorf("expected no queued tasks, got %d", len(runs))
+	}
+	if interrupted, _ := r.GetInterruptedTasks(ctx); len(interrupted) != 0 {
+		t.Errorf("expected no interrupted tasks, got %d", len(interrupted))
+	}
+}
+
+func TestRecoveryService_InterruptedRuns(t *testing.T) {
+	r := newTestRecoveryService(t)
+	ctx := context.Background()
+
+	// A run left over from a crash is marked interrupted on startup
+	crashed := recordTaskRun(models.TaskRun{Kind: "operation", Action: "dedupe", Profile: models.Profile{Name: "remote:"},
+		Options: models.TaskOptions{DedupeMode: "newest", DedupeByHash: true}})
+	if err := r.ServiceStartup(ctx, application.ServiceOptions{}); err != nil {
+		t.Fatalf("ServiceStartup failed: %v", err)
+	}
+
+	// A run cancelled by quitting stays interrupted
+	shuttingDown.Store(true)
+	quit := recordTaskRun(models.TaskRun{Kind: "board", BoardId: "board-1"})
+	finishTaskRun(quit, true)
+
+	runs, err := r.GetInterruptedTasks(ctx)
+	if err != nil {
+		t.Fatalf("GetInterruptedTasks failed: %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("expected 2 interrupted tasks, got %d", len(runs))
+	}
+	if runs[0].Id != crashed || runs[0].InterruptedAt == nil {
+		t.Errorf("expected crashed run first with an interruption time, got %+v", runs[0])
+	}
+	if runs[0].Options.DedupeMode != "newest" || !runs[0].Options.DedupeByHash {
+		t.Errorf("expected dedupe options kept, got %+v", runs[0].Options)
+	}
+	if runs[1].Id != quit || runs[1].BoardId != "board-1" {
+		t.Errorf("expected quit board run, got %+v", runs[1])
+	}
+
+	// Resuming without the services fails and keeps the run
+	if err := r.ResumeInterruptedTask(ctx, crashed); err == nil {
+		t.Error("expected error resuming without an operation service")
+	}
+	if err := r.DiscardInterruptedTask(ctx, crashed); err != nil {
+		t.Fatalf("DiscardInterruptedTask failed: %v", err)
+	}
+	if runs, _ := r.GetInterruptedTasks(ctx); len(runs) != 1 {
+		t.Errorf("expected 1 interrupted task after discarding, got %d", len(runs))
+	}
+}
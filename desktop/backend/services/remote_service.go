@@ -3,9 +3,13 @@ package services
 import (
 	"context"
 	"desktop/backend/events"
+	"desktop/backend/models"
+	"desktop/backend/rclone"
 	"desktop/backend/validation"
 	"fmt"
 	"log"
+	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/rclone/rclone/fs"
@@ -102,11 +106,16 @@ func (r *RemoteService) GetRemotes(ctx context.Context) ([]RemoteInfo, error) {
 	remotes := make([]RemoteInfo, 0, len(rcloneRemotes))
 
 	for _, remote := range rcloneRemotes {
+		values, err := rclone.RemoteConfigValues(remote.Name)
+		if err != nil {
+			log.Printf("RemoteService: Failed to read config of remote '%s': %v", remote.Name, err)
+			values = make(map[string]string)
+		}
 		remoteInfo := RemoteInfo{
 			Name:        remote.Name,
 			Type:        remote.Type,
-			Config:      make(map[string]string),
-			Description: r.getRemoteDescription(remote.Type),
+			Config:      values,
+			Description: rclone.ProviderDescription(remote.Type),
 		}
 		remotes = append(remotes, remoteInfo)
 	}
@@ -115,6 +124,26 @@ func (r *RemoteService) GetRemotes(ctx context.Context) ([]RemoteInfo, error) {
 	return remotes, nil
 }
 
+// GetProviders returns the storage backends remotes can be created for
+func (r *RemoteService) GetProviders(ctx context.Context) ([]models.Provider, error) {
+	return rclone.Providers(), nil
+}
+
+// GetProviderOptions returns the configuration options of a backend, for building the
+// form of a new remote
+func (r *RemoteService) GetProviderOptions(ctx context.Context, remoteType string) ([]models.ProviderOption, error) {
+	return rclone.ProviderOptions(remoteType)
+}
+
+// GetRemoteConfig returns the configuration options of a remote with their current
+// values. Passwords and other sensitive values are redacted.
+func (r *RemoteService) GetRemoteConfig(ctx context.Context, name string) (*models.RemoteConfig, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return rclone.GetRemoteConfig(name)
+}
+
 // AddRemote adds a new remote configuration
 func (r *RemoteService) AddRemote(ctx context.Context, name, remoteType string, config map[string]string) error {
 	// Validate remote name
@@ -124,6 +153,9 @@ func (r *RemoteService) AddRemote(ctx context.Context, name, remoteType string,
 	if remoteType == "" {
 		return fmt.Errorf("remote type cannot be empty")
 	}
+	if err := rclone.ValidateProviderConfig(remoteType, config); err != nil {
+		return err
+	}
 
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
@@ -149,12 +181,17 @@ func (r *RemoteService) AddRemote(ctx context.Context, name, remoteType string,
 		return fmt.Errorf("failed to create remote: %w", err)
 	}
 
+	values, err := rclone.RemoteConfigValues(name)
+	if err != nil {
+		values = make(map[string]string)
+	}
+
 	// Create remote info for event
 	remoteInfo := RemoteInfo{
 		Name:        name,
 		Type:        remoteType,
-		Config:      config,
-		Description: r.getRemoteDescription(remoteType),
+		Config:      values,
+		Description: rclone.ProviderDescription(remoteType),
 	}
 
 	// Emit remote added event
@@ -164,8 +201,10 @@ func (r *RemoteService) AddRemote(ctx context.Context, name, remoteType string,
 	return nil
 }
 
-// UpdateRemote updates an existing remote configuration
-func (r *RemoteService) UpdateRemote(ctx context.Context, name string, config map[string]string) error {
+// UpdateRemote updates options of an existing remote in place. Options not in the update,
+// listed in its Keep or sent back redacted are kept and an empty value resets an option
+// to its default.
+func (r *RemoteService) UpdateRemote(ctx context.Context, name string, update models.RemoteConfigUpdate) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
@@ -184,25 +223,23 @@ func (r *RemoteService) UpdateRemote(ctx context.Context, name string, config ma
 		return fmt.Errorf("remote '%s' not found", name)
 	}
 
-	// Convert config to rc.Params
-	rcParams := rc.Params{}
-	for k, v := range config {
-		rcParams[k] = v
-	}
-
 	// Update the remote configuration
-	_, err := fsConfig.CreateRemote(ctx, name, existingType, rcParams, fsConfig.UpdateRemoteOpt{})
-	if err != nil {
+	if err := rclone.UpdateRemoteConfig(name, update); err != nil {
 		log.Printf("Failed to update remote '%s': %v", name, err)
 		return fmt.Errorf("failed to update remote: %w", err)
 	}
 
+	values, err := rclone.RemoteConfigValues(name)
+	if err != nil {
+		values = make(map[string]string)
+	}
+
 	// Create remote info for event
 	remoteInfo := RemoteInfo{
 		Name:        name,
 		Type:        existingType,
-		Config:      config,
-		Description: r.getRemoteDescription(existingType),
+		Config:      values,
+		Description: rclone.ProviderDescription(existingType),
 	}
 
 	// Emit remote updated event
@@ -254,7 +291,7 @@ func (r *RemoteService) DeleteRemote(ctx context.Context, name string) error {
 		Name:        name,
 		Type:        remoteType,
 		Config:      make(map[string]string),
-		Description: r.getRemoteDescription(remoteType),
+		Description: rclone.ProviderDescription(remoteType),
 	}
 
 	// Emit remote deleted event
@@ -294,31 +331,30 @@ func (r *RemoteService) TestRemote(ctx context.Context, name string) error {
 	return nil
 }
 
-// getRemoteDescription returns a description for a remote type
-func (r *RemoteService) getRemoteDescription(remoteType string) string {
-	descriptions := map[string]string{
-		"s3":       "Amazon S3 Compatible Storage",
-		"drive":    "Google Drive",
-		"dropbox":  "Dropbox",
-		"onedrive": "Microsoft OneDrive",
-		"gdrive":   "Google Drive",
-		"box":      "Box",
-		"mega":     "Mega",
-		"pcloud":   "pCloud",
-		"webdav":   "WebDAV",
-		"ftp":      "FTP",
-		"sftp":     "SFTP",
-		"local":    "Local Filesystem",
-		"memory":   "In Memory",
-		"crypt":    "Encrypted Remote",
-		"compress": "Compressed Remote",
-		"cache":    "Cached Remote",
+// GetRemoteCapabilities reports what a remote supports according to its backend:
+// hash types, modtime precision, server-side copy and move, listing and quota features
+func (r *RemoteService) GetRemoteCapabilities(ctx context.Context, remotePath string) (*models.RemoteCapabilities, error) {
+	return rclone.GetCapabilities(ctx, remoteRootPath(remotePath))
+}
+
+// ProbeRemote measures a remote's real behaviour by writing, reading, stating and
+// deleting a temporary object at remotePath. Unlike TestRemote, failed steps are
+// reported rather than returned as an error.
+func (r *RemoteService) ProbeRemote(ctx context.Context, remotePath string) (*models.RemoteProbe, error) {
+	probe, err := rclone.ProbeRemote(ctx, remoteRootPath(remotePath))
+	if err != nil {
+		return nil, err
 	}
+	log.Printf("Remote '%s' probed: ok=%v writable=%v", remotePath, probe.Ok, probe.Writable)
+	return probe, nil
+}
 
-	if desc, exists := descriptions[remoteType]; exists {
-		return desc
+// remoteRootPath turns a bare remote name into the path of its root
+func remoteRootPath(remotePath string) string {
+	if !strings.Contains(remotePath, ":") && !filepath.IsAbs(remotePath) {
+		return remotePath + ":"
 	}
-	return fmt.Sprintf("Remote type: %s", remoteType)
+	return remotePath
 }
 
 // emitRemoteEvent emits a remote event via unified EventBus
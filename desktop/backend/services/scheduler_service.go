@@ -4,8 +4,11 @@ import (
 	"context"
 	"desktop/backend/events"
 	"desktop/backend/models"
+	"desktop/backend/utils"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"sync"
 	"time"
 
@@ -13,6 +16,35 @@ import (
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
+const (
+	// clockCheckInterval is how often the scheduler checks the wall clock for jumps
+	clockCheckInterval = time.Minute
+	// clockJumpThreshold is how far the wall clock may drift from the check interval
+	// before the scheduler assumes the machine slept or the clock was changed
+	clockJumpThreshold = time.Minute
+	// misfireGrace is how late cron may fire a schedule before the run counts as missed
+	misfireGrace = 2 * time.Minute
+	// maxCatchUpRuns caps the missed runs replayed by the "all" misfire policy
+	maxCatchUpRuns = 10
+)
+
+// catchUpRun is a schedule to run again for the runs it missed
+type catchUpRun struct {
+	scheduleId  string
+	profileName string
+	action      string
+	runs        int
+}
+
+// scheduleRun tracks the sync started by a schedule, so that the next trigger can apply
+// the schedule's overlap policy
+type scheduleRun struct {
+	taskId int           // 0 until the sync has started
+	queued bool          // a trigger waits for this run to finish
+	stop   bool          // a trigger restarts the schedule; stop the sync once started
+	done   chan struct{} // closed when the run finishes
+}
+
 // SchedulerService manages cron-based scheduled sync operations
 type SchedulerService struct {
 	app         *application.App
@@ -20,11 +52,20 @@ type SchedulerService struct {
 	cron        *cron.Cron
 	schedules   []models.ScheduleEntry
 	cronEntries map[string]cron.EntryID // scheduleId -> cron entry ID
+	boardCrons  map[string]cron.EntryID // boardId -> cron entry ID
+	flowCrons   map[string]cron.EntryID // flowId -> cron entry ID
+	runs        map[string]*scheduleRun // scheduleId -> its running sync
 	mutex       sync.RWMutex
 	initialized bool
+	catchUps    []catchUpRun  // runs missed while the app was closed, run after startup
+	stopClock   chan struct{} // closed on shutdown to stop watching the clock
 
 	// Dependencies injected after creation
-	syncService *SyncService
+	syncService         *SyncService
+	configService       *ConfigService
+	notificationService *NotificationService
+	boardService        *BoardService
+	flowService         *FlowService
 }
 
 // NewSchedulerService creates a new scheduler service
@@ -33,7 +74,11 @@ func NewSchedulerService(app *application.App) *SchedulerService {
 		app:         app,
 		schedules:   []models.ScheduleEntry{},
 		cronEntries: make(map[string]cron.EntryID),
+		boardCrons:  make(map[string]cron.EntryID),
+		flowCrons:   make(map[string]cron.EntryID),
+		runs:        make(map[string]*scheduleRun),
 		cron:        cron.New(),
+		stopClock:   make(chan struct{}),
 	}
 }
 
@@ -52,6 +97,26 @@ func (s *SchedulerService) SetSyncService(syncService *SyncService) {
 	s.syncService = syncService
 }
 
+// SetConfigService sets the config service dependency for resolving profiles
+func (s *SchedulerService) SetConfigService(configService *ConfigService) {
+	s.configService = configService
+}
+
+// SetNotificationService sets the notification service dependency
+func (s *SchedulerService) SetNotificationService(notificationService *NotificationService) {
+	s.notificationService = notificationService
+}
+
+// SetBoardService sets the board service dependency for running scheduled boards
+func (s *SchedulerService) SetBoardService(boardService *BoardService) {
+	s.boardService = boardService
+}
+
+// SetFlowService sets the flow service dependency for running scheduled flows
+func (s *SchedulerService) SetFlowService(flowService *FlowService) {
+	s.flowService = flowService
+}
+
 // ServiceName returns the name of the service
 func (s *SchedulerService) ServiceName() string {
 	return "SchedulerService"
@@ -67,6 +132,16 @@ func (s *SchedulerService) ServiceStartup(ctx context.Context, options applicati
 			return
 		}
 		s.cron.Start()
+		s.scheduleBoards()
+		s.scheduleFlows()
+
+		s.mutex.Lock()
+		catchUps := s.catchUps
+		s.catchUps = nil
+		s.mutex.Unlock()
+		s.runCatchUps(catchUps)
+
+		s.watchClock()
 	}()
 	return nil
 }
@@ -85,6 +160,13 @@ func (s *SchedulerService) ensureInitialized() error {
 // ServiceShutdown is called when the service shuts down
 func (s *SchedulerService) ServiceShutdown(ctx context.Context) error {
 	log.Printf("SchedulerService shutting down...")
+	s.mutex.Lock()
+	select {
+	case <-s.stopClock:
+	default:
+		close(s.stopClock)
+	}
+	s.mutex.Unlock()
 	s.cron.Stop()
 	return nil
 }
@@ -106,6 +188,18 @@ func (s *SchedulerService) initialize() error {
 		s.schedules = schedules
 	}
 
+	// A schedule still running when the app last exited never got its outcome
+	for i := range s.schedules {
+		if s.schedules[i].LastResult == "running" {
+			s.schedules[i].LastResult = "cancelled"
+			s.schedules[i].LastError = "The app exited before the scheduled sync finished"
+			_ = s.saveScheduleToDB(s.schedules[i])
+		}
+	}
+
+	// Find the runs missed while the app was closed before the next runs move on
+	s.catchUps = s.findMissedRuns(time.Now())
+
 	// Register enabled schedules with cron
 	for i := range s.schedules {
 		if s.schedules[i].Enabled {
@@ -114,6 +208,13 @@ func (s *SchedulerService) initialize() error {
 			}
 		}
 	}
+	for _, catchUp := range s.catchUps {
+		for i := range s.schedules {
+			if s.schedules[i].Id == catchUp.scheduleId {
+				_ = s.saveScheduleToDB(s.schedules[i])
+			}
+		}
+	}
 
 	s.initialized = true
 	log.Printf("SchedulerService initialized with %d schedules", len(s.schedules))
@@ -132,6 +233,19 @@ func (s *SchedulerService) AddSchedule(ctx context.Context, entry models.Schedul
 	if _, err := cron.ParseStandard(entry.CronExpr); err != nil {
 		return fmt.Errorf("invalid cron expression %q: %w", entry.CronExpr, err)
 	}
+	if !IsValidSyncAction(entry.Action) {
+		return fmt.Errorf("invalid action %q", entry.Action)
+	}
+	if entry.MisfirePolicy == "" {
+		entry.MisfirePolicy = "once"
+	} else if !isValidMisfirePolicy(entry.MisfirePolicy) {
+		return fmt.Errorf("invalid misfire policy %q", entry.MisfirePolicy)
+	}
+	if entry.OverlapPolicy == "" {
+		entry.OverlapPolicy = "skip"
+	} else if !isValidOverlapPolicy(entry.OverlapPolicy) {
+		return fmt.Errorf("invalid overlap policy %q", entry.OverlapPolicy)
+	}
 
 	if entry.CreatedAt.IsZero() {
 		entry.CreatedAt = time.Now()
@@ -169,6 +283,26 @@ func (s *SchedulerService) UpdateSchedule(ctx context.Context, entry models.Sche
 	if _, err := cron.ParseStandard(entry.CronExpr); err != nil {
 		return fmt.Errorf("invalid cron expression %q: %w", entry.CronExpr, err)
 	}
+	if !IsValidSyncAction(entry.Action) {
+		return fmt.Errorf("invalid action %q", entry.Action)
+	}
+	if entry.MisfirePolicy == "" {
+		// Without a policy the schedule keeps its own; schedules from before misfire
+		// policies existed keep skipping missed runs
+		entry.MisfirePolicy = "once"
+		for _, existing := range s.schedules {
+			if existing.Id == entry.Id && existing.MisfirePolicy != "" {
+				entry.MisfirePolicy = existing.MisfirePolicy
+			}
+		}
+	} else if !isValidMisfirePolicy(entry.MisfirePolicy) {
+		return fmt.Errorf("invalid misfire policy %q", entry.MisfirePolicy)
+	}
+	if entry.OverlapPolicy == "" {
+		entry.OverlapPolicy = "skip"
+	} else if !isValidOverlapPolicy(entry.OverlapPolicy) {
+		return fmt.Errorf("invalid overlap policy %q", entry.OverlapPolicy)
+	}
 
 	found := false
 	var oldEntry models.ScheduleEntry
@@ -314,18 +448,16 @@ func (s *SchedulerService) registerCronJob(entry *models.ScheduleEntry) error {
 	profileName := entry.ProfileName
 	action := entry.Action
 
-	entryId, err := s.cron.AddFunc(entry.CronExpr, func() {
-		s.triggerSchedule(scheduleId, profileName, action)
-	})
+	schedule, err := cron.ParseStandard(entry.CronExpr)
 	if err != nil {
 		return err
 	}
+	s.cronEntries[scheduleId] = s.cron.Schedule(schedule, cron.FuncJob(func() {
+		s.triggerSchedule(scheduleId, profileName, action)
+	}))
 
-	s.cronEntries[scheduleId] = entryId
-
-	// Update next run time
-	cronEntry := s.cron.Entry(entryId)
-	nextRun := cronEntry.Next
+	// Update next run time. Computed here as cron only knows it once started.
+	nextRun := schedule.Next(time.Now())
 	entry.NextRun = &nextRun
 
 	return nil
@@ -339,8 +471,31 @@ func (s *SchedulerService) unregisterCronJob(scheduleId string) {
 	}
 }
 
-// triggerSchedule is called by cron to execute a scheduled sync
+// triggerSchedule is called by cron to execute a scheduled sync. It resolves the
+// profile when the schedule fires, waits for the sync to finish and records its outcome.
+// A trigger while the previous run still goes is handled by the overlap policy.
 func (s *SchedulerService) triggerSchedule(scheduleId, profileName, action string) {
+	// Cron fires a job it overslept as soon as its timer runs out after a wake, whatever
+	// the schedule's misfire policy; catching up applies the policy instead
+	if s.firedLate(scheduleId) {
+		log.Printf("Schedule '%s' fired late; catching up missed runs", scheduleId)
+		s.catchUpMissedRuns()
+		return
+	}
+	run := s.claimRun(scheduleId)
+	if run == nil {
+		// The previous run goes on; only move the next run on
+		entry := s.updateScheduleRun(scheduleId, func(entry *models.ScheduleEntry) {
+			s.updateNextRun(entry, time.Now())
+		})
+		s.emitScheduleEvent(events.ScheduleSkipped, scheduleId, entry)
+		return
+	}
+	defer func() {
+		if s.releaseRun(scheduleId, run) {
+			go s.triggerSchedule(scheduleId, profileName, action)
+		}
+	}()
 	log.Printf("Schedule '%s' triggered: profile=%s action=%s", scheduleId, profileName, action)
 
 	s.emitScheduleEvent(events.ScheduleTriggered, scheduleId, map[string]string{
@@ -348,71 +503,502 @@ func (s *SchedulerService) triggerSchedule(scheduleId, profileName, action strin
 		"action":       action,
 	})
 
-	// Update last run time
+	startTime := time.Now()
+	s.updateScheduleRun(scheduleId, func(entry *models.ScheduleEntry) {
+		entry.LastRun = &startTime
+		entry.LastResult = "running"
+		entry.LastError = ""
+		entry.LastDuration = ""
+		s.updateNextRun(entry, startTime)
+	})
+
+	task, err := s.startScheduledSync(profileName, action)
+	if err != nil {
+		log.Printf("Failed to trigger sync for schedule '%s': %v", scheduleId, err)
+		entry := s.updateScheduleRun(scheduleId, func(entry *models.ScheduleEntry) {
+			entry.LastResult = "failed"
+			entry.LastError = err.Error()
+			entry.LastDuration = time.Since(startTime).Round(time.Millisecond).String()
+		})
+		s.emitScheduleEvent(events.ScheduleFailed, scheduleId, entry)
+		s.notifyScheduleFailed(profileName, err)
+		return
+	}
+
+	s.setRunTask(run, task.Id)
+
+	// The sync reports its own failures; here we only record the outcome. Waiting on the
+	// task itself covers a sync stopped by a restart until it has really ended.
+	<-task.Done
+	err = task.Err
+	result := "success"
+	if errors.Is(err, context.Canceled) {
+		result = "cancelled"
+	} else if err != nil {
+		result = "failed"
+	}
+	entry := s.updateScheduleRun(scheduleId, func(entry *models.ScheduleEntry) {
+		entry.LastResult = result
+		if err != nil {
+			entry.LastError = err.Error()
+		}
+		entry.LastDuration = time.Since(startTime).Round(time.Millisecond).String()
+	})
+	log.Printf("Schedule '%s' finished: result=%s duration=%s", scheduleId, result, entry.LastDuration)
+	s.emitScheduleEvent(events.ScheduleCompleted, scheduleId, entry)
+}
+
+// startScheduledSync starts the scheduled sync with the current version of the profile
+// and returns its task
+func (s *SchedulerService) startScheduledSync(profileName, action string) (*SyncTask, error) {
+	if !IsValidSyncAction(action) {
+		return nil, fmt.Errorf("unknown action '%s'", action)
+	}
+	if s.syncService == nil || s.configService == nil {
+		return nil, fmt.Errorf("scheduler is not connected to the sync and config services")
+	}
+
+	profiles, err := s.configService.GetProfiles(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load profiles: %w", err)
+	}
+	idx := slices.IndexFunc(profiles, func(p models.Profile) bool { return p.Name == profileName })
+	if idx < 0 {
+		return nil, fmt.Errorf("profile '%s' no longer exists; it may have been renamed or deleted", profileName)
+	}
+
+	ctx := utils.WithTaskPriority(context.Background(), utils.PriorityScheduled)
+	task, _, err := s.syncService.startSyncTask(ctx, action, profiles[idx], "")
+	return task, err
+}
+
+// updateScheduleRun applies update to the schedule, saves it and returns a copy. The
+// schedule may have been deleted meanwhile, in which case nothing is saved.
+func (s *SchedulerService) updateScheduleRun(scheduleId string, update func(entry *models.ScheduleEntry)) models.ScheduleEntry {
 	s.mutex.Lock()
-	now := time.Now()
-	for i, entry := range s.schedules {
-		if entry.Id == scheduleId {
-			s.schedules[i].LastRun = &now
+	defer s.mutex.Unlock()
 
-			// Update next run from cron
-			if entryId, exists := s.cronEntries[scheduleId]; exists {
-				cronEntry := s.cron.Entry(entryId)
-				nextRun := cronEntry.Next
-				s.schedules[i].NextRun = &nextRun
+	for i := range s.schedules {
+		if s.schedules[i].Id == scheduleId {
+			update(&s.schedules[i])
+			if err := s.saveScheduleToDB(s.schedules[i]); err != nil {
+				log.Printf("Failed to save schedule '%s': %v", scheduleId, err)
 			}
+			return s.schedules[i]
+		}
+	}
+	entry := models.ScheduleEntry{Id: scheduleId}
+	update(&entry)
+	return entry
+}
 
-			// Trigger sync via SyncService if available
-			if s.syncService != nil {
-				var syncAction SyncAction
-				switch action {
-				case "pull":
-					syncAction = ActionPull
-				case "push":
-					syncAction = ActionPush
-				case "bi":
-					syncAction = ActionBi
-				case "bi-resync":
-					syncAction = ActionBiResync
-				default:
-					s.schedules[i].LastResult = "failed"
-					log.Printf("Unknown action '%s' for schedule '%s'", action, scheduleId)
-					_ = s.saveScheduleToDB(s.schedules[i])
-					s.mutex.Unlock()
-					return
-				}
+// updateNextRun sets the next run of the schedule after from. Call with the lock held.
+func (s *SchedulerService) updateNextRun(entry *models.ScheduleEntry, from time.Time) {
+	if entryId, exists := s.cronEntries[entry.Id]; exists {
+		nextRun := s.cron.Entry(entryId).Schedule.Next(from)
+		entry.NextRun = &nextRun
+	}
+}
+
+// isValidOverlapPolicy reports whether policy is a known overlap policy
+func isValidOverlapPolicy(policy string) bool {
+	return policy == "skip" || policy == "queue" || policy == "restart"
+}
+
+// claimRun registers a run of the schedule and returns it, or nil if the previous run
+// is still going and the schedule's overlap policy says not to run now: "skip" drops the
+// trigger, "queue" runs it once the previous run finishes (one at most) and "restart"
+// stops the previous run and waits for it to finish first.
+func (s *SchedulerService) claimRun(scheduleId string) *scheduleRun {
+	for {
+		s.mutex.Lock()
+		current, running := s.runs[scheduleId]
+		if !running {
+			run := &scheduleRun{done: make(chan struct{})}
+			s.runs[scheduleId] = run
+			s.mutex.Unlock()
+			return run
+		}
 
-				// We need the profile from ConfigService, but for now we use profile name
-				// The caller should have the profile data when creating the schedule
-				s.mutex.Unlock()
-
-				// Start sync (will run asynchronously)
-				_, err := s.syncService.StartSync(context.Background(), string(syncAction), models.Profile{Name: profileName}, "")
-				s.mutex.Lock()
-
-				if err != nil {
-					for j, e := range s.schedules {
-						if e.Id == scheduleId {
-							s.schedules[j].LastResult = "failed"
-							break
-						}
-					}
-					log.Printf("Failed to trigger sync for schedule '%s': %v", scheduleId, err)
-				} else {
-					for j, e := range s.schedules {
-						if e.Id == scheduleId {
-							s.schedules[j].LastResult = "success"
-							break
-						}
-					}
+		policy := ""
+		for _, entry := range s.schedules {
+			if entry.Id == scheduleId {
+				policy = entry.OverlapPolicy
+				break
+			}
+		}
+		switch policy {
+		case "queue":
+			queued := current.queued
+			current.queued = true
+			s.mutex.Unlock()
+			if queued {
+				log.Printf("Schedule '%s' is still running with a run queued; skipping", scheduleId)
+			} else {
+				log.Printf("Schedule '%s' is still running; queueing the next run", scheduleId)
+			}
+			return nil
+		case "restart":
+			current.stop = true
+			taskId := current.taskId
+			s.mutex.Unlock()
+			log.Printf("Schedule '%s' is still running; restarting it", scheduleId)
+			if taskId != 0 {
+				if err := s.syncService.StopSync(context.Background(), taskId); err != nil {
+					log.Printf("Failed to stop the previous run of schedule '%s': %v", scheduleId, err)
 				}
 			}
+			<-current.done
+		default:
+			s.mutex.Unlock()
+			log.Printf("Schedule '%s' is still running; skipping", scheduleId)
+			return nil
+		}
+	}
+}
 
-			_ = s.saveScheduleToDB(s.schedules[i])
-			break
+// setRunTask records the sync started by a run, stopping it if a restart came in while
+// it was starting
+func (s *SchedulerService) setRunTask(run *scheduleRun, taskId int) {
+	s.mutex.Lock()
+	run.taskId = taskId
+	stop := run.stop
+	s.mutex.Unlock()
+	if stop {
+		if err := s.syncService.StopSync(context.Background(), taskId); err != nil {
+			log.Printf("Failed to stop scheduled sync %d: %v", taskId, err)
 		}
 	}
+}
+
+// releaseRun forgets a finished run and reports whether a trigger was queued behind it
+func (s *SchedulerService) releaseRun(scheduleId string, run *scheduleRun) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.runs[scheduleId] == run {
+		delete(s.runs, scheduleId)
+	}
+	close(run.done)
+	return run.queued
+}
+
+// isValidMisfirePolicy reports whether policy is a known misfire policy
+func isValidMisfirePolicy(policy string) bool {
+	return policy == "skip" || policy == "once" || policy == "all"
+}
+
+// missedRuns returns how many runs of the schedule were due between its next run and
+// now, up to maxCatchUpRuns
+func missedRuns(entry models.ScheduleEntry, now time.Time) int {
+	schedule, err := cron.ParseStandard(entry.CronExpr)
+	if err != nil {
+		return 0
+	}
+	var due time.Time
+	switch {
+	case entry.NextRun != nil && !entry.NextRun.IsZero():
+		due = *entry.NextRun
+	case entry.LastRun != nil:
+		due = schedule.Next(*entry.LastRun)
+	default:
+		return 0
+	}
+
+	missed := 0
+	for !due.After(now) && missed < maxCatchUpRuns {
+		missed++
+		due = schedule.Next(due)
+	}
+	return missed
+}
+
+// findMissedRuns returns the runs to catch up for the enabled schedules whose next run
+// passed without them firing, per their misfire policy. Call with the lock held, before
+// the next runs are updated.
+func (s *SchedulerService) findMissedRuns(now time.Time) []catchUpRun {
+	var catchUps []catchUpRun
+	for _, entry := range s.schedules {
+		if !entry.Enabled {
+			continue
+		}
+		missed := missedRuns(entry, now)
+		if missed == 0 {
+			continue
+		}
+
+		runs := 1
+		switch entry.MisfirePolicy {
+		case "skip":
+			runs = 0
+		case "all":
+			runs = missed
+		}
+		log.Printf("Schedule '%s' missed %d run(s); policy=%s, catching up %d", entry.Id, missed, entry.MisfirePolicy, runs)
+		catchUps = append(catchUps, catchUpRun{
+			scheduleId:  entry.Id,
+			profileName: entry.ProfileName,
+			action:      entry.Action,
+			runs:        runs,
+		})
+	}
+	return catchUps
+}
+
+// runCatchUps runs the missed runs in the background, one after another for each schedule
+func (s *SchedulerService) runCatchUps(catchUps []catchUpRun) {
+	for _, catchUp := range catchUps {
+		if catchUp.runs == 0 {
+			continue
+		}
+		go func(catchUp catchUpRun) {
+			for i := 0; i < catchUp.runs; i++ {
+				s.triggerSchedule(catchUp.scheduleId, catchUp.profileName, catchUp.action)
+			}
+		}(catchUp)
+	}
+}
+
+// catchUpMissedRuns runs the schedules whose runs were missed while the machine slept,
+// per their misfire policy, and moves every next run past now
+func (s *SchedulerService) catchUpMissedRuns() {
+	s.mutex.Lock()
+	select {
+	case <-s.stopClock:
+		s.mutex.Unlock()
+		return // shutting down
+	default:
+	}
+	now := time.Now()
+	catchUps := s.findMissedRuns(now)
+
+	// Replacing the schedules' cron jobs makes cron compute their next runs from now,
+	// instead of firing the overdue jobs itself. Board and flow jobs share the cron and
+	// keep their own missed runs.
+	for i := range s.schedules {
+		if _, exists := s.cronEntries[s.schedules[i].Id]; !exists || !s.schedules[i].Enabled {
+			continue
+		}
+		s.unregisterCronJob(s.schedules[i].Id)
+		if err := s.registerCronJob(&s.schedules[i]); err != nil {
+			log.Printf("Failed to register cron job for schedule '%s': %v", s.schedules[i].Id, err)
+			continue
+		}
+		if err := s.saveScheduleToDB(s.schedules[i]); err != nil {
+			log.Printf("Failed to save schedule '%s': %v", s.schedules[i].Id, err)
+		}
+		s.emitScheduleEvent(events.ScheduleUpdated, s.schedules[i].Id, s.schedules[i])
+	}
 	s.mutex.Unlock()
+
+	s.runCatchUps(catchUps)
+}
+
+// firedLate reports whether cron fired the schedule well after its next run, as it does
+// for the first job due after the machine wakes up
+func (s *SchedulerService) firedLate(scheduleId string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, entry := range s.schedules {
+		if entry.Id == scheduleId {
+			return entry.NextRun != nil && time.Since(*entry.NextRun) > misfireGrace
+		}
+	}
+	return false
+}
+
+// watchClock catches up missed runs whenever the wall clock jumps, which happens when
+// the machine wakes up from sleep or the system time is changed. It returns on shutdown.
+func (s *SchedulerService) watchClock() {
+	ticker := time.NewTicker(clockCheckInterval)
+	defer ticker.Stop()
+
+	// Round(0) drops the monotonic reading, which doesn't advance during sleep everywhere
+	last := time.Now().Round(0)
+	for {
+		select {
+		case <-s.stopClock:
+			return
+		case <-ticker.C:
+		}
+		now := time.Now().Round(0)
+		if drift := now.Sub(last) - clockCheckInterval; drift > clockJumpThreshold || drift < -clockJumpThreshold {
+			log.Printf("SchedulerService detected a clock jump of %s; catching up missed runs", drift.Round(time.Second))
+			s.catchUpMissedRuns()
+		}
+		last = now
+	}
+}
+
+// notifyScheduleFailed tells the user a scheduled sync could not start
+func (s *SchedulerService) notifyScheduleFailed(profileName string, err error) {
+	if s.notificationService == nil {
+		return
+	}
+	body := fmt.Sprintf("Scheduled sync of \"%s\" did not run: %v", profileName, err)
+	if err := s.notificationService.SendNotification(context.Background(), "Scheduled Sync Failed", body); err != nil {
+		log.Printf("Failed to send schedule notification: %v", err)
+	}
+}
+
+// ScheduleBoard registers, replaces or removes the cron job of a board to match its
+// schedule settings and returns its next run, or nil if the board isn't scheduled.
+// BoardService calls it whenever a board is saved.
+func (s *SchedulerService) ScheduleBoard(board models.Board) *time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.unregisterBoardCronJob(board.Id)
+	if !board.ScheduleEnabled {
+		return nil
+	}
+	schedule, err := cron.ParseStandard(board.CronExpr)
+	if err != nil {
+		log.Printf("Invalid cron expression for board '%s': %v", board.Id, err)
+		return nil
+	}
+
+	boardId := board.Id
+	s.boardCrons[boardId] = s.cron.Schedule(schedule, cron.FuncJob(func() {
+		s.triggerBoard(boardId, schedule)
+	}))
+	nextRun := schedule.Next(time.Now())
+	return &nextRun
+}
+
+// UnscheduleBoard removes the cron job of a board
+func (s *SchedulerService) UnscheduleBoard(boardId string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.unregisterBoardCronJob(boardId)
+}
+
+// unregisterBoardCronJob removes the cron job of a board. Call with the lock held.
+func (s *SchedulerService) unregisterBoardCronJob(boardId string) {
+	if entryId, exists := s.boardCrons[boardId]; exists {
+		s.cron.Remove(entryId)
+		delete(s.boardCrons, boardId)
+	}
+}
+
+// scheduleBoards registers the cron jobs of the saved boards on startup
+func (s *SchedulerService) scheduleBoards() {
+	if s.boardService == nil {
+		return
+	}
+	boards, err := s.boardService.GetBoards(context.Background())
+	if err != nil {
+		log.Printf("Warning: Could not load boards for scheduling: %v", err)
+		return
+	}
+
+	scheduled := 0
+	for _, board := range boards {
+		nextRun := s.ScheduleBoard(board)
+		if nextRun != nil {
+			scheduled++
+		}
+		if nextRun == nil && board.LastResult != "running" {
+			continue
+		}
+		s.boardService.updateBoardRun(board.Id, func(board *models.Board) {
+			board.NextRun = nextRun
+			// A board still running when the app last exited never got its outcome
+			if board.LastResult == "running" {
+				board.LastResult = "cancelled"
+			}
+		})
+	}
+	log.Printf("SchedulerService scheduled %d boards", scheduled)
+}
+
+// triggerBoard is called by cron to execute a scheduled board. It waits for the
+// execution to finish and records its outcome on the board.
+func (s *SchedulerService) triggerBoard(boardId string, schedule cron.Schedule) {
+	log.Printf("Board '%s' schedule triggered", boardId)
+
+	startTime := time.Now()
+	nextRun := schedule.Next(startTime)
+	s.boardService.updateBoardRun(boardId, func(board *models.Board) {
+		board.NextRun = &nextRun
+	})
+	if s.boardService.isFlowRunning(boardId) {
+		// The run in progress goes on; only the next run moved on
+		log.Printf("Board '%s' is still running; skipping", boardId)
+		return
+	}
+
+	// The board records the last run and its result of every execution itself
+	ctx := utils.WithTaskPriority(context.Background(), utils.PriorityScheduled)
+	flow, err := s.boardService.startFlow(ctx, boardId)
+	if err != nil {
+		log.Printf("Failed to trigger board '%s': %v", boardId, err)
+		s.boardService.updateBoardRun(boardId, func(board *models.Board) {
+			board.LastRun = &startTime
+			board.LastResult = "failed"
+		})
+		name := boardId
+		if board, err := s.boardService.GetBoard(context.Background(), boardId); err == nil {
+			name = board.Name
+		}
+		s.notifyScheduleFailed(name, err)
+		return
+	}
+
+	status, _ := s.boardService.waitForFlow(context.Background(), flow)
+	log.Printf("Board '%s' scheduled run finished: result=%s duration=%s", boardId, boardRunResult(status), time.Since(startTime).Round(time.Millisecond))
+}
+
+// ScheduleFlows replaces the cron jobs of flows with those of the given flows.
+// FlowService calls it whenever the flows are saved.
+func (s *SchedulerService) ScheduleFlows(flows []models.Flow) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for flowId, entryId := range s.flowCrons {
+		s.cron.Remove(entryId)
+		delete(s.flowCrons, flowId)
+	}
+	for _, flow := range flows {
+		if !flow.ScheduleEnabled || flow.CronExpr == "" {
+			continue
+		}
+		// Flows are saved as they are edited, so a bad expression is skipped, not refused
+		schedule, err := cron.ParseStandard(flow.CronExpr)
+		if err != nil {
+			log.Printf("Invalid cron expression for flow '%s': %v", flow.Id, err)
+			continue
+		}
+		flowId := flow.Id
+		s.flowCrons[flowId] = s.cron.Schedule(schedule, cron.FuncJob(func() {
+			s.triggerFlow(flowId)
+		}))
+	}
+}
+
+// scheduleFlows registers the cron jobs of the saved flows on startup
+func (s *SchedulerService) scheduleFlows() {
+	if s.flowService == nil {
+		return
+	}
+	flows, err := s.flowService.GetFlows(context.Background())
+	if err != nil {
+		log.Printf("Warning: Could not load flows for scheduling: %v", err)
+		return
+	}
+	s.ScheduleFlows(flows)
+	log.Printf("SchedulerService scheduled %d flows", len(s.flowCrons))
+}
+
+// triggerFlow is called by cron to execute a scheduled flow
+func (s *SchedulerService) triggerFlow(flowId string) {
+	log.Printf("Flow '%s' schedule triggered", flowId)
+
+	ctx := utils.WithTaskPriority(context.Background(), utils.PriorityScheduled)
+	if _, err := s.flowService.ExecuteFlow(ctx, flowId); err != nil {
+		log.Printf("Failed to trigger flow '%s': %v", flowId, err)
+		s.flowService.notifyFlowNotStarted(flowId, err)
+	}
 }
 
 // loadSchedulesFromDB loads all schedules from SQLite
@@ -422,7 +1008,7 @@ func (s *SchedulerService) loadSchedulesFromDB() ([]models.ScheduleEntry, error)
 		return nil, err
 	}
 
-	rows, err := db.Query("SELECT id, profile_name, action, cron_expr, enabled, last_run, next_run, last_result, created_at FROM schedules")
+	rows, err := db.Query("SELECT id, profile_name, action, cron_expr, enabled, last_run, next_run, last_result, last_error, last_duration, misfire_policy, overlap_policy, created_at FROM schedules")
 	if err != nil {
 		return nil, err
 	}
@@ -434,7 +1020,7 @@ func (s *SchedulerService) loadSchedulesFromDB() ([]models.ScheduleEntry, error)
 		var enabled int
 		var lastRun, nextRun *string
 		var createdAt string
-		if err := rows.Scan(&e.Id, &e.ProfileName, &e.Action, &e.CronExpr, &enabled, &lastRun, &nextRun, &e.LastResult, &createdAt); err != nil {
+		if err := rows.Scan(&e.Id, &e.ProfileName, &e.Action, &e.CronExpr, &enabled, &lastRun, &nextRun, &e.LastResult, &e.LastError, &e.LastDuration, &e.MisfirePolicy, &e.OverlapPolicy, &createdAt); err != nil {
 			return nil, fmt.Errorf("failed to scan schedule: %w", err)
 		}
 		e.Enabled = enabled != 0
@@ -465,11 +1051,11 @@ func (s *SchedulerService) saveScheduleToDB(e models.ScheduleEntry) error {
 	if err != nil {
 		return err
 	}
-	_, err = db.Exec(`INSERT OR REPLACE INTO schedules (id, profile_name, action, cron_expr, enabled, last_run, next_run, last_result, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+	_, err = db.Exec(`INSERT OR REPLACE INTO schedules (id, profile_name, action, cron_expr, enabled, last_run, next_run, last_result, last_error, last_duration, misfire_policy, overlap_policy, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		e.Id, e.ProfileName, e.Action, e.CronExpr, boolToInt(e.Enabled),
 		timePtrToNullable(e.LastRun), timePtrToNullable(e.NextRun),
-		e.LastResult, e.CreatedAt.UTC().Format(time.RFC3339))
+		e.LastResult, e.LastError, e.LastDuration, e.MisfirePolicy, e.OverlapPolicy, e.CreatedAt.UTC().Format(time.RFC3339))
 	return err
 }
 
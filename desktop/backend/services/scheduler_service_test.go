@@ -17,7 +17,11 @@ func newTestSchedulerService(t *testing.T) *SchedulerService {
 	return &SchedulerService{
 		schedules:   []models.ScheduleEntry{},
 		cronEntries: make(map[string]cron.EntryID),
+		boardCrons:  make(map[string]cron.EntryID),
+		flowCrons:   make(map[string]cron.EntryID),
+		runs:        make(map[string]*scheduleRun),
 		cron:        cron.New(),
+		stopClock:   make(chan struct{}),
 		initialized: true,
 	}
 }
@@ -72,6 +76,33 @@ func TestSchedulerService_AddSchedule_InvalidCron(t *testing.T) {
 	}
 }
 
+func TestSchedulerService_AddSchedule_Actions(t *testing.T) {
+	s := newTestSchedulerService(t)
+	ctx := context.Background()
+
+	for _, action := range []string{"copy", "move", "check"} {
+		entry := models.ScheduleEntry{
+			Id:          "sched-" + action,
+			ProfileName: "test",
+			Action:      action,
+			CronExpr:    "0 * * * *",
+		}
+		if err := s.AddSchedule(ctx, entry); err != nil {
+			t.Errorf("AddSchedule with action %q failed: %v", action, err)
+		}
+	}
+
+	entry := models.ScheduleEntry{
+		Id:          "sched-bad-action",
+		ProfileName: "test",
+		Action:      "mirror",
+		CronExpr:    "0 * * * *",
+	}
+	if err := s.AddSchedule(ctx, entry); err == nil {
+		t.Error("expected error for unknown action")
+	}
+}
+
 func TestSchedulerService_DeleteSchedule(t *testing.T) {
 	s := newTestSchedulerService(t)
 	s.cron.Start()
@@ -232,3 +263,241 @@ func TestSchedulerService_Persistence(t *testing.T) {
 		t.Error("expected to find 'persist-sched' after loading from DB")
 	}
 }
+
+func TestSchedulerService_TriggerMissingProfile(t *testing.T) {
+	s := newTestSchedulerService(t)
+	s.SetSyncService(NewSyncService(nil))
+	s.SetConfigService(&ConfigService{configInfo: &models.ConfigInfo{}, initialized: true})
+	ctx := context.Background()
+
+	entry := models.ScheduleEntry{
+		Id:          "renamed-sched",
+		ProfileName: "renamed-profile",
+		Action:      "push",
+		CronExpr:    "0 0 * * *",
+		CreatedAt:   time.Now(),
+	}
+	if err := s.AddSchedule(ctx, entry); err != nil {
+		t.Fatalf("AddSchedule failed: %v", err)
+	}
+
+	s.triggerSchedule(entry.Id, entry.ProfileName, entry.Action)
+
+	loaded, err := s.loadSchedulesFromDB()
+	if err != nil {
+		t.Fatalf("loadSchedulesFromDB failed: %v", err)
+	}
+	if len(loaded) != 1 {
+		t.Fatalf("expected 1 schedule, got %d", len(loaded))
+	}
+	if loaded[0].LastResult != "failed" || loaded[0].LastError == "" || loaded[0].LastRun == nil {
+		t.Errorf("expected a recorded failure for the missing profile, got %+v", loaded[0])
+	}
+}
+
+func TestSchedulerService_ScheduleBoard(t *testing.T) {
+	s := newTestSchedulerService(t)
+	b := newTestBoardService(t)
+	s.SetBoardService(b)
+	b.SetSchedulerService(s)
+	ctx := context.Background()
+
+	board := makeTestBoard("sched-board", "Scheduled Board")
+	board.ScheduleEnabled = true
+	board.CronExpr = "0 */6 * * *"
+	if err := b.AddBoard(ctx, board); err != nil {
+		t.Fatalf("AddBoard failed: %v", err)
+	}
+	saved, _ := b.GetBoard(ctx, board.Id)
+	if saved.NextRun == nil || len(s.boardCrons) != 1 {
+		t.Fatalf("expected the board to be scheduled, got next run %v and %d jobs", saved.NextRun, len(s.boardCrons))
+	}
+
+	// The editor doesn't overwrite the run state kept by the scheduler
+	b.updateBoardRun(board.Id, func(board *models.Board) { board.LastResult = "success" })
+	board.ScheduleEnabled = false
+	board.LastResult = ""
+	if err := b.UpdateBoard(ctx, board); err != nil {
+		t.Fatalf("UpdateBoard failed: %v", err)
+	}
+	saved, _ = b.GetBoard(ctx, board.Id)
+	if saved.NextRun != nil || len(s.boardCrons) != 0 {
+		t.Errorf("expected the board to be unscheduled, got next run %v and %d jobs", saved.NextRun, len(s.boardCrons))
+	}
+	if saved.LastResult != "success" {
+		t.Errorf("expected last result kept, got %q", saved.LastResult)
+	}
+
+	board.ScheduleEnabled = true
+	board.CronExpr = "not a cron"
+	if err := b.UpdateBoard(ctx, board); err == nil {
+		t.Error("expected error for an invalid cron expression")
+	}
+}
+
+func TestSchedulerService_FindMissedRuns(t *testing.T) {
+	s := newTestSchedulerService(t)
+
+	// A daily 2am schedule last due three days ago
+	now := time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)
+	nextRun := time.Date(2024, 1, 13, 2, 0, 0, 0, time.Local)
+	upcoming := time.Date(2024, 1, 16, 2, 0, 0, 0, time.Local)
+	for _, policy := range []string{"skip", "once", "all"} {
+		s.schedules = append(s.schedules, models.ScheduleEntry{
+			Id: policy, CronExpr: "0 2 * * *", Enabled: true, NextRun: &nextRun, MisfirePolicy: policy,
+		})
+	}
+	s.schedules = append(s.schedules,
+		models.ScheduleEntry{Id: "disabled", CronExpr: "0 2 * * *", NextRun: &nextRun},
+		models.ScheduleEntry{Id: "on-time", CronExpr: "0 2 * * *", Enabled: true, NextRun: &upcoming},
+	)
+
+	runs := map[string]int{}
+	for _, catchUp := range s.findMissedRuns(now) {
+		runs[catchUp.scheduleId] = catchUp.runs
+	}
+	expected := map[string]int{"skip": 0, "once": 1, "all": 3}
+	if len(runs) != len(expected) {
+		t.Fatalf("expected catch-ups for %v, got %v", expected, runs)
+	}
+	for id, want := range expected {
+		if runs[id] != want {
+			t.Errorf("expected %d catch-up run(s) for policy %q, got %d", want, id, runs[id])
+		}
+	}
+
+	// Every-minute schedules missed for a week replay at most maxCatchUpRuns
+	if got := missedRuns(models.ScheduleEntry{CronExpr: "* * * * *", NextRun: &nextRun}, now); got != maxCatchUpRuns {
+		t.Errorf("expected missed runs capped at %d, got %d", maxCatchUpRuns, got)
+	}
+}
+
+func TestSchedulerService_AddSchedule_MisfirePolicy(t *testing.T) {
+	s := newTestSchedulerService(t)
+	ctx := context.Background()
+
+	entry := models.ScheduleEntry{Id: "sched-1", ProfileName: "test", Action: "push", CronExpr: "0 2 * * *"}
+	if err := s.AddSchedule(ctx, entry); err != nil {
+		t.Fatalf("AddSchedule failed: %v", err)
+	}
+	entry.Id = "sched-2"
+	entry.MisfirePolicy = "sometimes"
+	if err := s.AddSchedule(ctx, entry); err == nil {
+		t.Error("expected error for an unknown misfire policy")
+	}
+
+	schedules, _ := s.GetSchedules(ctx)
+	if len(schedules) != 1 || schedules[0].MisfirePolicy != "once" {
+		t.Errorf("expected one schedule defaulting to the \"once\" policy, got %+v", schedules)
+	}
+
+	// An update without a policy keeps the schedule's own, as migrated schedules have "skip"
+	s.schedules[0].MisfirePolicy = "skip"
+	entry.Id = "sched-1"
+	entry.MisfirePolicy = ""
+	if err := s.UpdateSchedule(ctx, entry); err != nil {
+		t.Fatalf("UpdateSchedule failed: %v", err)
+	}
+	schedules, _ = s.GetSchedules(ctx)
+	if schedules[0].MisfirePolicy != "skip" {
+		t.Errorf("expected the \"skip\" policy kept, got %q", schedules[0].MisfirePolicy)
+	}
+}
+
+// hourFromSchedule runs an hour after the time cron computes it from
+type hourFromSchedule struct{}
+
+func (hourFromSchedule) Next(t time.Time) time.Time { return t.Add(time.Hour) }
+
+func TestSchedulerService_CatchUpKeepsBoardJobs(t *testing.T) {
+	s := newTestSchedulerService(t)
+	s.cron.Start()
+	defer s.cron.Stop()
+	ctx := context.Background()
+
+	s.boardCrons["board-1"] = s.cron.Schedule(hourFromSchedule{}, cron.FuncJob(func() {}))
+	boardNext := s.cron.Entry(s.boardCrons["board-1"]).Next
+
+	entry := models.ScheduleEntry{Id: "sched-1", ProfileName: "test", Action: "push", CronExpr: "0 2 * * *", Enabled: true}
+	if err := s.AddSchedule(ctx, entry); err != nil {
+		t.Fatalf("AddSchedule failed: %v", err)
+	}
+	scheduleEntry := s.cronEntries["sched-1"]
+
+	s.catchUpMissedRuns()
+
+	if next := s.cron.Entry(s.boardCrons["board-1"]).Next; !next.Equal(boardNext) {
+		t.Errorf("expected the board job's next run kept at %v, got %v", boardNext, next)
+	}
+	if s.cronEntries["sched-1"] == scheduleEntry || !s.cron.Entry(s.cronEntries["sched-1"]).Valid() {
+		t.Error("expected the schedule's cron job replaced")
+	}
+}
+
+func TestSchedulerService_OverlapPolicy(t *testing.T) {
+	s := newTestSchedulerService(t)
+	for _, policy := range []string{"skip", "queue", "restart"} {
+		s.schedules = append(s.schedules, models.ScheduleEntry{Id: policy, OverlapPolicy: policy})
+	}
+
+	// skip drops the trigger while the schedule runs
+	first := s.claimRun("skip")
+	if first == nil || s.claimRun("skip") != nil {
+		t.Fatal("expected the second skip trigger to be dropped")
+	}
+	if s.releaseRun("skip", first) {
+		t.Error("expected nothing queued behind a skip run")
+	}
+
+	// queue keeps one trigger for when the run finishes
+	first = s.claimRun("queue")
+	if s.claimRun("queue") != nil || s.claimRun("queue") != nil {
+		t.Fatal("expected queue triggers to wait for the running one")
+	}
+	if !s.releaseRun("queue", first) {
+		t.Error("expected a trigger queued behind the run")
+	}
+
+	// restart stops the running sync and starts once it has finished
+	first = s.claimRun("restart")
+	claimed := make(chan *scheduleRun)
+	go func() { claimed <- s.claimRun("restart") }()
+	select {
+	case <-claimed:
+		t.Fatal("expected the restart to wait for the running sync to finish")
+	case <-time.After(50 * time.Millisecond):
+	}
+	s.mutex.RLock()
+	stop := first.stop
+	s.mutex.RUnlock()
+	if !stop {
+		t.Error("expected the running sync to be stopped")
+	}
+	s.releaseRun("restart", first)
+	if second := <-claimed; second == nil || second == first {
+		t.Error("expected a new run once the previous one finished")
+	}
+}
+
+func TestSchedulerService_AddSchedule_OverlapPolicy(t *testing.T) {
+	s := newTestSchedulerService(t)
+	ctx := context.Background()
+
+	entry := models.ScheduleEntry{Id: "sched-1", ProfileName: "test", Action: "bi", CronExpr: "*/5 * * * *", OverlapPolicy: "restart"}
+	if err := s.AddSchedule(ctx, entry); err != nil {
+		t.Fatalf("AddSchedule failed: %v", err)
+	}
+	entry.Id = "sched-2"
+	entry.OverlapPolicy = "parallel"
+	if err := s.AddSchedule(ctx, entry); err == nil {
+		t.Error("expected error for an unknown overlap policy")
+	}
+
+	loaded, err := s.loadSchedulesFromDB()
+	if err != nil {
+		t.Fatalf("loadSchedulesFromDB failed: %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].OverlapPolicy != "restart" {
+		t.Errorf("expected the overlap policy saved, got %+v", loaded)
+	}
+}
@@ -84,8 +84,31 @@ const (
 	ActionPush     SyncAction = "push"
 	ActionBi       SyncAction = "bi"
 	ActionBiResync SyncAction = "bi-resync"
+	ActionCopy     SyncAction = "copy"
+	ActionMove     SyncAction = "move"
+	ActionCheck    SyncAction = "check"
 )
 
+// IsValidSyncAction reports whether action is one SyncService can execute
+func IsValidSyncAction(action string) bool {
+	switch SyncAction(action) {
+	case ActionPull, ActionPush, ActionBi, ActionBiResync, ActionCopy, ActionMove, ActionCheck:
+		return true
+	}
+	return false
+}
+
+// journalRun returns the kind of transfer journal run of action
+func journalRun(action SyncAction) rclone.JournalRun {
+	switch action {
+	case ActionCopy:
+		return rclone.JournalCopy
+	case ActionMove:
+		return rclone.JournalMove
+	}
+	return rclone.JournalSync
+}
+
 // SyncResult represents the result of a sync operation
 type SyncResult struct {
 	TaskId    int        `json:"taskId"`
@@ -96,13 +119,25 @@ type SyncResult struct {
 	EndTime   *time.Time `json:"endTime,omitempty"`
 }
 
+// maxFinishedTasks is how many recent task IDs keep their result for WaitForTask
+const maxFinishedTasks = 100
+
+// isOrchestratedTab reports whether a task belongs to a board edge or a flow operation,
+// which are tracked, resumed and notified about by their board or flow
+func isOrchestratedTab(tabId string) bool {
+	return strings.HasPrefix(tabId, "board-") || strings.HasPrefix(tabId, "flow-")
+}
+
 // SyncService handles all sync operations using the rclone Go library
 type SyncService struct {
 	app                 *application.App
 	eventBus            *events.WailsEventBus
 	logService          *LogService
 	notificationService *NotificationService
+	historyService      *HistoryService
 	activeTasks         map[int]*SyncTask
+	finishedTasks       map[int]error // results of recently finished tasks, for WaitForTask
+	finishedOrder       []int         // IDs in finishedTasks, oldest first
 	taskCounter         int
 	mutex               sync.RWMutex
 	envConfig           beConfig.Config
@@ -117,16 +152,23 @@ type SyncTask struct {
 	Cancel    context.CancelFunc
 	StartTime time.Time
 	EndTime   *time.Time
-	Status    string
-	Done      chan error // closed with result when task completes
+	Status    string             // guarded by SyncService.mutex
+	Done      chan struct{}      // closed when the task completes
+	Err       error              // result of the task, set before Done is closed
+	Pause     *rclone.PauseGate  // holds the task while paused
+	Ticket    *utils.QueueTicket // the task's place in the task queue
+	RunId     string             // persisted run, resumed if the app quits first; empty if not resumable
+
+	CheckReport *models.CheckReport // result of a "check" task
 }
 
 // NewSyncService creates a new sync service
 func NewSyncService(app *application.App) *SyncService {
 	return &SyncService{
-		app:         app,
-		activeTasks: make(map[int]*SyncTask),
-		taskCounter: 0,
+		app:           app,
+		activeTasks:   make(map[int]*SyncTask),
+		finishedTasks: make(map[int]error),
+		taskCounter:   0,
 	}
 }
 
@@ -157,6 +199,11 @@ func (s *SyncService) SetNotificationService(notificationService *NotificationSe
 	s.notificationService = notificationService
 }
 
+// SetHistoryService sets the history service used to record finished tasks
+func (s *SyncService) SetHistoryService(historyService *HistoryService) {
+	s.historyService = historyService
+}
+
 // ServiceName returns the name of the service
 func (s *SyncService) ServiceName() string {
 	return "SyncService"
@@ -187,6 +234,13 @@ func (s *SyncService) ServiceShutdown(ctx context.Context) error {
 
 // StartSync starts a sync operation with context support
 func (s *SyncService) StartSync(ctx context.Context, action string, profile models.Profile, tabId string) (*SyncResult, error) {
+	_, result, err := s.startSyncTask(ctx, action, profile, tabId)
+	return result, err
+}
+
+// startSyncTask starts a sync operation and returns its task, whose Done channel callers
+// inside the backend can wait on without looking the task up again
+func (s *SyncService) startSyncTask(ctx context.Context, action string, profile models.Profile, tabId string) (*SyncTask, *SyncResult, error) {
 	log.Printf("[SyncService] StartSync called: action=%s tabId=%s from=%s to=%s", action, tabId, profile.From, profile.To)
 
 	s.mutex.Lock()
@@ -196,7 +250,7 @@ func (s *SyncService) StartSync(ctx context.Context, action string, profile mode
 	select {
 	case <-ctx.Done():
 		log.Printf("[SyncService] StartSync: context already cancelled")
-		return nil, ctx.Err()
+		return nil, nil, ctx.Err()
 	default:
 	}
 
@@ -216,7 +270,15 @@ func (s *SyncService) StartSync(ctx context.Context, action string, profile mode
 		Cancel:    cancel,
 		StartTime: time.Now(),
 		Status:    "starting",
-		Done:      make(chan error, 1),
+		Done:      make(chan struct{}),
+		Pause:     rclone.NewPauseGate(),
+	}
+
+	// Persist the run so it can be resumed if the app quits first. Board edges and flow
+	// operations are resumed with their board or flow; checks and dry runs change
+	// nothing worth resuming.
+	if !isOrchestratedTab(tabId) && task.Action != ActionCheck && !profile.DryRun {
+		task.RunId = recordTaskRun(models.TaskRun{Kind: "sync", Action: action, Profile: profile, TabId: tabId})
 	}
 
 	s.activeTasks[taskId] = task
@@ -224,21 +286,38 @@ func (s *SyncService) StartSync(ctx context.Context, action string, profile mode
 	// Emit sync started event
 	s.emitSyncEvent(events.SyncStarted, tabId, action, "starting", "Sync operation started")
 
+	// Queue the task with the priority of ctx; it waits for a free slot in executeSyncTask
+	task.Ticket = utils.SubmitTask(ctx, utils.QueueEntry{
+		Kind:    "sync",
+		TaskId:  taskId,
+		TabId:   tabId,
+		Name:    profile.Name,
+		Action:  action,
+		Remotes: rclone.TaskRemotes(profile.From, profile.To),
+	}, cancel)
+	status := "started"
+	if task.Ticket.Queued() {
+		status = "queued"
+		task.Status = "queued"
+		s.emitSyncEvent(events.SyncQueued, tabId, action, "queued", "Sync operation queued")
+	}
+
 	// Start sync operation in goroutine
 	go s.executeSyncTask(taskCtx, task)
 
 	result := &SyncResult{
 		TaskId:    taskId,
 		Action:    action,
-		Status:    "started",
+		Status:    status,
 		Message:   "Sync operation initiated",
 		StartTime: task.StartTime,
 	}
 
-	return result, nil
+	return task, result, nil
 }
 
-// StopSync stops a running sync operation
+// StopSync stops a running sync operation. The task stays active, with status
+// "cancelled", until it has wound down, so WaitForTask reports its real end.
 func (s *SyncService) StopSync(ctx context.Context, taskId int) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -247,6 +326,9 @@ func (s *SyncService) StopSync(ctx context.Context, taskId int) error {
 	if !exists {
 		return fmt.Errorf("task %d not found", taskId)
 	}
+	if task.Status == "cancelled" {
+		return nil
+	}
 
 	// Cancel the task context (this cancels the rclone Go library operation)
 	if task.Cancel != nil {
@@ -259,39 +341,119 @@ func (s *SyncService) StopSync(ctx context.Context, taskId int) error {
 	// Emit cancelled event
 	s.emitSyncEvent(events.SyncCancelled, task.TabId, string(task.Action), "cancelled", "Sync operation cancelled")
 
-	// Remove from active tasks
-	delete(s.activeTasks, taskId)
+	return nil
+}
+
+// PauseSync pauses a running sync task. Transfers in flight finish their current file,
+// then the task holds without using bandwidth until resumed, keeping its context,
+// accounting and listings, so a long bisync continues where it stopped.
+func (s *SyncService) PauseSync(ctx context.Context, taskId int) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	task, exists := s.activeTasks[taskId]
+	if !exists {
+		return fmt.Errorf("task %d not found", taskId)
+	}
+	if task.Status == "cancelled" {
+		return fmt.Errorf("task %d is being cancelled", taskId)
+	}
+	if !task.Pause.Pause() {
+		return fmt.Errorf("task %d is already paused", taskId)
+	}
+	task.Status = "paused"
+
+	s.emitSyncEvent(events.SyncPaused, task.TabId, string(task.Action), "paused", "Sync operation paused")
+	return nil
+}
+
+// ResumeSync resumes a paused sync task
+func (s *SyncService) ResumeSync(ctx context.Context, taskId int) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	task, exists := s.activeTasks[taskId]
+	if !exists {
+		return fmt.Errorf("task %d not found", taskId)
+	}
+	if task.Status == "cancelled" {
+		return fmt.Errorf("task %d is being cancelled", taskId)
+	}
+	if !task.Pause.Resume() {
+		return fmt.Errorf("task %d is not paused", taskId)
+	}
+	task.Status = "running"
+
+	s.emitSyncEvent(events.SyncResumed, task.TabId, string(task.Action), "running", "Sync operation resumed")
+	return nil
+}
+
+// SetTaskBandwidth caps the bandwidth of a running sync task until it finishes, e.g.
+// "1M", "10M:2M" (upload:download) or "off". An empty limit restores the profile's.
+// rclone shares one token bucket between tasks, so the cap throttles every running task
+// and the most restrictive cap among them is the one in force. Returns that shared
+// limit, empty when unlimited.
+func (s *SyncService) SetTaskBandwidth(ctx context.Context, taskId int, limit string) (string, error) {
+	s.mutex.RLock()
+	_, exists := s.activeTasks[taskId]
+	s.mutex.RUnlock()
+
+	if !exists {
+		return "", fmt.Errorf("task %d not found", taskId)
+	}
+	current, err := rclone.SetTaskBandwidth(taskId, limit)
+	if err != nil {
+		return "", err
+	}
+	log.Printf("[SyncService] Bandwidth of task %d capped at %q, shared limit now %q", taskId, limit, current)
+	return current, nil
+}
 
+// SetRunningBandwidth changes the bandwidth limit of all running tasks, syncs and
+// operations alike, until they finish, e.g. "1M" to throttle everything now. An empty
+// limit restores their own limits.
+func (s *SyncService) SetRunningBandwidth(ctx context.Context, limit string) error {
+	if err := rclone.SetRunningBandwidth(limit); err != nil {
+		return err
+	}
+	log.Printf("[SyncService] Bandwidth of running tasks set to %q", limit)
 	return nil
 }
 
-// GetActiveTasks returns all currently active sync tasks
+// GetActiveTasks returns a snapshot of all currently active sync tasks
 func (s *SyncService) GetActiveTasks(ctx context.Context) (map[int]*SyncTask, error) {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
-	// Create a copy to avoid race conditions
+	// Copy the tasks, as their status keeps changing under the lock
 	tasks := make(map[int]*SyncTask)
 	for id, task := range s.activeTasks {
-		tasks[id] = task
+		snapshot := *task
+		tasks[id] = &snapshot
 	}
 
 	return tasks, nil
 }
 
-// WaitForTask blocks until the given task completes and returns its error (nil on success)
+// WaitForTask blocks until the given task completes and returns its error (nil on success).
+// Tasks that already finished return their recorded result. Any number of callers may
+// wait for the same task.
 func (s *SyncService) WaitForTask(ctx context.Context, taskId int) error {
 	s.mutex.RLock()
 	task, exists := s.activeTasks[taskId]
+	taskErr, finished := s.finishedTasks[taskId]
 	s.mutex.RUnlock()
 
 	if !exists {
+		if finished {
+			return taskErr
+		}
 		return fmt.Errorf("task %d not found", taskId)
 	}
 
 	select {
-	case err := <-task.Done:
-		return err
+	case <-task.Done:
+		return task.Err
 	case <-ctx.Done():
 		return ctx.Err()
 	}
@@ -301,22 +463,49 @@ func (s *SyncService) WaitForTask(ctx context.Context, taskId int) error {
 func (s *SyncService) executeSyncTask(ctx context.Context, task *SyncTask) {
 	log.Printf("[SyncService] executeSyncTask started: taskId=%d action=%s tabId=%s from=%s to=%s", task.Id, task.Action, task.TabId, task.Profile.From, task.Profile.To)
 	var taskErr error
+	var statsCtx context.Context
 	defer func() {
 		log.Printf("[SyncService] executeSyncTask finished: taskId=%d err=%v", task.Id, taskErr)
-		task.Done <- taskErr
-		close(task.Done)
+		status := s.taskStatus(task)
+		s.recordHistory(statsCtx, task, status, taskErr)
+		finishTaskRun(task.RunId, status == "cancelled")
+		task.Ticket.Release()
 		s.mutex.Lock()
 		delete(s.activeTasks, task.Id)
+		s.finishedTasks[task.Id] = taskErr
+		s.finishedOrder = append(s.finishedOrder, task.Id)
+		// Tasks finish in any order, so the oldest results go first, not the lowest IDs
+		if len(s.finishedOrder) > maxFinishedTasks {
+			delete(s.finishedTasks, s.finishedOrder[0])
+			s.finishedOrder = s.finishedOrder[1:]
+		}
 		s.mutex.Unlock()
+		task.Err = taskErr
+		close(task.Done)
 	}()
 
+	// Wait for the task queue to start the task
+	if err := task.Ticket.Wait(ctx); err != nil {
+		s.setTaskStatus(task, "cancelled")
+		taskErr = err
+		s.emitSyncEvent(events.SyncCancelled, task.TabId, string(task.Action), "cancelled", "Sync operation was cancelled")
+		return
+	}
+	startTaskRun(task.RunId)
+
 	// Create isolated rclone context for this task
 	ctx, err := rclone.NewTaskContext(ctx, task.Id)
 	if err != nil {
+		s.setTaskStatus(task, "failed")
 		taskErr = fmt.Errorf("failed to create rclone task context: %w", err)
 		s.handleSyncError(task, taskErr.Error())
 		return
 	}
+	if !task.Profile.DryRun && task.Action != ActionCheck {
+		ctx = rclone.WithTransferJournal(ctx, rclone.NewTransferJournal(journalRun(task.Action)))
+	}
+	statsCtx = ctx
+	ctx = rclone.WithPause(ctx, task.Pause)
 
 	// Create structured status channel
 	outStatus := make(chan *dto.SyncStatusDTO, 100)
@@ -349,6 +538,9 @@ func (s *SyncService) executeSyncTask(ctx context.Context, task *SyncTask) {
 			status.Id = &task.Id
 			status.TabId = &task.TabId
 			status.Action = string(task.Action)
+			if task.Pause.Paused() {
+				status.Status = "paused"
+			}
 
 			// Extract LogMessages for text-based consumers
 			for _, logMsg := range status.LogMessages {
@@ -370,8 +562,12 @@ func (s *SyncService) executeSyncTask(ctx context.Context, task *SyncTask) {
 		}
 	}()
 
-	// Update task status
-	task.Status = "running"
+	// Update task status (a task paused or stopped before it got here stays so)
+	s.mutex.Lock()
+	if task.Status != "paused" && task.Status != "cancelled" {
+		task.Status = "running"
+	}
+	s.mutex.Unlock()
 	s.emitSyncEvent(events.SyncProgress, task.TabId, string(task.Action), "running", "Sync operation in progress")
 
 	// Execute the sync operation using rclone Go library
@@ -385,6 +581,19 @@ func (s *SyncService) executeSyncTask(ctx context.Context, task *SyncTask) {
 		err = rclone.BiSync(ctx, config, task.Profile, false, outStatus)
 	case ActionBiResync:
 		err = rclone.BiSync(ctx, config, task.Profile, true, outStatus)
+	case ActionCopy:
+		err = rclone.Copy(ctx, config, task.Profile, outStatus)
+	case ActionMove:
+		err = rclone.Move(ctx, config, task.Profile, outStatus)
+	case ActionCheck:
+		var report *models.CheckReport
+		report, err = rclone.Check(ctx, config, task.Profile, false, outStatus)
+		s.mutex.Lock()
+		task.CheckReport = report
+		s.mutex.Unlock()
+		if err == nil && !report.InSync {
+			err = fmt.Errorf("%d differences found", report.Differences())
+		}
 	default:
 		err = fmt.Errorf("unknown sync action: %s", task.Action)
 	}
@@ -399,7 +608,7 @@ func (s *SyncService) executeSyncTask(ctx context.Context, task *SyncTask) {
 	// Check if context was cancelled
 	select {
 	case <-ctx.Done():
-		task.Status = "cancelled"
+		s.setTaskStatus(task, "cancelled")
 		taskErr = ctx.Err()
 		s.emitSyncEvent(events.SyncCancelled, task.TabId, string(task.Action), "cancelled", "Sync operation was cancelled")
 		return
@@ -408,30 +617,60 @@ func (s *SyncService) executeSyncTask(ctx context.Context, task *SyncTask) {
 
 	// Handle result
 	if err != nil {
-		task.Status = "failed"
+		s.setTaskStatus(task, "failed")
 		taskErr = fmt.Errorf("sync failed: %w", err)
 		s.handleSyncError(task, taskErr.Error())
 
-		// Send notification for sync failure (only for non-board tasks)
-		if !strings.HasPrefix(task.TabId, "board-") {
+		// Send notification for sync failure (boards and flows notify once for the whole run)
+		if !isOrchestratedTab(task.TabId) {
 			s.sendSyncNotification(task, false, err.Error())
 		}
 		return
 	}
 
 	// Success
-	task.Status = "completed"
-	endTime := time.Now()
-	task.EndTime = &endTime
+	s.setTaskStatus(task, "completed")
 
 	s.emitSyncEvent(events.SyncCompleted, task.TabId, string(task.Action), "completed", "Sync operation completed successfully")
 
-	// Send notification for sync success (only for non-board tasks)
-	if !strings.HasPrefix(task.TabId, "board-") {
+	// Send notification for sync success (boards and flows notify once for the whole run)
+	if !isOrchestratedTab(task.TabId) {
 		s.sendSyncNotification(task, true, "")
 	}
 }
 
+// setTaskStatus changes the status of a task under the lock, which PauseSync and StopSync
+// change it under too. Completed tasks get their end time.
+func (s *SyncService) setTaskStatus(task *SyncTask, status string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	task.Status = status
+	if status == "completed" {
+		endTime := time.Now()
+		task.EndTime = &endTime
+	}
+}
+
+// taskStatus returns the current status of a task
+func (s *SyncService) taskStatus(task *SyncTask) string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return task.Status
+}
+
+// recordHistory persists a history entry with the final accounting stats of a finished task
+func (s *SyncService) recordHistory(statsCtx context.Context, task *SyncTask, status string, taskErr error) {
+	if s.historyService == nil {
+		return
+	}
+
+	entry := NewHistoryEntry(statsCtx, task.Profile.Name, string(task.Action), status, task.StartTime, taskErr)
+	// context.Background() since task context may be cancelled
+	if err := s.historyService.AddEntry(context.Background(), entry); err != nil {
+		log.Printf("Failed to record history for task %d: %v", task.Id, err)
+	}
+}
+
 // emitSyncEvent emits a sync event to the frontend via unified EventBus
 func (s *SyncService) emitSyncEvent(eventType events.EventType, tabId, action, status, message string) {
 	log.Printf("[sync:%s:%s] %s: %s", action, tabId, status, message)
@@ -463,6 +702,12 @@ func (s *SyncService) sendSyncNotification(task *SyncTask, success bool, errorMs
 		actionLabel = "Bi-directional Sync"
 	case ActionBiResync:
 		actionLabel = "Bi-directional Resync"
+	case ActionCopy:
+		actionLabel = "Copy"
+	case ActionMove:
+		actionLabel = "Move"
+	case ActionCheck:
+		actionLabel = "Check"
 	}
 
 	profileName := task.Profile.Name
@@ -474,6 +719,9 @@ func (s *SyncService) sendSyncNotification(task *SyncTask, success bool, errorMs
 	if success {
 		title = fmt.Sprintf("%s Completed", actionLabel)
 		body = fmt.Sprintf("Profile \"%s\" synced successfully.", profileName)
+		if task.Action == ActionCheck {
+			body = fmt.Sprintf("Profile \"%s\" is in sync.", profileName)
+		}
 	} else {
 		title = fmt.Sprintf("%s Failed", actionLabel)
 		// Truncate error message if too long
@@ -2,13 +2,24 @@ package services
 
 import (
 	"context"
+	"desktop/backend/rclone"
+	"desktop/backend/utils"
 	"fmt"
 	"log"
 	"sync"
 
+	"github.com/rclone/rclone/fs"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
+// trayBandwidthPresets are the limits offered in the tray for all running tasks
+var trayBandwidthPresets = []struct{ label, spec string }{
+	{"1 MB/s", "1M"},
+	{"5 MB/s", "5M"},
+	{"10 MB/s", "10M"},
+	{"Unlimited", "off"},
+}
+
 // TrayService manages the system tray
 type TrayService struct {
 	app            *application.App
@@ -92,11 +103,21 @@ func (t *TrayService) Initialize() error {
 		t.tray.SetIcon(t.iconData)
 	}
 
-	t.tray.SetTooltip("NS-Drive")
+	t.tray.SetTooltip(trayTooltip(utils.CurrentBandwidth()))
 
 	// Build menu
 	t.buildMenu()
 
+	// Reflect bandwidth changes, e.g. from a timetable or a live change, in the tray
+	utils.OnBandwidthChange(func(limit fs.BwPair) {
+		t.mutex.Lock()
+		if t.tray != nil {
+			t.tray.SetTooltip(trayTooltip(limit))
+		}
+		t.mutex.Unlock()
+		t.RefreshMenu()
+	})
+
 	// Set click handler to show window
 	t.tray.OnClick(func() {
 		t.showWindow()
@@ -164,6 +185,9 @@ func (t *TrayService) populateMenu(menu *application.Menu) {
 		}
 	}
 
+	t.addBandwidthMenu(menu)
+	menu.AddSeparator()
+
 	// Add standard items
 	menu.Add("Open NS-Drive").OnClick(func(ctx *application.Context) {
 		t.showWindow()
@@ -176,11 +200,53 @@ func (t *TrayService) populateMenu(menu *application.Menu) {
 	})
 }
 
-// executeFlow emits an event for the frontend to execute a flow
+// addBandwidthMenu adds the submenu that shows the bandwidth limit in force and changes
+// it for all running tasks
+func (t *TrayService) addBandwidthMenu(menu *application.Menu) {
+	current := utils.FormatBandwidth(utils.CurrentBandwidth())
+	if current == "" {
+		current = "Unlimited"
+	}
+	submenu := menu.AddSubmenu("Bandwidth")
+	submenu.Add("Current: " + current).SetEnabled(false)
+	submenu.AddSeparator()
+
+	for _, preset := range trayBandwidthPresets {
+		spec := preset.spec
+		submenu.Add("Running tasks: " + preset.label).OnClick(func(ctx *application.Context) {
+			t.setRunningBandwidth(spec)
+		})
+	}
+	submenu.Add("Restore profile limits").OnClick(func(ctx *application.Context) {
+		t.setRunningBandwidth("")
+	})
+}
+
+// setRunningBandwidth changes the bandwidth limit of all running tasks
+func (t *TrayService) setRunningBandwidth(spec string) {
+	log.Printf("TrayService: Setting running tasks bandwidth to %q", spec)
+	if err := rclone.SetRunningBandwidth(spec); err != nil {
+		log.Printf("TrayService: Failed to set bandwidth: %v", err)
+	}
+}
+
+// trayTooltip describes the app and the bandwidth limit in force
+func trayTooltip(limit fs.BwPair) string {
+	if bw := utils.FormatBandwidth(limit); bw != "" {
+		return "NS-Drive (bandwidth " + bw + "/s)"
+	}
+	return "NS-Drive"
+}
+
+// executeFlow runs a flow in the backend, so it works with the window hidden
 func (t *TrayService) executeFlow(flowId string) {
-	log.Printf("TrayService: Requesting flow execution %s", flowId)
-	if t.app != nil {
-		t.app.Event.Emit("tray:execute_flow", flowId)
+	log.Printf("TrayService: Executing flow %s", flowId)
+	if t.flowService == nil {
+		return
+	}
+	if _, err := t.flowService.ExecuteFlow(context.Background(), flowId); err != nil {
+		log.Printf("TrayService: Failed to execute flow %s: %v", flowId, err)
+		t.flowService.notifyFlowNotStarted(flowId, err)
 	}
 }
 
@@ -0,0 +1,197 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+)
+
+// rclone limits bandwidth with a single token bucket for the whole process, so running
+// operations share it. The bucket follows the most restrictive limit among them, each
+// using its own timetable or the default one, and is re-evaluated every minute for
+// timetables that change with the time of day. Running operations are identified by
+// the stats group of their context, so a task's limit can be changed while it runs, but
+// that only ever caps the shared bucket.
+var bandwidth = struct {
+	sync.Mutex
+	defaultLimit fs.BwTimetable
+	active       map[int]*bandwidthLimit
+	nextId       int
+	current      fs.BwPair
+	listeners    []func(fs.BwPair)
+	tickerOnce   sync.Once
+}{active: map[int]*bandwidthLimit{}}
+
+// bandwidthLimit is the timetable of a running operation and the one it was changed to
+// while running, if any
+type bandwidthLimit struct {
+	group     string
+	timetable fs.BwTimetable
+	override  fs.BwTimetable
+}
+
+// effective returns the timetable in force for the operation. Call with the lock held.
+func (l *bandwidthLimit) effective() fs.BwTimetable {
+	switch {
+	case len(l.override) > 0:
+		return l.override
+	case len(l.timetable) > 0:
+		return l.timetable
+	default:
+		return bandwidth.defaultLimit
+	}
+}
+
+// SetDefaultBandwidth sets the timetable used by operations without their own limit.
+// An empty timetable means unlimited.
+func SetDefaultBandwidth(timetable fs.BwTimetable) {
+	bandwidth.Lock()
+	bandwidth.defaultLimit = timetable
+	applyBandwidthLocked(time.Now())
+	bandwidth.Unlock()
+	startBandwidthTicker()
+}
+
+// DefaultBandwidth returns the timetable used by operations without their own limit
+func DefaultBandwidth() fs.BwTimetable {
+	bandwidth.Lock()
+	defer bandwidth.Unlock()
+	return bandwidth.defaultLimit
+}
+
+// CurrentBandwidth returns the limit the shared token bucket is set to. Zero values
+// mean unlimited.
+func CurrentBandwidth() fs.BwPair {
+	bandwidth.Lock()
+	defer bandwidth.Unlock()
+	return bandwidth.current
+}
+
+// FormatBandwidth formats a limit as "10Mi" or "10Mi:2Mi" (upload:download), with "off"
+// for an unlimited direction and an empty string when both are unlimited
+func FormatBandwidth(limit fs.BwPair) string {
+	if !limit.IsSet() {
+		return ""
+	}
+	format := func(v fs.SizeSuffix) string {
+		if v <= 0 {
+			return "off"
+		}
+		return v.String()
+	}
+	if limit.Tx == limit.Rx {
+		return format(limit.Tx)
+	}
+	return format(limit.Tx) + ":" + format(limit.Rx)
+}
+
+// OnBandwidthChange registers a function called with the new limit whenever the shared
+// token bucket changes. It is called on its own goroutine.
+func OnBandwidthChange(fn func(fs.BwPair)) {
+	bandwidth.Lock()
+	defer bandwidth.Unlock()
+	bandwidth.listeners = append(bandwidth.listeners, fn)
+}
+
+// SetRunningBandwidth overrides the timetable of the running operations in the given
+// stats group, or of all running operations when group is empty, until they finish. An
+// empty timetable removes the override. Returns false if none is running.
+func SetRunningBandwidth(group string, timetable fs.BwTimetable) bool {
+	bandwidth.Lock()
+	defer bandwidth.Unlock()
+
+	found := false
+	for _, limit := range bandwidth.active {
+		if group == "" || limit.group == group {
+			limit.override = timetable
+			found = true
+		}
+	}
+	if found {
+		applyBandwidthLocked(time.Now())
+	}
+	return found
+}
+
+// LimitBandwidth registers a running operation with the given timetable (the default
+// one when empty) and returns the function to call when it finishes. The operation is
+// identified by the stats group of ctx.
+func LimitBandwidth(ctx context.Context, timetable fs.BwTimetable) (release func()) {
+	group, _ := accounting.StatsGroupFromContext(ctx)
+
+	bandwidth.Lock()
+	bandwidth.nextId++
+	id := bandwidth.nextId
+	bandwidth.active[id] = &bandwidthLimit{group: group, timetable: timetable}
+	applyBandwidthLocked(time.Now())
+	bandwidth.Unlock()
+	startBandwidthTicker()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			bandwidth.Lock()
+			delete(bandwidth.active, id)
+			applyBandwidthLocked(time.Now())
+			bandwidth.Unlock()
+		})
+	}
+}
+
+// startBandwidthTicker re-evaluates the timetables every minute
+func startBandwidthTicker() {
+	bandwidth.tickerOnce.Do(func() {
+		go func() {
+			for now := range time.Tick(time.Minute) {
+				bandwidth.Lock()
+				applyBandwidthLocked(now)
+				bandwidth.Unlock()
+			}
+		}()
+	})
+}
+
+// applyBandwidthLocked sets the token bucket to the most restrictive limit in force at
+// now. Call with the lock held.
+func applyBandwidthLocked(now time.Time) {
+	var limit fs.BwPair
+	if len(bandwidth.active) == 0 {
+		limit = bandwidthAt(bandwidth.defaultLimit, now)
+	}
+	for _, running := range bandwidth.active {
+		at := bandwidthAt(running.effective(), now)
+		limit.Tx = minBandwidth(limit.Tx, at.Tx)
+		limit.Rx = minBandwidth(limit.Rx, at.Rx)
+	}
+
+	if limit == bandwidth.current {
+		return
+	}
+	bandwidth.current = limit
+	accounting.TokenBucket.SetBwLimit(limit)
+	for _, fn := range bandwidth.listeners {
+		go fn(limit)
+	}
+}
+
+// bandwidthAt returns the limit of a timetable at the given time, with zero meaning
+// unlimited in both directions
+func bandwidthAt(timetable fs.BwTimetable, now time.Time) fs.BwPair {
+	limit := timetable.LimitAt(now).Bandwidth
+	return fs.BwPair{Tx: max(limit.Tx, 0), Rx: max(limit.Rx, 0)}
+}
+
+// minBandwidth returns the lower of two limits, where zero means unlimited
+func minBandwidth(a, b fs.SizeSuffix) fs.SizeSuffix {
+	switch {
+	case a == 0:
+		return b
+	case b == 0:
+		return a
+	default:
+		return min(a, b)
+	}
+}
@@ -0,0 +1,304 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TaskPriority orders queued tasks; higher priorities start first
+type TaskPriority int
+
+const (
+	PriorityBackground TaskPriority = iota // resumed and other unattended work
+	PriorityScheduled                      // started by a schedule
+	PriorityManual                         // started by the user
+)
+
+// QueueEntry describes a task in the queue
+type QueueEntry struct {
+	Id        int          `json:"id"`
+	Kind      string       `json:"kind"` // "sync", "operation" or "legacy"
+	TaskId    int          `json:"task_id"`
+	TabId     string       `json:"tab_id,omitempty"`
+	Name      string       `json:"name"`
+	Action    string       `json:"action"`
+	Remotes   []string     `json:"remotes,omitempty"` // remotes the task uses, for the per-remote caps
+	Priority  TaskPriority `json:"priority"`
+	Status    string       `json:"status"` // "queued" or "running"
+	QueuedAt  time.Time    `json:"queued_at"`
+	StartedAt *time.Time   `json:"started_at,omitempty"`
+}
+
+// QueueTicket is a task's place in the queue
+type QueueTicket struct {
+	entry    QueueEntry
+	ready    chan struct{} // closed when the task may start
+	cancel   context.CancelFunc
+	released bool
+}
+
+// Every task goes through one queue that starts queued tasks in order while fewer than
+// the maximum number of tasks run and each of their remotes is below its cap. A task
+// blocked by a remote cap doesn't hold up the tasks behind it.
+var taskQueue = struct {
+	sync.Mutex
+	queued        []*QueueTicket // in start order
+	running       []*QueueTicket
+	nextId        int
+	maxConcurrent int            // 0 means unlimited
+	remoteLimits  map[string]int // remote name -> max concurrent tasks
+	held          bool
+	listeners     []func([]QueueEntry)
+}{remoteLimits: map[string]int{}}
+
+// taskPriorityKey is the context key of a task's priority
+type taskPriorityKey struct{}
+
+// WithTaskPriority returns a context whose tasks are queued with the given priority
+func WithTaskPriority(ctx context.Context, priority TaskPriority) context.Context {
+	return context.WithValue(ctx, taskPriorityKey{}, priority)
+}
+
+// TaskPriorityFromContext returns the priority of tasks started with ctx, manual by default
+func TaskPriorityFromContext(ctx context.Context) TaskPriority {
+	if priority, ok := ctx.Value(taskPriorityKey{}).(TaskPriority); ok {
+		return priority
+	}
+	return PriorityManual
+}
+
+// SubmitTask queues a task with the priority of ctx, after the queued tasks of the same
+// or a higher priority. cancel is called when the task is cancelled from the queue.
+// Call Wait before running the task and Release when it finishes.
+func SubmitTask(ctx context.Context, entry QueueEntry, cancel context.CancelFunc) *QueueTicket {
+	taskQueue.Lock()
+	defer taskQueue.Unlock()
+
+	taskQueue.nextId++
+	entry.Id = taskQueue.nextId
+	entry.Priority = TaskPriorityFromContext(ctx)
+	entry.Status = "queued"
+	entry.QueuedAt = time.Now()
+	ticket := &QueueTicket{entry: entry, ready: make(chan struct{}), cancel: cancel}
+
+	position := len(taskQueue.queued)
+	for position > 0 && taskQueue.queued[position-1].entry.Priority < entry.Priority {
+		position--
+	}
+	taskQueue.queued = append(taskQueue.queued, nil)
+	copy(taskQueue.queued[position+1:], taskQueue.queued[position:])
+	taskQueue.queued[position] = ticket
+
+	dispatchTasksLocked()
+	return ticket
+}
+
+// Id returns the queue entry ID of the ticket
+func (t *QueueTicket) Id() int {
+	return t.entry.Id
+}
+
+// Queued reports whether the task is still waiting to start
+func (t *QueueTicket) Queued() bool {
+	taskQueue.Lock()
+	defer taskQueue.Unlock()
+	return t.entry.Status == "queued" && !t.released
+}
+
+// Wait blocks until the task may start. If ctx ends first, the task leaves the queue and
+// the context's error is returned.
+func (t *QueueTicket) Wait(ctx context.Context) error {
+	select {
+	case <-t.ready:
+		return nil
+	case <-ctx.Done():
+		t.Release()
+		return ctx.Err()
+	}
+}
+
+// Release removes the task from the queue, freeing its slot if it was running. Releasing
+// twice is harmless.
+func (t *QueueTicket) Release() {
+	taskQueue.Lock()
+	defer taskQueue.Unlock()
+
+	if t.released {
+		return
+	}
+	t.released = true
+	taskQueue.queued = removeTicket(taskQueue.queued, t)
+	taskQueue.running = removeTicket(taskQueue.running, t)
+	dispatchTasksLocked()
+}
+
+// QueuedTasks returns the running tasks followed by the queued ones in start order
+func QueuedTasks() []QueueEntry {
+	taskQueue.Lock()
+	defer taskQueue.Unlock()
+	return queueSnapshotLocked()
+}
+
+// MoveQueuedTask moves a queued task to the given position among the queued tasks,
+// 0 being the next to start
+func MoveQueuedTask(id, position int) error {
+	taskQueue.Lock()
+	defer taskQueue.Unlock()
+
+	ticket := findTicket(taskQueue.queued, id)
+	if ticket == nil {
+		return fmt.Errorf("queued task %d not found", id)
+	}
+	queued := removeTicket(taskQueue.queued, ticket)
+	position = max(0, min(position, len(queued)))
+	queued = append(queued, nil)
+	copy(queued[position+1:], queued[position:])
+	queued[position] = ticket
+	taskQueue.queued = queued
+
+	dispatchTasksLocked()
+	return nil
+}
+
+// CancelQueuedTask removes a task that hasn't started from the queue and cancels it
+func CancelQueuedTask(id int) error {
+	taskQueue.Lock()
+	ticket := findTicket(taskQueue.queued, id)
+	taskQueue.Unlock()
+
+	if ticket == nil {
+		return fmt.Errorf("queued task %d not found", id)
+	}
+	ticket.Release()
+	if ticket.cancel != nil {
+		ticket.cancel()
+	}
+	return nil
+}
+
+// SetMaxConcurrentTasks sets how many tasks may run at once. 0 means unlimited.
+func SetMaxConcurrentTasks(n int) {
+	taskQueue.Lock()
+	defer taskQueue.Unlock()
+	taskQueue.maxConcurrent = max(n, 0)
+	dispatchTasksLocked()
+}
+
+// SetRemoteConcurrency sets how many tasks may use each remote at once. Remotes not in
+// limits are unlimited.
+func SetRemoteConcurrency(limits map[string]int) {
+	taskQueue.Lock()
+	defer taskQueue.Unlock()
+	taskQueue.remoteLimits = make(map[string]int, len(limits))
+	for remote, limit := range limits {
+		if limit > 0 {
+			taskQueue.remoteLimits[remote] = limit
+		}
+	}
+	dispatchTasksLocked()
+}
+
+// HoldTaskQueue stops starting queued tasks, e.g. while the app quits
+func HoldTaskQueue() {
+	taskQueue.Lock()
+	defer taskQueue.Unlock()
+	taskQueue.held = true
+}
+
+// OnQueueChange registers a function called with the queue whenever a task is queued,
+// starts, moves or leaves it. It is called on its own goroutine.
+func OnQueueChange(fn func([]QueueEntry)) {
+	taskQueue.Lock()
+	defer taskQueue.Unlock()
+	taskQueue.listeners = append(taskQueue.listeners, fn)
+}
+
+// dispatchTasksLocked starts the queued tasks that fit and notifies the listeners. Call
+// with the lock held.
+func dispatchTasksLocked() {
+	defer notifyQueueLocked()
+	if taskQueue.held {
+		return
+	}
+
+	remoteCounts := map[string]int{}
+	for _, ticket := range taskQueue.running {
+		for _, remote := range ticket.entry.Remotes {
+			remoteCounts[remote]++
+		}
+	}
+
+	var waiting []*QueueTicket
+	for _, ticket := range taskQueue.queued {
+		if taskQueue.maxConcurrent > 0 && len(taskQueue.running) >= taskQueue.maxConcurrent {
+			waiting = append(waiting, ticket)
+			continue
+		}
+		fits := true
+		for _, remote := range ticket.entry.Remotes {
+			if limit, ok := taskQueue.remoteLimits[remote]; ok && remoteCounts[remote] >= limit {
+				fits = false
+				break
+			}
+		}
+		if !fits {
+			waiting = append(waiting, ticket)
+			continue
+		}
+
+		for _, remote := range ticket.entry.Remotes {
+			remoteCounts[remote]++
+		}
+		now := time.Now()
+		ticket.entry.Status = "running"
+		ticket.entry.StartedAt = &now
+		taskQueue.running = append(taskQueue.running, ticket)
+		close(ticket.ready)
+	}
+	taskQueue.queued = waiting
+}
+
+// notifyQueueLocked calls the listeners with the queue. Call with the lock held.
+func notifyQueueLocked() {
+	if len(taskQueue.listeners) == 0 {
+		return
+	}
+	entries := queueSnapshotLocked()
+	for _, fn := range taskQueue.listeners {
+		go fn(entries)
+	}
+}
+
+// queueSnapshotLocked copies the queue entries. Call with the lock held.
+func queueSnapshotLocked() []QueueEntry {
+	entries := make([]QueueEntry, 0, len(taskQueue.running)+len(taskQueue.queued))
+	for _, ticket := range taskQueue.running {
+		entries = append(entries, ticket.entry)
+	}
+	for _, ticket := range taskQueue.queued {
+		entries = append(entries, ticket.entry)
+	}
+	return entries
+}
+
+// findTicket returns the ticket with the given entry ID, or nil
+func findTicket(tickets []*QueueTicket, id int) *QueueTicket {
+	for _, ticket := range tickets {
+		if ticket.entry.Id == id {
+			return ticket
+		}
+	}
+	return nil
+}
+
+// removeTicket returns tickets without t
+func removeTicket(tickets []*QueueTicket, t *QueueTicket) []*QueueTicket {
+	for i, ticket := range tickets {
+		if ticket == t {
+			return append(tickets[:i:i], tickets[i+1:]...)
+		}
+	}
+	return tickets
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"context"
+	"testing"
+)
+
+// submitTestTask queues a task with the given priority and remotes
+func submitTestTask(t *testing.T, priority TaskPriority, name string, remotes ...string) (*QueueTicket, context.Context) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(WithTaskPriority(context.Background(), priority))
+	t.Cleanup(cancel)
+	ticket := SubmitTask(ctx, QueueEntry{Kind: "sync", Name: name, Remotes: remotes}, cancel)
+	t.Cleanup(ticket.Release)
+	return ticket, ctx
+}
+
+// queueNames returns the names of the queue entries in order
+func queueNames(status string) []string {
+	var names []string
+	for _, entry := range QueuedTasks() {
+		if entry.Status == status {
+			names = append(names, entry.Name)
+		}
+	}
+	return names
+}
+
+// TestTaskQueue tests the concurrency limit, priorities, reordering and cancelling
+func TestTaskQueue(t *testing.T) {
+	SetMaxConcurrentTasks(1)
+	defer SetMaxConcurrentTasks(0)
+
+	running, _ := submitTestTask(t, PriorityManual, "running")
+	if running.Queued() {
+		t.Fatal("expected the first task to start at once")
+	}
+	submitTestTask(t, PriorityBackground, "background")
+	scheduled, _ := submitTestTask(t, PriorityScheduled, "scheduled")
+	manual, _ := submitTestTask(t, PriorityManual, "manual")
+
+	if got := queueNames("queued"); len(got) != 3 || got[0] != "manual" || got[1] != "scheduled" || got[2] != "background" {
+		t.Fatalf("expected queue ordered by priority, got %v", got)
+	}
+
+	if err := MoveQueuedTask(scheduled.Id(), 0); err != nil {
+		t.Fatalf("MoveQueuedTask failed: %v", err)
+	}
+	if err := CancelQueuedTask(manual.Id()); err != nil {
+		t.Fatalf("CancelQueuedTask failed: %v", err)
+	}
+	if err := CancelQueuedTask(running.Id()); err == nil {
+		t.Error("expected error cancelling a running task from the queue")
+	}
+
+	running.Release()
+	if err := scheduled.Wait(context.Background()); err != nil {
+		t.Fatalf("expected the moved task to start next: %v", err)
+	}
+	if got := queueNames("queued"); len(got) != 1 || got[0] != "background" {
+		t.Errorf("expected only the background task left queued, got %v", got)
+	}
+}
+
+// TestTaskQueueRemoteConcurrency tests that a task blocked by a remote cap doesn't hold
+// up the tasks behind it
+func TestTaskQueueRemoteConcurrency(t *testing.T) {
+	SetRemoteConcurrency(map[string]int{"gdrive": 1})
+	defer SetRemoteConcurrency(nil)
+
+	first, _ := submitTestTask(t, PriorityManual, "first", "gdrive")
+	second, ctx := submitTestTask(t, PriorityManual, "second", "gdrive", "s3")
+	other, _ := submitTestTask(t, PriorityManual, "other", "s3")
+
+	if first.Queued() || !second.Queued() || other.Queued() {
+		t.Fatalf("expected only the second gdrive task queued, got %v queued", queueNames("queued"))
+	}
+
+	first.Release()
+	if err := second.Wait(ctx); err != nil {
+		t.Fatalf("expected the second gdrive task to start: %v", err)
+	}
+}
@@ -51,6 +51,10 @@ func RunRcloneWithRetryAndStats(ctx context.Context, retry bool, showStats bool,
 
 	stopStats = startProgress(ctx, outStatus)
 
+	// Enforce the operation's bandwidth timetable while it runs
+	releaseBandwidth := LimitBandwidth(ctx, fsConfig.BwLimit)
+	defer releaseBandwidth()
+
 	cmd.SigInfoHandler()
 
 	for try := 1; try <= fsConfig.Retries; try++ {
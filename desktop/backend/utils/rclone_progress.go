@@ -19,6 +19,9 @@ import (
 const (
 	// interval between progress status emissions
 	defaultProgressInterval = 500 * time.Millisecond
+	// most recent completed transfers listed in a progress status; a journaled task
+	// keeps all of them in its stats
+	maxListedTransfers = 100
 )
 
 // shouldSkipLogMessage returns true for backend debug messages that should be filtered out
@@ -169,7 +172,11 @@ func createStatusFromStats(ctx context.Context, startTime time.Time, logMessages
 		}
 
 		// Completed/failed transfers
-		for _, tr := range stats.Transferred() {
+		completed := stats.Transferred()
+		if len(completed) > maxListedTransfers {
+			completed = completed[len(completed)-maxListedTransfers:]
+		}
+		for _, tr := range completed {
 			fi := dto.FileTransferInfo{
 				Name:  tr.Name,
 				Size:  tr.Size,
@@ -198,6 +205,9 @@ func createStatusFromStats(ctx context.Context, startTime time.Time, logMessages
 		syncStatus.Deletes = stats.GetDeletes()
 	}
 
+	// rclone's token bucket is shared, so the limit in force applies to every task
+	syncStatus.BandwidthLimit = FormatBandwidth(CurrentBandwidth())
+
 	// Elapsed time
 	elapsed := time.Since(startTime)
 	syncStatus.ElapsedTime = formatDuration(elapsed)
@@ -343,3 +353,9 @@ func startProgress(ctx context.Context, outStatus chan *dto.SyncStatusDTO) func(
 		// NOTE: Do NOT close outStatus here — the caller is responsible
 	}
 }
+
+// SnapshotStats returns the current rclone accounting stats of the task context as a
+// SyncStatusDTO. Call it after the operation finishes to obtain the final totals.
+func SnapshotStats(ctx context.Context, startTime time.Time) *dto.SyncStatusDTO {
+	return createStatusFromStats(ctx, startTime, nil)
+}
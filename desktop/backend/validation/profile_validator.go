@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/rclone/rclone/fs"
 )
 
 // ProfileValidator handles profile validation
@@ -61,6 +63,9 @@ func (v *ProfileValidator) ValidateProfile(profile models.Profile) error {
 	if err := v.ValidateBandwidth(profile.Bandwidth); err != nil {
 		return err
 	}
+	if err := v.ValidateBandwidthSchedule(profile.BandwidthSchedule, "bandwidth_schedule"); err != nil {
+		return err
+	}
 	if err := v.ValidatePaths(profile.IncludedPaths, "included_paths"); err != nil {
 		return err
 	}
@@ -211,6 +216,22 @@ func (v *ProfileValidator) ValidateBandwidth(bandwidth int) error {
 	return nil
 }
 
+// ValidateBandwidthSchedule validates an rclone bandwidth timetable, e.g. "10M",
+// "10M:2M" (upload:download) or "Mon-08:00,2M Mon-18:00,off"
+func (v *ProfileValidator) ValidateBandwidthSchedule(value string, fieldName string) error {
+	if value == "" {
+		return nil
+	}
+	var timetable fs.BwTimetable
+	if err := timetable.Set(value); err != nil {
+		return &ValidationError{
+			Field:   fieldName,
+			Message: fmt.Sprintf("invalid bandwidth timetable (use e.g. '10M', '10M:2M' or 'Mon-08:00,2M Mon-18:00,off'): %v", err),
+		}
+	}
+	return nil
+}
+
 // ValidatePaths validates include/exclude path patterns
 func (v *ProfileValidator) ValidatePaths(paths []string, fieldName string) error {
 	for i, path := range paths {
@@ -140,6 +140,34 @@ func TestValidateBandwidth(t *testing.T) {
 	}
 }
 
+func TestValidateBandwidthSchedule(t *testing.T) {
+	v := NewProfileValidator()
+
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"", false},                            // Empty = not set
+		{"10M", false},                         // Constant limit
+		{"10M:2M", false},                      // Upload:download
+		{"off", false},                         // Unlimited
+		{"08:00,512k 19:00,off", false},        // Daily timetable
+		{"Mon-08:00,2M Fri-18:00,off", false},  // Weekly timetable
+		{"Mon-08:00,2M:1M Sat-00:00,off", false}, // Weekly upload:download
+		{"fast", true},                         // Invalid size
+		{"25:00,1M", true},                     // Invalid hour
+		{"Xyz-08:00,1M", true},                 // Invalid weekday
+		{"08:00,1M 12:00", true},               // Slot without a limit
+	}
+
+	for _, tt := range tests {
+		err := v.ValidateBandwidthSchedule(tt.value, "bandwidth_schedule")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateBandwidthSchedule(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+		}
+	}
+}
+
 func TestValidateRemoteName(t *testing.T) {
 	tests := []struct {
 		name    string
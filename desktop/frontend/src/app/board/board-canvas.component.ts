@@ -799,6 +799,12 @@ export class BoardCanvasComponent {
                 return "Bi-Sync";
             case "bi-resync":
                 return "Resync";
+            case "copy":
+                return "Copy";
+            case "move":
+                return "Move";
+            case "check":
+                return "Check";
             case "push":
             default:
                 return "Push";
@@ -37,6 +37,9 @@ export const EDGE_ACTIONS = [
     { value: "pull", label: "Pull", icon: "pi pi-download" },
     { value: "bi", label: "Bi-Sync", icon: "pi pi-sync" },
     { value: "bi-resync", label: "Resync", icon: "pi pi-replay" },
+    { value: "copy", label: "Copy", icon: "pi pi-copy" },
+    { value: "move", label: "Move", icon: "pi pi-arrow-right-arrow-left" },
+    { value: "check", label: "Check", icon: "pi pi-check-square" },
 ] as const;
 
 // Generate unique ID
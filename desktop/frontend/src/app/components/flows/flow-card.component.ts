@@ -163,6 +163,15 @@ import { RemoteInfo } from '../remote-dropdown/remote-dropdown.component';
           <span class="text-sm text-sys-fg-muted font-medium">Add Operation</span>
         </div>
 
+        <!-- Failure Policy -->
+        @if (flow.operations.length > 1) {
+          <label class="flex items-center gap-2 text-sm text-sys-fg-muted mt-2 cursor-pointer">
+            <input type="checkbox" [(ngModel)]="flow.continueOnFailure" (ngModelChange)="onFlowChange()"
+                   class="w-4 h-4 border-2 border-sys-border" />
+            <span>Continue with the next operations when one fails</span>
+          </label>
+        }
+
         <!-- Schedule Info -->
         @if (flow.scheduleEnabled && flow.cronExpr) {
           <div class="flex items-center gap-2 text-sm text-sys-fg-muted mt-2 pt-2 border-t border-sys-border-subtle">
@@ -591,6 +591,9 @@ export class OperationSettingsPanelComponent implements OnInit {
     { value: 'pull', label: 'Pull', icon: 'pi pi-arrow-left', description: 'Target \u2192 Source. Deletes source files not in target.' },
     { value: 'bi', label: 'Bi-directional', icon: 'pi pi-arrows-h', description: 'Syncs both ways. Changes on either side propagate to the other.' },
     { value: 'bi-resync', label: 'Bi-directional (Resync)', icon: 'pi pi-refresh', description: 'Forces full re-sync. Use when sync state is lost or corrupted.' },
+    { value: 'copy', label: 'Copy', icon: 'pi pi-copy', description: 'Source \u2192 Target. Never deletes anything on the target.' },
+    { value: 'move', label: 'Move', icon: 'pi pi-arrow-right-arrow-left', description: 'Source \u2192 Target. Deletes source files once transferred.' },
+    { value: 'check', label: 'Check', icon: 'pi pi-check-square', description: 'Compares source and target without changing anything.' },
   ];
 
   conflictOptions: DropdownOption[] = [
@@ -62,6 +62,12 @@ export class SyncStatusComponent {
       case "bi":
       case "bi-resync":
         return "pi pi-sync";
+      case "copy":
+        return "pi pi-copy";
+      case "move":
+        return "pi pi-arrow-right-arrow-left";
+      case "check":
+        return "pi pi-check-square";
       default:
         return "pi pi-sync";
     }
@@ -79,6 +85,12 @@ export class SyncStatusComponent {
         return "Bi-Sync";
       case "bi-resync":
         return "Bi-Resync";
+      case "copy":
+        return "Copy";
+      case "move":
+        return "Move";
+      case "check":
+        return "Check";
       default:
         return "Sync";
     }
@@ -7,6 +7,9 @@ export type EventType =
     | "sync:completed"
     | "sync:failed"
     | "sync:cancelled"
+    | "sync:paused"
+    | "sync:resumed"
+    | "sync:queued"
     // Config Events
     | "config:updated"
     | "profile:added"
@@ -31,6 +34,18 @@ export type EventType =
     | "board:execution:completed"
     | "board:execution:failed"
     | "board:execution:cancelled"
+    | "board:execution:paused"
+    | "board:execution:resumed"
+    // Flow Events
+    | "flow:execution:started"
+    | "flow:execution:progress"
+    | "flow:execution:completed"
+    | "flow:execution:failed"
+    | "flow:execution:cancelled"
+    // Recovery Events
+    | "tasks:interrupted"
+    // Queue Events
+    | "queue:updated"
     // Legacy command types
     | "command_started"
     | "command_stoped"
@@ -52,7 +67,10 @@ export interface SyncEvent extends BaseEvent {
         | "sync:progress"
         | "sync:completed"
         | "sync:failed"
-        | "sync:cancelled";
+        | "sync:cancelled"
+        | "sync:paused"
+        | "sync:resumed"
+        | "sync:queued";
     tabId?: string;
     action: string;
     progress?: number;
@@ -101,13 +119,29 @@ export interface BoardEvent extends BaseEvent {
         | "board:execution:progress"
         | "board:execution:completed"
         | "board:execution:failed"
-        | "board:execution:cancelled";
+        | "board:execution:cancelled"
+        | "board:execution:paused"
+        | "board:execution:resumed";
     boardId: string;
     edgeId?: string;
     status: string;
     message?: string;
 }
 
+// Flow event structure
+export interface FlowEvent extends BaseEvent {
+    type:
+        | "flow:execution:started"
+        | "flow:execution:progress"
+        | "flow:execution:completed"
+        | "flow:execution:failed"
+        | "flow:execution:cancelled";
+    flowId: string;
+    operationId?: string;
+    status: string;
+    message?: string;
+}
+
 // Legacy command DTO (for backward compatibility)
 export interface CommandDTO {
     command: string;
@@ -125,6 +159,7 @@ export type AppEvent =
     | TabEvent
     | ErrorEvent
     | BoardEvent
+    | FlowEvent
     | CommandDTO;
 
 // Type guards
@@ -181,6 +216,15 @@ export function isBoardEvent(event: unknown): event is BoardEvent {
     );
 }
 
+export function isFlowEvent(event: unknown): event is FlowEvent {
+    if (typeof event !== "object" || event === null) return false;
+    const e = event as Record<string, unknown>;
+    return (
+        typeof e["type"] === "string" &&
+        (e["type"] as string).startsWith("flow:")
+    );
+}
+
 export function isLegacyCommandDTO(event: unknown): event is CommandDTO {
     if (typeof event !== "object" || event === null) return false;
     const e = event as Record<string, unknown>;
@@ -67,7 +67,7 @@ export interface SyncConfig {
   conflictSuffix?: string;
 }
 
-export type SyncAction = 'push' | 'pull' | 'bi' | 'bi-resync';
+export type SyncAction = 'push' | 'pull' | 'bi' | 'bi-resync' | 'copy' | 'move' | 'check';
 
 export type OperationStatus = 'idle' | 'pending' | 'running' | 'completed' | 'failed' | 'cancelled';
 
@@ -100,6 +100,9 @@ export interface Flow {
   operations: Operation[]; // Sequential operations
   isCollapsed: boolean; // UI state - flow card collapsed
 
+  // Run the remaining operations after one fails instead of stopping the flow
+  continueOnFailure?: boolean;
+
   // Schedule (applies to entire flow)
   scheduleEnabled: boolean;
   cronExpr?: string;
@@ -27,7 +27,7 @@ export interface SyncStatus {
   renames: number;
   timestamp: string;
   elapsed_time: string;
-  action: "pull" | "push" | "bi" | "bi-resync";
+  action: "pull" | "push" | "bi" | "bi-resync" | "copy" | "move" | "check";
   transfers?: FileTransferInfo[];
 }
 
@@ -84,5 +84,5 @@ export function isValidSyncStatus(
 export function isValidSyncAction(
   action: string
 ): action is SyncStatus["action"] {
-  return ["pull", "push", "bi", "bi-resync"].includes(action);
+  return ["pull", "push", "bi", "bi-resync", "copy", "move", "check"].includes(action);
 }
@@ -4,18 +4,16 @@ import { BehaviorSubject, Subject, Subscription } from 'rxjs';
 import { debounceTime } from 'rxjs/operators';
 import * as models from '../../../wailsjs/desktop/backend/models/models.js';
 import {
-  AddBoard,
   DeleteBoard,
-  ExecuteBoard,
-  GetBoardExecutionStatus,
   GetBoards,
-  StopBoardExecution,
 } from '../../../wailsjs/desktop/backend/services/boardservice.js';
 import {
+  ExecuteFlow,
   GetFlows,
   SaveFlows,
+  StopFlow,
 } from '../../../wailsjs/desktop/backend/services/flowservice.js';
-import { isBoardEvent, isSyncEvent, isSyncStatusDTO, parseEvent, type BoardEvent, type SyncEvent } from '../models/events.js';
+import { isFlowEvent, isSyncEvent, isSyncStatusDTO, parseEvent, type FlowEvent, type SyncEvent } from '../models/events.js';
 import { type SyncStatus, type SyncStatusEvent } from '../models/sync-status.interface.js';
 import { ErrorService } from './error.service.js';
 import {
@@ -24,7 +22,7 @@ import {
   DragData,
   Flow,
   FlowsState,
-  generateId,
+  type FlowStatus,
   Operation,
   type SyncAction,
   type SyncConfig,
@@ -47,17 +45,11 @@ export class FlowsService implements OnDestroy {
   // Active execution tracking
   private executingFlowId: string | null = null;
   private executingOperationIndex = -1;
-  private tempBoardId: string | null = null;
 
   private eventCleanup: (() => void) | undefined;
-  private trayEventCleanup: (() => void) | undefined;
   private autoSaveSubscription: Subscription | null = null;
   private autoSaveTrigger$ = new Subject<void>();
 
-  // Board completion tracking (event-driven instead of polling)
-  private boardCompletionResolve: ((status: string) => void) | null = null;
-  private boardCompletionBoardId: string | null = null;
-
   constructor() {
     this.eventCleanup = Events.On('tofe', (event) => {
       const rawData = event.data;
@@ -67,16 +59,8 @@ export class FlowsService implements OnDestroy {
       } else if (parsedEvent && isSyncEvent(parsedEvent)) {
         this.ngZone.run(() => this.handleSyncLogEvent(parsedEvent));
       }
-      if (parsedEvent && isBoardEvent(parsedEvent)) {
-        this.ngZone.run(() => this.handleBoardCompletionEvent(parsedEvent));
-      }
-    });
-
-    // Listen for tray-initiated flow execution
-    this.trayEventCleanup = Events.On('tray:execute_flow', (event) => {
-      const flowId = event.data as string;
-      if (flowId) {
-        this.ngZone.run(() => this.executeFlow(flowId));
+      if (parsedEvent && isFlowEvent(parsedEvent)) {
+        this.ngZone.run(() => this.handleFlowEvent(parsedEvent));
       }
     });
 
@@ -88,7 +72,6 @@ export class FlowsService implements OnDestroy {
 
   ngOnDestroy(): void {
     this.eventCleanup?.();
-    this.trayEventCleanup?.();
     this.autoSaveSubscription?.unsubscribe();
   }
 
@@ -365,7 +348,9 @@ export class FlowsService implements OnDestroy {
   // ============ Execution ============
 
   /**
-   * Execute all operations in a flow sequentially
+   * Execute all operations in a flow sequentially. The backend runs the flow and
+   * reports progress through flow events, so flows started from the tray or a
+   * schedule show up here as well.
    */
   async executeFlow(flowId: string): Promise<void> {
     console.log(`[FlowsService] executeFlow called: flowId=${flowId}`);
@@ -376,11 +361,6 @@ export class FlowsService implements OnDestroy {
       return;
     }
 
-    console.log(`[FlowsService] executeFlow: ${flow.operations.length} operations`);
-    flow.operations.forEach((op, i) => {
-      console.log(`[FlowsService]   op[${i}]: source=${op.sourceRemote}:${op.sourcePath} target=${op.targetRemote}:${op.targetPath} action=${op.syncConfig.action}`);
-    });
-
     // Check all operations have valid remotes
     const hasInvalidOps = flow.operations.some((op) => !op.sourceRemote || !op.targetRemote);
     if (hasInvalidOps) {
@@ -392,69 +372,13 @@ export class FlowsService implements OnDestroy {
       return;
     }
 
-    this.executingFlowId = flowId;
-    this.executingOperationIndex = 0;
-    this.updateFlow(flowId, { status: 'running' });
-
-    // Clear sync status and set to pending
-    const clearedOps: Operation[] = flow.operations.map((op, idx) => ({
-      ...op,
-      syncStatus: undefined,
-      status: (idx === 0 ? 'running' : 'pending') as Operation['status'],
-    }));
-    this.updateFlowOperations(flowId, clearedOps);
-
     try {
-      // Execute each operation sequentially
-      for (let i = 0; i < flow.operations.length; i++) {
-        // Check if flow was stopped/cancelled
-        if (this.executingFlowId !== flowId) break;
-
-        this.executingOperationIndex = i;
-        const operation = this.getFlow(flowId)?.operations[i];
-        if (!operation) {
-          console.warn(`[FlowsService] executeFlow: operation[${i}] not found after state update`);
-          break;
-        }
-
-        console.log(`[FlowsService] executeFlow: starting operation[${i}] id=${operation.id}`);
-
-        // Mark current operation as running
-        this.updateOperation(flowId, operation.id, { status: 'running' });
-
-        const boardStatus = await this.executeSingleOperation(flowId, operation);
-
-        console.log(`[FlowsService] executeFlow: operation[${i}] result=${boardStatus}`);
-
-        // Check if flow was stopped during execution
-        if (this.executingFlowId !== flowId) break;
-
-        // Use board status from backend as source of truth
-        if (boardStatus === 'failed') {
-          this.updateOperation(flowId, operation.id, { status: 'failed' });
-          this.updateFlow(flowId, { status: 'failed' });
-          break;
-        } else if (boardStatus === 'cancelled') {
-          this.updateOperation(flowId, operation.id, { status: 'cancelled' });
-          this.updateFlow(flowId, { status: 'cancelled' });
-          break;
-        } else {
-          this.updateOperation(flowId, operation.id, { status: 'completed' });
-        }
-      }
-
-      // Mark flow as completed if all operations succeeded
-      const finalFlow = this.getFlow(flowId);
-      if (finalFlow?.status === 'running') {
-        this.updateFlow(flowId, { status: 'completed' });
-      }
+      // Save pending edits first so the backend runs the flow as shown
+      await this.persistFlows();
+      await ExecuteFlow(flowId);
     } catch (err) {
       console.error(`[FlowsService] executeFlow error:`, err);
-      this.updateFlow(flowId, { status: 'failed' });
       this.errorService.handleApiError(err, 'Flow execution failed');
-    } finally {
-      this.executingFlowId = null;
-      this.executingOperationIndex = -1;
     }
   }
 
@@ -462,38 +386,11 @@ export class FlowsService implements OnDestroy {
    * Stop executing a flow
    */
   async stopFlow(flowId: string): Promise<void> {
-    // Immediately resolve any pending board completion wait so executeFlow unblocks
-    if (this.boardCompletionResolve) {
-      this.boardCompletionResolve('cancelled');
-    }
-
-    if (this.tempBoardId) {
-      try {
-        await StopBoardExecution(this.tempBoardId);
-      } catch (err) {
-        this.errorService.handleApiError(err, 'Failed to stop execution');
-      }
-    }
-
-    // Mark flow and current operation as cancelled
-    const flow = this.getFlow(flowId);
-    if (flow) {
-      const ops = flow.operations.map((op, idx) => ({
-        ...op,
-        status:
-          idx === this.executingOperationIndex
-            ? 'cancelled'
-            : idx < this.executingOperationIndex
-              ? op.status
-              : ('idle' as const),
-      }));
-      this.updateFlowOperations(flowId, ops as Operation[]);
-      this.updateFlow(flowId, { status: 'cancelled' });
+    try {
+      await StopFlow(flowId);
+    } catch (err) {
+      this.errorService.handleApiError(err, 'Failed to stop execution');
     }
-
-    this.executingFlowId = null;
-    this.executingOperationIndex = -1;
-    await this.cleanupTempBoard();
   }
 
   // ============ Private Methods ============
@@ -515,143 +412,6 @@ export class FlowsService implements OnDestroy {
     this.autoSaveTrigger$.next();
   }
 
-  private async executeSingleOperation(flowId: string, operation: Operation): Promise<string> {
-    // Create a temporary board for this single operation
-    const board = this.operationToBoard(operation);
-    this.tempBoardId = board.id;
-
-    console.log(`[FlowsService] executeSingleOperation: boardId=${board.id} source=${operation.sourceRemote}:${operation.sourcePath} target=${operation.targetRemote}:${operation.targetPath} action=${operation.syncConfig.action}`);
-
-    try {
-      console.log(`[FlowsService] Adding board...`, JSON.stringify(board, null, 2));
-      await AddBoard(board);
-      console.log(`[FlowsService] Board added successfully`);
-
-      console.log(`[FlowsService] Executing board ${board.id}...`);
-      await ExecuteBoard(board.id);
-      console.log(`[FlowsService] ExecuteBoard returned, waiting for completion...`);
-
-      // Wait for board execution to actually complete (ExecuteBoard returns immediately)
-      const result = await this.waitForBoardCompletion(board.id);
-      console.log(`[FlowsService] Board completed with status: ${result}`);
-      return result;
-    } catch (err) {
-      console.error(`[FlowsService] executeSingleOperation error:`, err);
-      throw err;
-    } finally {
-      // Clean up this operation's temp board immediately
-      await this.cleanupTempBoard();
-    }
-  }
-
-  private waitForBoardCompletion(boardId: string): Promise<string> {
-    return new Promise((resolve) => {
-      let resolved = false;
-      let fallbackInterval: ReturnType<typeof setInterval> | null = null;
-      let consecutiveErrors = 0;
-
-      const doResolve = (status: string) => {
-        if (resolved) return;
-        resolved = true;
-        if (fallbackInterval) clearInterval(fallbackInterval);
-        this.boardCompletionResolve = null;
-        this.boardCompletionBoardId = null;
-        console.log(`[FlowsService] Board ${boardId} completed with status: ${status}`);
-        resolve(status);
-      };
-
-      this.boardCompletionBoardId = boardId;
-      this.boardCompletionResolve = doResolve;
-
-      // Fallback: poll GetBoardExecutionStatus in case the event is missed
-      fallbackInterval = setInterval(async () => {
-        if (resolved) {
-          clearInterval(fallbackInterval!);
-          return;
-        }
-        try {
-          const status = await GetBoardExecutionStatus(boardId);
-          consecutiveErrors = 0;
-          if (status && status.status !== 'running') {
-            doResolve(status.status);
-          }
-        } catch {
-          // Board removed from activeFlows — execution likely completed already
-          consecutiveErrors++;
-          console.warn(`[FlowsService] GetBoardExecutionStatus failed (attempt ${consecutiveErrors})`);
-          if (consecutiveErrors >= 1) {
-            console.warn(`[FlowsService] Board ${boardId} presumed completed after polling failure`);
-            doResolve('completed');
-          }
-        }
-      }, 1000);
-    });
-  }
-
-  private handleBoardCompletionEvent(event: BoardEvent): void {
-    console.log(`[FlowsService] handleBoardCompletionEvent: type=${event.type} boardId=${event.boardId} waiting=${this.boardCompletionBoardId}`);
-    if (!this.boardCompletionBoardId || event.boardId !== this.boardCompletionBoardId) return;
-
-    switch (event.type) {
-      case 'board:execution:completed':
-        console.log(`[FlowsService] Board execution completed event received`);
-        this.boardCompletionResolve?.('completed');
-        break;
-      case 'board:execution:failed':
-        console.log(`[FlowsService] Board execution failed event received`);
-        this.boardCompletionResolve?.('failed');
-        break;
-      case 'board:execution:cancelled':
-        console.log(`[FlowsService] Board execution cancelled event received`);
-        this.boardCompletionResolve?.('cancelled');
-        break;
-    }
-  }
-
-  private operationToBoard(operation: Operation): models.Board {
-    const board = new models.Board();
-    board.id = `board-${generateId()}`;
-    board.name = `__temp_${generateId()}__`;
-
-    // Set valid dates — Wails v3 can't deserialize null into Go's non-pointer time.Time
-    const now = new Date().toISOString();
-    board.created_at = now;
-    board.updated_at = now;
-
-    // Source node
-    const sourceNode = new models.BoardNode();
-    sourceNode.id = generateId();
-    sourceNode.remote_name = operation.sourceRemote;
-    sourceNode.path = operation.sourcePath;
-    sourceNode.label = operation.sourceRemote;
-    sourceNode.x = 100;
-    sourceNode.y = 100;
-
-    // Target node
-    const targetNode = new models.BoardNode();
-    targetNode.id = generateId();
-    targetNode.remote_name = operation.targetRemote;
-    targetNode.path = operation.targetPath;
-    targetNode.label = operation.targetRemote;
-    targetNode.x = 400;
-    targetNode.y = 100;
-
-    board.nodes = [sourceNode, targetNode];
-
-    // Edge
-    const edge = new models.BoardEdge();
-    edge.id = generateId();
-    edge.source_id = sourceNode.id;
-    edge.target_id = targetNode.id;
-    edge.action = operation.syncConfig.action;
-    edge.sync_config = this.syncConfigToProfile(operation.syncConfig);
-
-    board.edges = [edge];
-
-    console.log(`[FlowsService] operationToBoard: board=${board.id} nodes=${board.nodes.length} edges=${board.edges.length} action=${edge.action}`);
-    return board;
-  }
-
   private syncConfigToProfile(sc: SyncConfig): models.Profile {
     const p = new models.Profile();
     // Performance
@@ -762,17 +522,6 @@ export class FlowsService implements OnDestroy {
     return sc;
   }
 
-  private async cleanupTempBoard(): Promise<void> {
-    if (this.tempBoardId) {
-      try {
-        await DeleteBoard(this.tempBoardId);
-      } catch {
-        // Ignore cleanup errors
-      }
-      this.tempBoardId = null;
-    }
-  }
-
   private async cleanupStaleTempBoards(): Promise<void> {
     try {
       const boards = await GetBoards();
@@ -790,8 +539,45 @@ export class FlowsService implements OnDestroy {
     }
   }
 
+  private handleFlowEvent(event: FlowEvent): void {
+    const flow = this.getFlow(event.flowId);
+    if (!flow) return;
+
+    switch (event.type) {
+      case 'flow:execution:started':
+        this.executingFlowId = event.flowId;
+        this.executingOperationIndex = 0;
+        this.updateFlowOperations(
+          event.flowId,
+          flow.operations.map((op) => ({ ...op, syncStatus: undefined, status: 'pending' as const }))
+        );
+        this.updateFlow(event.flowId, { status: 'running' });
+        break;
+      case 'flow:execution:progress': {
+        const index = flow.operations.findIndex((op) => op.id === event.operationId);
+        if (index < 0) return;
+        if (event.status === 'running' && this.executingFlowId === event.flowId) {
+          this.executingOperationIndex = index;
+        }
+        // Skipped operations never ran
+        const status = (event.status === 'skipped' ? 'idle' : event.status) as Operation['status'];
+        this.updateOperation(event.flowId, flow.operations[index].id, { status });
+        break;
+      }
+      case 'flow:execution:completed':
+      case 'flow:execution:failed':
+      case 'flow:execution:cancelled':
+        this.updateFlow(event.flowId, { status: event.status as FlowStatus });
+        if (this.executingFlowId === event.flowId) {
+          this.executingFlowId = null;
+          this.executingOperationIndex = -1;
+        }
+        break;
+    }
+  }
+
   private handleSyncLogEvent(_event: SyncEvent): void {
-    // Operation status is managed by executeFlow via board completion events.
+    // Operation status is managed by the backend through flow events.
     // No need to duplicate status updates here — doing so would prematurely
     // change the operation status and cause the UI to lose the syncStatus display.
   }
@@ -823,7 +609,7 @@ export class FlowsService implements OnDestroy {
       renames: event.renames || 0,
       timestamp: event.timestamp || new Date().toISOString(),
       elapsed_time: event.elapsed_time || '0s',
-      action: (['pull', 'push', 'bi', 'bi-resync'].includes(event.action || '') ? event.action : 'push') as SyncStatus['action'],
+      action: (['pull', 'push', 'bi', 'bi-resync', 'copy', 'move', 'check'].includes(event.action || '') ? event.action : 'push') as SyncStatus['action'],
       transfers: event.transfers,
     };
 
@@ -857,6 +643,7 @@ export class FlowsService implements OnDestroy {
       isCollapsed: bf.is_collapsed,
       scheduleEnabled: bf.schedule_enabled,
       cronExpr: bf.cron_expr || undefined,
+      continueOnFailure: bf.continue_on_failure,
       status: 'idle',
       operations: (bf.operations || []).map((bo) => ({
         id: bo.id,
@@ -878,6 +665,7 @@ export class FlowsService implements OnDestroy {
     bf.is_collapsed = f.isCollapsed;
     bf.schedule_enabled = f.scheduleEnabled;
     bf.cron_expr = f.cronExpr || '';
+    bf.continue_on_failure = f.continueOnFailure || false;
     bf.sort_order = sortOrder;
     bf.operations = f.operations.map((op, opIdx) => {
       const bo = new models.Operation();
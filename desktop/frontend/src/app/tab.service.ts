@@ -318,6 +318,24 @@ export class TabService {
                     data: this.limitOutput([...tab.data, "Sync cancelled"]),
                 });
                 break;
+            case "sync:paused":
+                this.updateTab(event.tabId, {
+                    data: this.limitOutput([...tab.data, "Sync paused"]),
+                });
+                break;
+            case "sync:resumed":
+                this.updateTab(event.tabId, {
+                    data: this.limitOutput([...tab.data, "Sync resumed"]),
+                });
+                break;
+            case "sync:queued":
+                this.updateTab(event.tabId, {
+                    data: this.limitOutput([
+                        ...tab.data,
+                        "Sync queued, waiting for other tasks to finish",
+                    ]),
+                });
+                break;
         }
     }
 
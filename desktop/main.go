@@ -1,13 +1,16 @@
 package main
 
 import (
+	"context"
 	be "desktop/backend"
 	"desktop/backend/utils"
+	"desktop/backend/rclone"
 	"desktop/backend/services"
 	"embed"
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
 	"github.com/wailsapp/wails/v3/pkg/events"
@@ -29,6 +32,8 @@ func main() {
 	tabService := services.NewTabService(nil)
 	operationService := services.NewOperationService(nil)
 	historyService := services.NewHistoryService(nil)
+	recoveryService := services.NewRecoveryService(nil)
+	queueService := services.NewQueueService(nil)
 	schedulerService := services.NewSchedulerService(nil)
 	notificationService := services.NewNotificationService(nil)
 	cryptService := services.NewCryptService(nil)
@@ -36,6 +41,7 @@ func main() {
 	exportService := services.NewExportService(nil)
 	importService := services.NewImportService(nil)
 	flowService := services.NewFlowService(nil)
+	bisyncStateService := services.NewBisyncStateService(nil)
 	trayService := services.NewTrayService(appIcon)
 
 	// Create application with all services registered
@@ -54,6 +60,9 @@ func main() {
 			application.NewService(tabService),
 			application.NewService(operationService),
 			application.NewService(historyService),
+			// Before the scheduler so runs it starts aren't taken for leftovers
+			application.NewService(recoveryService),
+			application.NewService(queueService),
 			application.NewService(schedulerService),
 			application.NewService(notificationService),
 			application.NewService(cryptService),
@@ -61,6 +70,7 @@ func main() {
 			application.NewService(exportService),
 			application.NewService(importService),
 			application.NewService(flowService),
+			application.NewService(bisyncStateService),
 		},
 	})
 
@@ -73,6 +83,8 @@ func main() {
 	tabService.SetApp(app)
 	operationService.SetApp(app)
 	historyService.SetApp(app)
+	recoveryService.SetApp(app)
+	queueService.SetApp(app)
 	schedulerService.SetApp(app)
 	notificationService.SetApp(app)
 	cryptService.SetApp(app)
@@ -80,6 +92,7 @@ func main() {
 	exportService.SetApp(app)
 	importService.SetApp(app)
 	flowService.SetApp(app)
+	bisyncStateService.SetApp(app)
 
 	// Load env config and wire to SyncService
 	envConfig := utils.LoadEnvConfigFromEnvStr(be.GetEmbeddedEnvConfigStr())
@@ -89,18 +102,43 @@ func main() {
 		log.Println("[main] Debug mode enabled via NS_DRIVE_DEBUG env var")
 	}
 	syncService.SetEnvConfig(envConfig)
+	bisyncStateService.SetEnvConfig(envConfig)
 
 	// Wire up service dependencies
 	schedulerService.SetSyncService(syncService)
+	schedulerService.SetConfigService(configService)
+	schedulerService.SetNotificationService(notificationService)
+	schedulerService.SetBoardService(boardService)
 	boardService.SetSyncService(syncService)
 	boardService.SetNotificationService(notificationService)
+	boardService.SetSchedulerService(schedulerService)
+	flowService.SetSyncService(syncService)
+	flowService.SetNotificationService(notificationService)
+	flowService.SetSchedulerService(schedulerService)
+	schedulerService.SetFlowService(flowService)
 	syncService.SetLogService(logService)
 	syncService.SetNotificationService(notificationService)
+	syncService.SetHistoryService(historyService)
+	operationService.SetHistoryService(historyService)
+	cryptService.SetConfigService(configService)
+	cryptService.SetOperationService(operationService)
+	recoveryService.SetSyncService(syncService)
+	recoveryService.SetOperationService(operationService)
+	recoveryService.SetBoardService(boardService)
+	recoveryService.SetFlowService(flowService)
+	recoveryService.SetNotificationService(notificationService)
+	appService.SetHistoryRecorder(func(statsCtx context.Context, profileName, action, status string, startTime time.Time, taskErr error) {
+		entry := services.NewHistoryEntry(statsCtx, profileName, action, status, startTime, taskErr)
+		if err := historyService.AddEntry(context.Background(), entry); err != nil {
+			log.Printf("Failed to record history for %s: %v", profileName, err)
+		}
+	})
 
 	// Set singleton instances for cross-service access
 	services.SetBoardServiceInstance(boardService)
 	services.SetFlowServiceInstance(flowService)
 	services.SetTrayServiceInstance(trayService)
+	rclone.SetBisyncStateStore(bisyncStateService)
 
 	// Wire up tray service dependencies
 	trayService.SetApp(app)
@@ -172,6 +210,9 @@ func main() {
 		be.HideFromDock()
 	}
 
+	// On quit, wait for running tasks if configured, then keep the rest for resuming
+	app.OnShutdown(recoveryService.ShutdownTasks)
+
 	// Run the application
 	err = app.Run()
 	if err != nil {